@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/domains/users/domain"
+	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/domains/users/infrastructure/persistence"
+	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/platform/config"
+	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/platform/database"
+	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/platform/logger"
+	"github.com/jackc/pgx/v5"
+)
+
+const promoteAdminUsage = `Usage: api promote-admin <email>
+
+Grants the admin role to the active user with the given email address.
+Use it once to bootstrap the first admin, who can then change roles through the API.`
+
+/*
+runPromoteAdmin runs the promote-admin subcommand, which makes a registered user
+an admin. No admin exists on a fresh database and only admins may change roles,
+so this is how the first one is created. The change is audited with no actor.
+Returns the process exit code.
+*/
+func runPromoteAdmin(args []string) int {
+	log := logger.New("info")
+
+	if len(args) != 1 {
+		fmt.Fprintln(os.Stderr, promoteAdminUsage)
+		return 2
+	}
+	email, err := domain.NewEmail(args[0])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n\n%s\n", err, promoteAdminUsage)
+		return 2
+	}
+
+	// Only the database settings are needed, like for the migrate subcommand
+	cfg, err := config.LoadDatabase()
+	if err != nil {
+		log.Error("Failed to load configuration", "error", err.Error())
+		return 1
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	pool, err := database.NewPool(ctx, cfg)
+	if err != nil {
+		log.Error("Failed to connect to database", "error", err.Error())
+		return 1
+	}
+	defer pool.Close()
+
+	txManager := database.NewTxManager(pool,
+		database.WithIsolationLevel(pgx.TxIsoLevel(cfg.Database.TxIsolation)),
+		database.WithRetry(int(cfg.Database.TxMaxRetries), cfg.Database.TxRetryBaseDelay, cfg.Database.TxRetryMaxDelay),
+	)
+	users := persistence.NewUserRepository(pool, txManager)
+
+	var alreadyAdmin bool
+	err = txManager.Do(ctx, func(ctx context.Context) error {
+		user, err := users.FindByEmail(ctx, email)
+		if err != nil {
+			return err
+		}
+
+		alreadyAdmin = user.Role == domain.RoleAdmin
+		if alreadyAdmin {
+			return nil
+		}
+
+		if err := user.ChangeRole(domain.RoleAdmin); err != nil {
+			return err
+		}
+		return users.Update(ctx, user)
+	})
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			log.Error("No active user with this email; register the account first", "email", email.Value())
+			return 1
+		}
+		log.Error("Failed to promote user", "email", email.Value(), "error", err.Error())
+		return 1
+	}
+
+	if alreadyAdmin {
+		log.Info("User is already an admin", "email", email.Value())
+		return 0
+	}
+	log.Info("User promoted to admin", "email", email.Value())
+	return 0
+}
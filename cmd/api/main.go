@@ -8,18 +8,42 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/dzikrisyairozi/go-ddd-clean-starter/infrastructure/database/migrations"
 	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/domains/users/application"
+	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/domains/users/domain"
 	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/domains/users/handler"
 	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/domains/users/infrastructure/persistence"
+	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/platform/auth"
+	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/platform/background"
 	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/platform/config"
 	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/platform/database"
+	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/platform/database/migrate"
 	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/platform/docs"
+	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/platform/encryption"
+	apperrors "github.com/dzikrisyairozi/go-ddd-clean-starter/internal/platform/errors"
+	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/platform/events"
 	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/platform/logger"
+	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/platform/mailer"
 	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/platform/middleware"
+	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/platform/outbox"
+	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/platform/password"
+	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/platform/problem"
+	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/platform/ratelimit"
 	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5"
 )
 
 func main() {
+	// Subcommands manage the schema or the users instead of serving the API
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "migrate":
+			os.Exit(runMigrate(os.Args[2:]))
+		case "promote-admin":
+			os.Exit(runPromoteAdmin(os.Args[2:]))
+		}
+	}
+
 	// Initialize logger
 	log := logger.New("info")
 	log.Info("Starting Go DDD Clean Starter API...")
@@ -46,31 +70,124 @@ func main() {
 	if err != nil {
 		log.Fatal("Failed to connect to database", "error", err.Error())
 	}
+	// Closed when main returns, which is after the graceful shutdown has finished
 	defer pool.Close()
 
 	log.Info("Database connection established successfully")
 
+	// Apply pending migrations; replicas starting together wait for each other's lock
+	if cfg.Database.AutoMigrate {
+		migrator, err := migrate.New(pool, migrations.FS, log)
+		if err != nil {
+			log.Fatal("Failed to load migrations", "error", err.Error())
+		}
+		if err := migrator.Up(ctx); err != nil {
+			log.Fatal("Failed to migrate database", "error", err.Error())
+		}
+	}
+
 	// Initialize dependencies (Dependency Injection)
 	// Infrastructure layer
-	userRepo := persistence.NewUserRepository(pool)
+	txManager := database.NewTxManager(pool,
+		database.WithIsolationLevel(pgx.TxIsoLevel(cfg.Database.TxIsolation)),
+		database.WithRetry(int(cfg.Database.TxMaxRetries), cfg.Database.TxRetryBaseDelay, cfg.Database.TxRetryMaxDelay),
+	)
+	userRepo := persistence.NewUserRepository(pool, txManager)
+	refreshTokenRepo := persistence.NewRefreshTokenRepository(pool)
+	resetTokenRepo := persistence.NewPasswordResetTokenRepository(pool)
+	verificationTokenRepo := persistence.NewEmailVerificationTokenRepository(pool)
+	totpFactorRepo := persistence.NewTOTPFactorRepository(pool)
+	recoveryCodeRepo := persistence.NewRecoveryCodeRepository(pool, txManager)
+	apiKeyRepo := persistence.NewAPIKeyRepository(pool)
+	auditLogRepo := persistence.NewAuditLogRepository(pool)
+
+	tokenManager, err := auth.NewTokenManager(cfg)
+	if err != nil {
+		log.Fatal("Failed to initialize token manager", "error", err.Error())
+	}
+
+	mail, err := mailer.New(cfg, log)
+	if err != nil {
+		log.Fatal("Failed to initialize mailer", "error", err.Error())
+	}
+
+	secretCipher, err := encryption.NewCipher(cfg.MFA.EncryptionKey)
+	if err != nil {
+		log.Fatal("Failed to initialize secret cipher", "error", err.Error())
+	}
+
+	passwordPolicy, err := password.NewPolicy(cfg)
+	if err != nil {
+		log.Fatal("Failed to initialize password policy", "error", err.Error())
+	}
+
+	passwordHasher, err := password.NewHasher(cfg)
+	if err != nil {
+		log.Fatal("Failed to initialize password hasher", "error", err.Error())
+	}
+
+	// Domain events are stored in the outbox with the changes that raised them,
+	// and delivered to the configured sinks by the relay
+	outboxSinks, err := outbox.NewSinks(cfg, log)
+	if err != nil {
+		log.Fatal("Failed to initialize outbox sinks", "error", err.Error())
+	}
+	outboxRelay := outbox.NewRelay(pool, outbox.RelayConfig{
+		PollInterval:   cfg.Outbox.PollInterval,
+		BatchSize:      int(cfg.Outbox.BatchSize),
+		LeaseDuration:  cfg.Outbox.LeaseDuration,
+		MaxAttempts:    int(cfg.Outbox.MaxAttempts),
+		RetryBaseDelay: cfg.Outbox.RetryBaseDelay,
+		RetryMaxDelay:  cfg.Outbox.RetryMaxDelay,
+	}, log, outboxSinks...)
+	if cfg.Outbox.RelayEnabled {
+		outboxRelay.Start()
+	}
+
+	// Domain events are also dispatched in process once their changes are committed
+	eventDispatcher := events.NewDispatcher(log)
+	eventDispatcher.SubscribeAll(func(ctx context.Context, event events.Event) error {
+		log.Debug("Domain event published", "event", event.EventName())
+		return nil
+	})
 
 	// Application layer
-	userService := application.NewUserService(userRepo)
+	emailVerifier := application.NewEmailVerifier(userRepo, verificationTokenRepo, mail, cfg.Auth.EmailVerificationTTL, cfg.Auth.EmailVerificationURL)
+	eventDispatcher.Subscribe(domain.EventUserRegistered, emailVerifier.HandleUserEvent)
+	eventDispatcher.Subscribe(domain.EventUserEmailChanged, emailVerifier.HandleUserEvent)
+	totpAuthenticator := application.NewTOTPAuthenticator(totpFactorRepo, recoveryCodeRepo, secretCipher, cfg.MFA.Issuer)
+	loginGuard := application.NewLoginGuard(
+		userRepo,
+		ratelimit.NewFailureLimiter(int(cfg.Auth.LoginIPMaxFailures), cfg.Auth.LoginIPWindow),
+		domain.LockoutPolicy{
+			Threshold:    int(cfg.Auth.LockoutThreshold),
+			BaseDuration: cfg.Auth.LockoutDuration,
+			MaxDuration:  cfg.Auth.LockoutMaxDuration,
+		},
+	)
+	userService := application.NewUserService(userRepo, auditLogRepo, txManager, eventDispatcher, loginGuard, passwordPolicy, passwordHasher)
+	backgroundRunner := background.NewRunner(log)
+	resetLimiters := application.ResetLimiters{
+		PerClient: ratelimit.NewFailureLimiter(int(cfg.Auth.PasswordResetIPMaxRequests), cfg.Auth.PasswordResetWindow),
+		PerEmail:  ratelimit.NewFailureLimiter(int(cfg.Auth.PasswordResetEmailMaxRequests), cfg.Auth.PasswordResetWindow),
+	}
+	authService := application.NewAuthService(userRepo, refreshTokenRepo, resetTokenRepo, txManager, eventDispatcher, emailVerifier, totpAuthenticator, loginGuard, passwordPolicy, passwordHasher, tokenManager, mail, backgroundRunner, resetLimiters, application.AuthSettings{
+		RefreshTokenTTL:          cfg.Auth.RefreshTokenTTL,
+		PasswordResetTTL:         cfg.Auth.PasswordResetTTL,
+		PasswordResetURL:         cfg.Auth.PasswordResetURL,
+		RequireEmailVerification: cfg.Auth.RequireEmailVerification,
+	})
+	apiKeyService := application.NewAPIKeyService(apiKeyRepo, userRepo, backgroundRunner)
+
+	// Register domain errors for the shared error renderer
+	errorRegistry := apperrors.NewRegistry()
+	handler.RegisterErrors(errorRegistry)
 
 	// Create Fiber app
 	app := fiber.New(fiber.Config{
 		AppName:      "Go DDD Clean Starter API",
 		ServerHeader: "Fiber",
-		ErrorHandler: func(c *fiber.Ctx, err error) error {
-			code := fiber.StatusInternalServerError
-			if e, ok := err.(*fiber.Error); ok {
-				code = e.Code
-			}
-			return c.Status(code).JSON(fiber.Map{
-				"error":   "internal_error",
-				"message": err.Error(),
-			})
-		},
+		ErrorHandler: problem.NewRenderer(cfg.App.ProblemTypeBaseURI, errorRegistry, log).Render,
 	})
 
 	// Register middleware
@@ -90,10 +207,16 @@ func main() {
 	})
 
 	// Register domain routes
-	handler.RegisterRoutes(app, userService, log)
+	authenticate := middleware.Authenticate(tokenManager, apiKeyService)
+	handler.RegisterRoutes(app, userService, authenticate, log)
+	handler.RegisterAuthRoutes(app, authService, authenticate, log)
+	handler.RegisterAPIKeyRoutes(app, apiKeyService, authenticate, log)
 
-	// Graceful shutdown
+	// Graceful shutdown; closing done lets main return and close the pool
+	done := make(chan struct{})
 	go func() {
+		defer close(done)
+
 		sigChan := make(chan os.Signal, 1)
 		signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
 		<-sigChan
@@ -105,9 +228,15 @@ func main() {
 			log.Error("Server forced to shutdown", "error", err.Error())
 		}
 
-		// Close database connection
-		pool.Close()
-		log.Info("Server stopped")
+		// Let background work and the outbox relay finish before the pool is closed
+		stopCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		if err := backgroundRunner.Wait(stopCtx); err != nil {
+			log.Error("Background tasks did not finish", "error", err.Error())
+		}
+		if err := outboxRelay.Stop(stopCtx); err != nil {
+			log.Error("Outbox relay forced to stop", "error", err.Error())
+		}
+		cancel()
 	}()
 
 	// Start server
@@ -117,4 +246,8 @@ func main() {
 	if err := app.Listen(addr); err != nil {
 		log.Fatal("Failed to start server", "error", err.Error())
 	}
+
+	// Listen returns as soon as shutdown begins; wait until the relay has stopped
+	<-done
+	log.Info("Server stopped")
 }
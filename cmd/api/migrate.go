@@ -0,0 +1,149 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"text/tabwriter"
+
+	"github.com/dzikrisyairozi/go-ddd-clean-starter/infrastructure/database/migrations"
+	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/platform/config"
+	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/platform/database"
+	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/platform/database/migrate"
+	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/platform/logger"
+)
+
+const migrateUsage = `Usage: api migrate <command>
+
+Commands:
+  up          Apply all pending migrations
+  down N      Revert the last N migrations
+  status      Show the schema version and pending migrations
+  goto V      Migrate up or down to version V (0 reverts everything)
+  force V     Record version V as applied without running migrations`
+
+/*
+runMigrate runs the migrate subcommand against the database of the configuration.
+The migrations are embedded in the binary, so it needs nothing but database access.
+Returns the process exit code.
+*/
+func runMigrate(args []string) int {
+	log := logger.New("info")
+
+	command, err := parseMigrateCommand(args)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n\n%s\n", err, migrateUsage)
+		return 2
+	}
+
+	// Only the database settings are needed, so a migration job runs without the app's secrets
+	cfg, err := config.LoadDatabase()
+	if err != nil {
+		log.Error("Failed to load configuration", "error", err.Error())
+		return 1
+	}
+
+	// Stop waiting for the migration lock on Ctrl+C
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	pool, err := database.NewPool(ctx, cfg)
+	if err != nil {
+		log.Error("Failed to connect to database", "error", err.Error())
+		return 1
+	}
+	defer pool.Close()
+
+	migrator, err := migrate.New(pool, migrations.FS, log)
+	if err != nil {
+		log.Error("Failed to load migrations", "error", err.Error())
+		return 1
+	}
+
+	if err := command(ctx, migrator); err != nil {
+		log.Error("Migration failed", "command", args[0], "error", err.Error())
+		return 1
+	}
+	return 0
+}
+
+/*
+parseMigrateCommand turns the arguments of the migrate subcommand into the
+migrator call they ask for.
+Returns an error for an unknown command or a missing or malformed argument.
+*/
+func parseMigrateCommand(args []string) (func(context.Context, *migrate.Migrator) error, error) {
+	if len(args) == 0 {
+		return nil, fmt.Errorf("missing command")
+	}
+
+	switch command, arg := args[0], args[1:]; command {
+	case "up", "status":
+		if len(arg) != 0 {
+			return nil, fmt.Errorf("%s takes no arguments", command)
+		}
+		if command == "status" {
+			return printStatus, nil
+		}
+		return func(ctx context.Context, m *migrate.Migrator) error {
+			return m.Up(ctx)
+		}, nil
+	case "down":
+		if len(arg) != 1 {
+			return nil, fmt.Errorf("down needs the number of migrations to revert")
+		}
+		n, err := strconv.Atoi(arg[0])
+		if err != nil || n < 1 {
+			return nil, fmt.Errorf("invalid number of migrations: %s", arg[0])
+		}
+		return func(ctx context.Context, m *migrate.Migrator) error {
+			return m.Down(ctx, n)
+		}, nil
+	case "goto", "force":
+		if len(arg) != 1 {
+			return nil, fmt.Errorf("%s needs a version", command)
+		}
+		version, err := strconv.ParseInt(arg[0], 10, 64)
+		if err != nil || version < 0 {
+			return nil, fmt.Errorf("invalid version: %s", arg[0])
+		}
+		if command == "force" {
+			return func(ctx context.Context, m *migrate.Migrator) error {
+				return m.Force(ctx, version)
+			}, nil
+		}
+		return func(ctx context.Context, m *migrate.Migrator) error {
+			return m.Goto(ctx, version)
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown command: %s", command)
+	}
+}
+
+// printStatus writes the schema version and every migration's state to stdout
+func printStatus(ctx context.Context, migrator *migrate.Migrator) error {
+	status, err := migrator.Status(ctx)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Version: %d", status.Version)
+	if status.Dirty {
+		fmt.Print(" (dirty)")
+	}
+	fmt.Println()
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tSTATE")
+	for _, m := range status.Migrations {
+		state := "pending"
+		if m.Applied {
+			state = "applied"
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\n", m.Version, m.Name, state)
+	}
+	return w.Flush()
+}
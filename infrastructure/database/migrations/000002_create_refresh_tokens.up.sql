@@ -0,0 +1,26 @@
+-- Create refresh_tokens table for rotating refresh tokens
+CREATE TABLE IF NOT EXISTS refresh_tokens (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    family_id UUID NOT NULL,
+    token_hash VARCHAR(64) UNIQUE NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    rotated_at TIMESTAMP,
+    revoked_at TIMESTAMP
+);
+
+-- Create indexes for better query performance
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_user_id ON refresh_tokens(user_id);
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_family_id ON refresh_tokens(family_id);
+
+-- Add comment to table
+COMMENT ON TABLE refresh_tokens IS 'Stores hashed refresh tokens grouped into rotation families';
+COMMENT ON COLUMN refresh_tokens.id IS 'Unique identifier for the refresh token (UUID v4)';
+COMMENT ON COLUMN refresh_tokens.user_id IS 'User the token was issued to';
+COMMENT ON COLUMN refresh_tokens.family_id IS 'Rotation family - all tokens descending from one login';
+COMMENT ON COLUMN refresh_tokens.token_hash IS 'SHA-256 hash of the opaque refresh token';
+COMMENT ON COLUMN refresh_tokens.expires_at IS 'Timestamp after which the token can no longer be used';
+COMMENT ON COLUMN refresh_tokens.created_at IS 'Timestamp when the token was issued';
+COMMENT ON COLUMN refresh_tokens.rotated_at IS 'Timestamp when the token was exchanged for a new one';
+COMMENT ON COLUMN refresh_tokens.revoked_at IS 'Timestamp when the token was revoked (logout or reuse detection)';
@@ -0,0 +1,6 @@
+-- Drop index first
+DROP INDEX IF EXISTS idx_users_role;
+
+-- Drop role column and its constraint
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_role_check;
+ALTER TABLE users DROP COLUMN IF EXISTS role;
@@ -0,0 +1,11 @@
+-- Add role column to users (every existing user becomes a member)
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS role VARCHAR(20) NOT NULL DEFAULT 'member';
+
+ALTER TABLE users
+    ADD CONSTRAINT users_role_check CHECK (role IN ('admin', 'support', 'member'));
+
+-- Create index for role-based lookups
+CREATE INDEX IF NOT EXISTS idx_users_role ON users(role);
+
+COMMENT ON COLUMN users.role IS 'Access control role: admin, support or member';
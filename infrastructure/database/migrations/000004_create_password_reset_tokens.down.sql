@@ -0,0 +1,5 @@
+-- Drop indexes first
+DROP INDEX IF EXISTS idx_password_reset_tokens_user_id;
+
+-- Drop password_reset_tokens table
+DROP TABLE IF EXISTS password_reset_tokens;
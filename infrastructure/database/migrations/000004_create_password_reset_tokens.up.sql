@@ -0,0 +1,21 @@
+-- Create password_reset_tokens table for single-use password reset links
+CREATE TABLE IF NOT EXISTS password_reset_tokens (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    token_hash VARCHAR(64) UNIQUE NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    used_at TIMESTAMP
+);
+
+-- Create indexes for better query performance
+CREATE INDEX IF NOT EXISTS idx_password_reset_tokens_user_id ON password_reset_tokens(user_id);
+
+-- Add comment to table
+COMMENT ON TABLE password_reset_tokens IS 'Stores hashed single-use password reset tokens';
+COMMENT ON COLUMN password_reset_tokens.id IS 'Unique identifier for the reset token (UUID v4)';
+COMMENT ON COLUMN password_reset_tokens.user_id IS 'User the reset was requested for';
+COMMENT ON COLUMN password_reset_tokens.token_hash IS 'SHA-256 hash of the emailed reset token';
+COMMENT ON COLUMN password_reset_tokens.expires_at IS 'Timestamp after which the token can no longer be used';
+COMMENT ON COLUMN password_reset_tokens.created_at IS 'Timestamp when the reset was requested';
+COMMENT ON COLUMN password_reset_tokens.used_at IS 'Timestamp when the token was used or invalidated';
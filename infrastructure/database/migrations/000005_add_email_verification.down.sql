@@ -0,0 +1,9 @@
+-- Drop indexes first
+DROP INDEX IF EXISTS idx_email_verification_tokens_user_id;
+
+-- Drop email_verification_tokens table
+DROP TABLE IF EXISTS email_verification_tokens;
+
+-- Remove email verification timestamp from users
+ALTER TABLE users
+    DROP COLUMN IF EXISTS email_verified_at;
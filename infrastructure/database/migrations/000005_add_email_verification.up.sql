@@ -0,0 +1,31 @@
+-- Add email verification timestamp to users
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS email_verified_at TIMESTAMP;
+
+-- Accounts created before verification existed are treated as verified
+UPDATE users SET email_verified_at = created_at WHERE email_verified_at IS NULL;
+
+-- Add comment to column
+COMMENT ON COLUMN users.email_verified_at IS 'Timestamp when the email address was verified, NULL if unverified';
+
+-- Create email_verification_tokens table for single-use verification links
+CREATE TABLE IF NOT EXISTS email_verification_tokens (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    token_hash VARCHAR(64) UNIQUE NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    used_at TIMESTAMP
+);
+
+-- Create indexes for better query performance
+CREATE INDEX IF NOT EXISTS idx_email_verification_tokens_user_id ON email_verification_tokens(user_id);
+
+-- Add comment to table
+COMMENT ON TABLE email_verification_tokens IS 'Stores hashed single-use email verification tokens';
+COMMENT ON COLUMN email_verification_tokens.id IS 'Unique identifier for the verification token (UUID v4)';
+COMMENT ON COLUMN email_verification_tokens.user_id IS 'User whose email address is being verified';
+COMMENT ON COLUMN email_verification_tokens.token_hash IS 'SHA-256 hash of the emailed verification token';
+COMMENT ON COLUMN email_verification_tokens.expires_at IS 'Timestamp after which the token can no longer be used';
+COMMENT ON COLUMN email_verification_tokens.created_at IS 'Timestamp when the token was issued';
+COMMENT ON COLUMN email_verification_tokens.used_at IS 'Timestamp when the token was used or invalidated';
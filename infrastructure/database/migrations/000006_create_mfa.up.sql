@@ -0,0 +1,38 @@
+-- Create user_totp_factors table for TOTP two-factor authentication
+CREATE TABLE IF NOT EXISTS user_totp_factors (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    secret_ciphertext TEXT NOT NULL,
+    confirmed_at TIMESTAMP,
+    last_used_step BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Add comment to table
+COMMENT ON TABLE user_totp_factors IS 'Stores encrypted TOTP secrets for two-factor authentication';
+COMMENT ON COLUMN user_totp_factors.user_id IS 'User the authenticator belongs to';
+COMMENT ON COLUMN user_totp_factors.secret_ciphertext IS 'AES-GCM encrypted TOTP secret';
+COMMENT ON COLUMN user_totp_factors.confirmed_at IS 'Timestamp when enrolment was confirmed with a valid code, NULL while pending';
+COMMENT ON COLUMN user_totp_factors.last_used_step IS 'Time step of the last accepted code, used to reject replayed codes';
+COMMENT ON COLUMN user_totp_factors.created_at IS 'Timestamp when enrolment was started';
+COMMENT ON COLUMN user_totp_factors.updated_at IS 'Timestamp when the factor was last updated';
+
+-- Create mfa_recovery_codes table for single-use recovery codes
+CREATE TABLE IF NOT EXISTS mfa_recovery_codes (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    code_hash VARCHAR(64) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    used_at TIMESTAMP
+);
+
+-- Create indexes for better query performance
+CREATE INDEX IF NOT EXISTS idx_mfa_recovery_codes_user_id ON mfa_recovery_codes(user_id);
+
+-- Add comment to table
+COMMENT ON TABLE mfa_recovery_codes IS 'Stores hashed single-use MFA recovery codes';
+COMMENT ON COLUMN mfa_recovery_codes.id IS 'Unique identifier for the recovery code (UUID v4)';
+COMMENT ON COLUMN mfa_recovery_codes.user_id IS 'User the recovery code belongs to';
+COMMENT ON COLUMN mfa_recovery_codes.code_hash IS 'SHA-256 hash of the normalized recovery code';
+COMMENT ON COLUMN mfa_recovery_codes.created_at IS 'Timestamp when the code was generated';
+COMMENT ON COLUMN mfa_recovery_codes.used_at IS 'Timestamp when the code was used';
@@ -0,0 +1,5 @@
+-- Remove failed login tracking and lockout state from users
+ALTER TABLE users
+    DROP COLUMN IF EXISTS locked_until,
+    DROP COLUMN IF EXISTS lockout_count,
+    DROP COLUMN IF EXISTS failed_login_attempts;
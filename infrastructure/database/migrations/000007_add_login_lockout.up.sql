@@ -0,0 +1,9 @@
+-- Add failed login tracking and lockout state to users
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS failed_login_attempts INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS lockout_count INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS locked_until TIMESTAMP;
+
+COMMENT ON COLUMN users.failed_login_attempts IS 'Consecutive failed password attempts since the last success or lockout';
+COMMENT ON COLUMN users.lockout_count IS 'Consecutive lockouts, used to escalate the lockout duration';
+COMMENT ON COLUMN users.locked_until IS 'Timestamp until which password login is blocked, NULL if not locked';
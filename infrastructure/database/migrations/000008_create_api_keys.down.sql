@@ -0,0 +1,5 @@
+-- Drop indexes first
+DROP INDEX IF EXISTS idx_api_keys_user_id;
+
+-- Drop API keys table
+DROP TABLE IF EXISTS api_keys;
@@ -0,0 +1,29 @@
+-- Create api_keys table for personal API keys used by scripts and services
+CREATE TABLE IF NOT EXISTS api_keys (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    prefix VARCHAR(16) NOT NULL,
+    key_hash VARCHAR(64) NOT NULL UNIQUE,
+    scopes TEXT[] NOT NULL,
+    expires_at TIMESTAMP,
+    last_used_at TIMESTAMP,
+    revoked_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Create indexes for better query performance
+CREATE INDEX IF NOT EXISTS idx_api_keys_user_id ON api_keys(user_id);
+
+-- Add comment to table
+COMMENT ON TABLE api_keys IS 'Stores hashed personal API keys for machine-to-machine access';
+COMMENT ON COLUMN api_keys.id IS 'Unique identifier for the API key (UUID v4)';
+COMMENT ON COLUMN api_keys.user_id IS 'User the API key acts as';
+COMMENT ON COLUMN api_keys.name IS 'Name given by the user to tell keys apart';
+COMMENT ON COLUMN api_keys.prefix IS 'Non-secret start of the key, shown to identify it';
+COMMENT ON COLUMN api_keys.key_hash IS 'SHA-256 hash of the key';
+COMMENT ON COLUMN api_keys.scopes IS 'Permissions the key is limited to';
+COMMENT ON COLUMN api_keys.expires_at IS 'Timestamp after which the key is rejected, NULL if it never expires';
+COMMENT ON COLUMN api_keys.last_used_at IS 'Timestamp when the key last authenticated a request';
+COMMENT ON COLUMN api_keys.revoked_at IS 'Timestamp when the key was revoked';
+COMMENT ON COLUMN api_keys.created_at IS 'Timestamp when the key was created';
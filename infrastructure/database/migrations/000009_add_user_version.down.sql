@@ -0,0 +1,3 @@
+-- Remove the row version from users
+ALTER TABLE users
+    DROP COLUMN IF EXISTS version;
@@ -0,0 +1,5 @@
+-- Add a row version to users for optimistic concurrency control
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
+
+COMMENT ON COLUMN users.version IS 'Incremented on every update, used for optimistic concurrency control';
@@ -0,0 +1,6 @@
+-- Drop indexes first
+DROP INDEX IF EXISTS idx_outbox_events_aggregate;
+DROP INDEX IF EXISTS idx_outbox_events_pending;
+
+-- Drop outbox events table
+DROP TABLE IF EXISTS outbox_events;
@@ -0,0 +1,34 @@
+-- Create outbox_events table for the transactional outbox
+-- Events are inserted in the same transaction as the change that raised them
+-- and delivered to external sinks by the outbox relay
+CREATE TABLE IF NOT EXISTS outbox_events (
+    id UUID PRIMARY KEY,
+    aggregate_type VARCHAR(50) NOT NULL,
+    aggregate_id UUID NOT NULL,
+    event_type VARCHAR(100) NOT NULL,
+    payload JSONB NOT NULL,
+    occurred_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    last_error TEXT,
+    published_at TIMESTAMP
+);
+
+-- Create indexes for better query performance
+CREATE INDEX IF NOT EXISTS idx_outbox_events_pending ON outbox_events(next_attempt_at, created_at) WHERE published_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_outbox_events_aggregate ON outbox_events(aggregate_type, aggregate_id);
+
+-- Add comment to table
+COMMENT ON TABLE outbox_events IS 'Domain events waiting to be delivered by the outbox relay';
+COMMENT ON COLUMN outbox_events.id IS 'Unique event ID, also sent to sinks so consumers can drop duplicates';
+COMMENT ON COLUMN outbox_events.aggregate_type IS 'Kind of aggregate that raised the event (e.g. user)';
+COMMENT ON COLUMN outbox_events.aggregate_id IS 'ID of the aggregate that raised the event';
+COMMENT ON COLUMN outbox_events.event_type IS 'Event name (e.g. user.registered)';
+COMMENT ON COLUMN outbox_events.payload IS 'Event serialized as JSON';
+COMMENT ON COLUMN outbox_events.occurred_at IS 'Timestamp when the event happened';
+COMMENT ON COLUMN outbox_events.created_at IS 'Timestamp when the event was stored';
+COMMENT ON COLUMN outbox_events.attempts IS 'Number of failed delivery attempts';
+COMMENT ON COLUMN outbox_events.next_attempt_at IS 'Timestamp before which the event is not retried';
+COMMENT ON COLUMN outbox_events.last_error IS 'Error of the last failed delivery attempt';
+COMMENT ON COLUMN outbox_events.published_at IS 'Timestamp when the event was delivered to every sink, NULL while pending';
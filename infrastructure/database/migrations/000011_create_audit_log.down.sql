@@ -0,0 +1,9 @@
+-- Drop indexes first
+DROP INDEX IF EXISTS idx_audit_log_actor_id;
+DROP INDEX IF EXISTS idx_audit_log_target_user_id;
+
+-- Drop audit log table (its triggers are dropped with it)
+DROP TABLE IF EXISTS audit_log;
+
+-- Drop the append-only trigger function
+DROP FUNCTION IF EXISTS audit_log_reject_change();
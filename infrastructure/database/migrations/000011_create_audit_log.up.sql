@@ -0,0 +1,42 @@
+-- Create audit_log table recording every change to user accounts
+-- Entries are written in the same transaction as the change they describe
+CREATE TABLE IF NOT EXISTS audit_log (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    actor_id UUID,
+    action VARCHAR(50) NOT NULL,
+    target_user_id UUID NOT NULL,
+    changes JSONB NOT NULL DEFAULT '{}',
+    request_id VARCHAR(64),
+    ip_address VARCHAR(45),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Create indexes for better query performance
+CREATE INDEX IF NOT EXISTS idx_audit_log_target_user_id ON audit_log(target_user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_audit_log_actor_id ON audit_log(actor_id);
+
+-- Make the table append-only: entries can never be changed or removed
+CREATE OR REPLACE FUNCTION audit_log_reject_change() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_log is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_log_no_update_delete
+    BEFORE UPDATE OR DELETE ON audit_log
+    FOR EACH ROW EXECUTE FUNCTION audit_log_reject_change();
+
+CREATE TRIGGER audit_log_no_truncate
+    BEFORE TRUNCATE ON audit_log
+    FOR EACH STATEMENT EXECUTE FUNCTION audit_log_reject_change();
+
+-- Add comment to table
+COMMENT ON TABLE audit_log IS 'Append-only log of changes to user accounts';
+COMMENT ON COLUMN audit_log.id IS 'Unique identifier for the entry (UUID v4)';
+COMMENT ON COLUMN audit_log.actor_id IS 'Authenticated user who made the change, NULL for unauthenticated requests such as sign-up or password reset';
+COMMENT ON COLUMN audit_log.action IS 'What was done (e.g. user.updated)';
+COMMENT ON COLUMN audit_log.target_user_id IS 'User account that was changed; no foreign key, so entries outlive the account';
+COMMENT ON COLUMN audit_log.changes IS 'Changed fields as {"field": {"before": ..., "after": ...}}; the password hash is never included';
+COMMENT ON COLUMN audit_log.request_id IS 'ID of the HTTP request that made the change';
+COMMENT ON COLUMN audit_log.ip_address IS 'Client IP address of the request that made the change';
+COMMENT ON COLUMN audit_log.created_at IS 'Timestamp when the change was made';
@@ -0,0 +1,11 @@
+package migrations
+
+import "embed"
+
+/*
+FS holds the SQL migrations in this directory, embedded into the binary so the
+migration runner (see internal/platform/database/migrate) needs no files on disk.
+Migrations are named {version}_{name}.up.sql and {version}_{name}.down.sql.
+*/
+//go:embed *.sql
+var FS embed.FS
@@ -0,0 +1,281 @@
+package application
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/domains/users/domain"
+	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/platform/auth"
+	"github.com/google/uuid"
+)
+
+// apiKeyPrefix starts every API key so that leaked keys are easy to recognise
+const apiKeyPrefix = "ak_"
+
+// apiKeyTouchInterval limits how often a key's last-used time is written
+const apiKeyTouchInterval = time.Minute
+
+/*
+errAPIKeyRejected is returned by VerifyAPIKey for keys that do not authenticate.
+It matches domain.ErrInvalidAPIKey and auth.ErrAPIKeyRejected, which tells the
+authentication middleware to respond 401 rather than 500.
+*/
+var errAPIKeyRejected = fmt.Errorf("%w: %w", auth.ErrAPIKeyRejected, domain.ErrInvalidAPIKey)
+
+/*
+APIKeyService implements the personal API key use cases for the users domain.
+Users create, list and revoke their own keys from an interactive session; scripts
+then authenticate with "Authorization: ApiKey <key>", which the authentication
+middleware resolves through VerifyAPIKey.
+*/
+type APIKeyService struct {
+	apiKeys    domain.APIKeyRepository
+	userRepo   domain.UserRepository
+	background BackgroundRunner
+}
+
+/*
+NewAPIKeyService creates a new APIKeyService instance.
+Requires an APIKeyRepository to store keys, a UserRepository to resolve the
+user a key acts as, and a BackgroundRunner to record key use outside the request.
+*/
+func NewAPIKeyService(apiKeys domain.APIKeyRepository, userRepo domain.UserRepository, background BackgroundRunner) *APIKeyService {
+	return &APIKeyService{
+		apiKeys:    apiKeys,
+		userRepo:   userRepo,
+		background: background,
+	}
+}
+
+/*
+CreateAPIKey creates a named API key for the authenticated user.
+The key is limited to the given scopes (permission names) and optionally expires.
+The plain key is only returned here; afterwards only its prefix is shown.
+
+Returns ErrUnauthorized when called with an API key, ErrInvalidScope for missing
+or unknown scopes, ErrInvalidAPIKeyName or ErrInvalidAPIKeyExpiry.
+*/
+func (s *APIKeyService) CreateAPIKey(ctx context.Context, dto CreateAPIKeyDTO) (*CreatedAPIKeyDTO, error) {
+	principal, err := interactivePrincipal(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	scopes, err := parseScopes(dto.Scopes)
+	if err != nil {
+		return nil, err
+	}
+
+	plainKey, prefix, err := generateAPIKey()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate api key: %w", err)
+	}
+
+	key, err := domain.NewAPIKey(principal.UserID, dto.Name, prefix, hashOpaqueToken(plainKey), scopes, dto.ExpiresAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.apiKeys.Save(ctx, key); err != nil {
+		return nil, err
+	}
+
+	return &CreatedAPIKeyDTO{
+		APIKeyDTO: toAPIKeyDTO(key),
+		Key:       plainKey,
+	}, nil
+}
+
+/*
+ListAPIKeys lists the authenticated user's keys that have not been revoked.
+Returns ErrUnauthorized when called with an API key.
+*/
+func (s *APIKeyService) ListAPIKeys(ctx context.Context) (*APIKeyListResponseDTO, error) {
+	principal, err := interactivePrincipal(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	keys, err := s.apiKeys.ListByUserID(ctx, principal.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	dtos := make([]APIKeyDTO, len(keys))
+	for i, key := range keys {
+		dtos[i] = toAPIKeyDTO(key)
+	}
+
+	return &APIKeyListResponseDTO{APIKeys: dtos}, nil
+}
+
+/*
+RevokeAPIKey revokes one of the authenticated user's keys.
+Returns ErrUnauthorized when called with an API key, and ErrAPIKeyNotFound if
+the user has no active key with that ID.
+*/
+func (s *APIKeyService) RevokeAPIKey(ctx context.Context, id uuid.UUID) error {
+	principal, err := interactivePrincipal(ctx)
+	if err != nil {
+		return err
+	}
+
+	return s.apiKeys.Revoke(ctx, id, principal.UserID, time.Now())
+}
+
+/*
+VerifyAPIKey resolves an API key to the principal it acts as, implementing
+auth.APIKeyVerifier for the authentication middleware.
+The principal carries the user's current role and the key's scopes.
+The key's last-used time is recorded in the background; failing to record it
+does not fail the request.
+Returns ErrInvalidAPIKey (also matching auth.ErrAPIKeyRejected) for unknown,
+revoked or expired keys and for keys of deactivated users. Other errors mean
+the key could not be checked.
+*/
+func (s *APIKeyService) VerifyAPIKey(ctx context.Context, plainKey string) (*auth.Principal, error) {
+	if !strings.HasPrefix(plainKey, apiKeyPrefix) {
+		return nil, errAPIKeyRejected
+	}
+
+	key, err := s.apiKeys.FindByHash(ctx, hashOpaqueToken(plainKey))
+	if err != nil {
+		if errors.Is(err, domain.ErrAPIKeyNotFound) {
+			return nil, errAPIKeyRejected
+		}
+		return nil, fmt.Errorf("failed to find api key: %w", err)
+	}
+
+	now := time.Now()
+
+	if !key.IsUsable(now) {
+		return nil, errAPIKeyRejected
+	}
+
+	user, err := s.userRepo.FindByID(ctx, key.UserID)
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return nil, errAPIKeyRejected
+		}
+		return nil, fmt.Errorf("failed to find api key user: %w", err)
+	}
+	if !user.IsActive {
+		return nil, errAPIKeyRejected
+	}
+
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= apiKeyTouchInterval {
+		s.background.Go(ctx, "record api key use", func(ctx context.Context) error {
+			if err := s.apiKeys.TouchLastUsed(ctx, key.ID, now); err != nil {
+				return fmt.Errorf("failed to record api key use: %w", err)
+			}
+			return nil
+		})
+	}
+
+	scopes := make([]string, len(key.Scopes))
+	for i, scope := range key.Scopes {
+		scopes[i] = string(scope)
+	}
+
+	return &auth.Principal{
+		UserID:   user.ID,
+		Email:    user.Email.Value(),
+		Role:     user.Role.String(),
+		Scopes:   scopes,
+		APIKeyID: key.ID,
+	}, nil
+}
+
+// Helper functions
+
+/*
+interactivePrincipal returns the authenticated principal if it comes from an
+interactive login. Credentials are never managed with an API key, so a leaked
+key cannot be used to mint further keys.
+*/
+func interactivePrincipal(ctx context.Context) (*auth.Principal, error) {
+	principal, ok := auth.PrincipalFromContext(ctx)
+	if !ok || principal.ViaAPIKey() {
+		return nil, domain.ErrUnauthorized
+	}
+	return principal, nil
+}
+
+// parseScopes converts scope names to permissions, dropping duplicates
+func parseScopes(names []string) ([]domain.Permission, error) {
+	if len(names) == 0 {
+		return nil, domain.ErrInvalidScope
+	}
+
+	scopes := make([]domain.Permission, 0, len(names))
+	seen := make(map[domain.Permission]bool, len(names))
+	for _, name := range names {
+		scope, err := domain.ParsePermission(name)
+		if err != nil {
+			return nil, err
+		}
+		if !seen[scope] {
+			seen[scope] = true
+			scopes = append(scopes, scope)
+		}
+	}
+
+	return scopes, nil
+}
+
+/*
+generateAPIKey returns a new random API key and its non-secret prefix.
+Keys look like ak_<8 hex characters>_<43 characters>; the part up to the second
+underscore is the prefix shown in listings.
+*/
+func generateAPIKey() (string, string, error) {
+	id := make([]byte, 4)
+	if _, err := rand.Read(id); err != nil {
+		return "", "", err
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", "", err
+	}
+
+	prefix := apiKeyPrefix + hex.EncodeToString(id)
+	return prefix + "_" + base64.RawURLEncoding.EncodeToString(secret), prefix, nil
+}
+
+// scopePermissions converts a principal's scopes to permissions, keeping nil (unrestricted) as nil
+func scopePermissions(scopes []string) []domain.Permission {
+	if scopes == nil {
+		return nil
+	}
+
+	permissions := make([]domain.Permission, len(scopes))
+	for i, scope := range scopes {
+		permissions[i] = domain.Permission(scope)
+	}
+	return permissions
+}
+
+// toAPIKeyDTO maps an APIKey entity to its DTO, leaving out the hash
+func toAPIKeyDTO(key *domain.APIKey) APIKeyDTO {
+	scopes := make([]string, len(key.Scopes))
+	for i, scope := range key.Scopes {
+		scopes[i] = string(scope)
+	}
+
+	return APIKeyDTO{
+		ID:         key.ID,
+		Name:       key.Name,
+		Prefix:     key.Prefix,
+		Scopes:     scopes,
+		ExpiresAt:  key.ExpiresAt,
+		LastUsedAt: key.LastUsedAt,
+		CreatedAt:  key.CreatedAt,
+	}
+}
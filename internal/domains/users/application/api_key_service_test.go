@@ -0,0 +1,351 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/domains/users/domain"
+	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/domains/users/infrastructure/memory"
+	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/platform/auth"
+)
+
+// apiKeyStore keeps API keys in memory and can be told to fail
+type apiKeyStore struct {
+	keys     map[string]*domain.APIKey
+	findErr  error
+	touchErr error
+	touched  int
+}
+
+func (s *apiKeyStore) Save(ctx context.Context, key *domain.APIKey) error {
+	s.keys[key.KeyHash] = key
+	return nil
+}
+
+func (s *apiKeyStore) FindByHash(ctx context.Context, keyHash string) (*domain.APIKey, error) {
+	if s.findErr != nil {
+		return nil, s.findErr
+	}
+	key, ok := s.keys[keyHash]
+	if !ok {
+		return nil, domain.ErrAPIKeyNotFound
+	}
+	return key, nil
+}
+
+func (s *apiKeyStore) ListByUserID(ctx context.Context, userID uuid.UUID) ([]*domain.APIKey, error) {
+	var keys []*domain.APIKey
+	for _, key := range s.keys {
+		if key.UserID == userID && key.RevokedAt == nil {
+			keys = append(keys, key)
+		}
+	}
+	return keys, nil
+}
+
+func (s *apiKeyStore) Revoke(ctx context.Context, id, userID uuid.UUID, at time.Time) error {
+	for _, key := range s.keys {
+		if key.ID == id && key.UserID == userID && key.RevokedAt == nil {
+			key.RevokedAt = &at
+			return nil
+		}
+	}
+	return domain.ErrAPIKeyNotFound
+}
+
+func (s *apiKeyStore) TouchLastUsed(ctx context.Context, id uuid.UUID, at time.Time) error {
+	if s.touchErr != nil {
+		return s.touchErr
+	}
+	for _, key := range s.keys {
+		if key.ID == id {
+			key.LastUsedAt = &at
+			s.touched++
+		}
+	}
+	return nil
+}
+
+type apiKeyFixture struct {
+	service *APIKeyService
+	keys    *apiKeyStore
+	users   *memory.UserRepository
+	user    *domain.User
+	runner  *syncRunner
+	session context.Context // authenticated as user through an interactive login
+}
+
+func newAPIKeyFixture(t *testing.T) *apiKeyFixture {
+	t.Helper()
+
+	users := memory.NewUserRepository()
+	email, _ := domain.NewEmail("owner@example.com")
+	user, err := domain.NewUser(email, "Key Owner", "hash")
+	if err != nil {
+		t.Fatalf("NewUser() error = %v", err)
+	}
+	if err := users.Save(context.Background(), user); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	keys := &apiKeyStore{keys: make(map[string]*domain.APIKey)}
+	runner := &syncRunner{}
+
+	return &apiKeyFixture{
+		service: NewAPIKeyService(keys, users, runner),
+		keys:    keys,
+		users:   users,
+		user:    user,
+		runner:  runner,
+		session: auth.WithPrincipal(context.Background(), &auth.Principal{
+			UserID: user.ID,
+			Email:  user.Email.Value(),
+			Role:   user.Role.String(),
+		}),
+	}
+}
+
+// createKey creates a key for the fixture's user and returns its plain value
+func (f *apiKeyFixture) createKey(t *testing.T, scopes ...string) (string, *domain.APIKey) {
+	t.Helper()
+
+	created, err := f.service.CreateAPIKey(f.session, CreateAPIKeyDTO{Name: "ci", Scopes: scopes})
+	if err != nil {
+		t.Fatalf("CreateAPIKey() error = %v", err)
+	}
+	return created.Key, f.keys.keys[hashOpaqueToken(created.Key)]
+}
+
+func TestGenerateAPIKey(t *testing.T) {
+	format := regexp.MustCompile(`^ak_[0-9a-f]{8}_[A-Za-z0-9_-]{43}$`)
+
+	first, prefix, err := generateAPIKey()
+	if err != nil {
+		t.Fatalf("generateAPIKey() error = %v", err)
+	}
+	if !format.MatchString(first) {
+		t.Errorf("generateAPIKey() key = %q, want ak_<8 hex>_<43 characters>", first)
+	}
+	if want := first[:len("ak_")+8]; prefix != want {
+		t.Errorf("generateAPIKey() prefix = %q, want %q", prefix, want)
+	}
+
+	second, _, err := generateAPIKey()
+	if err != nil {
+		t.Fatalf("generateAPIKey() error = %v", err)
+	}
+	if first == second {
+		t.Error("generateAPIKey() returned the same key twice")
+	}
+}
+
+func TestParseScopes(t *testing.T) {
+	tests := []struct {
+		name    string
+		scopes  []string
+		want    []domain.Permission
+		wantErr error
+	}{
+		{name: "single", scopes: []string{"users:read"}, want: []domain.Permission{domain.PermissionUsersRead}},
+		{
+			name:   "duplicates dropped",
+			scopes: []string{"users:read", "users:list", "users:read"},
+			want:   []domain.Permission{domain.PermissionUsersRead, domain.PermissionUsersList},
+		},
+		{name: "none", scopes: nil, wantErr: domain.ErrInvalidScope},
+		{name: "unknown", scopes: []string{"users:read", "users:delete"}, wantErr: domain.ErrInvalidScope},
+		{name: "role is not a scope", scopes: []string{"admin"}, wantErr: domain.ErrInvalidScope},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseScopes(tt.scopes)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("parseScopes() error = %v, want %v", err, tt.wantErr)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("parseScopes() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("parseScopes() = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestCreateAPIKeyRequiresInteractiveSession(t *testing.T) {
+	f := newAPIKeyFixture(t)
+
+	viaKey := auth.WithPrincipal(context.Background(), &auth.Principal{
+		UserID:   f.user.ID,
+		Role:     f.user.Role.String(),
+		Scopes:   []string{"users:read"},
+		APIKeyID: uuid.New(),
+	})
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+	}{
+		{name: "anonymous", ctx: context.Background()},
+		{name: "api key", ctx: viaKey},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := f.service.CreateAPIKey(tt.ctx, CreateAPIKeyDTO{Name: "ci", Scopes: []string{"users:read"}})
+			if !errors.Is(err, domain.ErrUnauthorized) {
+				t.Errorf("CreateAPIKey() error = %v, want %v", err, domain.ErrUnauthorized)
+			}
+		})
+	}
+}
+
+func TestVerifyAPIKey(t *testing.T) {
+	f := newAPIKeyFixture(t)
+	plainKey, key := f.createKey(t, "users:read")
+
+	principal, err := f.service.VerifyAPIKey(context.Background(), plainKey)
+	if err != nil {
+		t.Fatalf("VerifyAPIKey() error = %v", err)
+	}
+
+	if principal.UserID != f.user.ID || principal.Role != f.user.Role.String() {
+		t.Errorf("VerifyAPIKey() principal = %+v, want user %s with role %s", principal, f.user.ID, f.user.Role)
+	}
+	if principal.APIKeyID != key.ID || !principal.ViaAPIKey() {
+		t.Errorf("VerifyAPIKey() APIKeyID = %s, want %s", principal.APIKeyID, key.ID)
+	}
+	if len(principal.Scopes) != 1 || principal.Scopes[0] != "users:read" {
+		t.Errorf("VerifyAPIKey() Scopes = %v, want [users:read]", principal.Scopes)
+	}
+	if f.keys.touched != 1 || key.LastUsedAt == nil {
+		t.Errorf("TouchLastUsed calls = %d, want 1", f.keys.touched)
+	}
+
+	// Within the touch interval the last-used time is not written again
+	if _, err := f.service.VerifyAPIKey(context.Background(), plainKey); err != nil {
+		t.Fatalf("VerifyAPIKey() error = %v", err)
+	}
+	if f.keys.touched != 1 {
+		t.Errorf("TouchLastUsed calls = %d, want 1", f.keys.touched)
+	}
+}
+
+func TestVerifyAPIKeyScopesRestrictTheRole(t *testing.T) {
+	f := newAPIKeyFixture(t)
+	plainKey, _ := f.createKey(t, "users:read")
+
+	principal, err := f.service.VerifyAPIKey(context.Background(), plainKey)
+	if err != nil {
+		t.Fatalf("VerifyAPIKey() error = %v", err)
+	}
+	ctx := auth.WithPrincipal(context.Background(), principal)
+	users := &UserService{}
+
+	if err := users.authorize(ctx, domain.PermissionUsersRead, f.user.ID); err != nil {
+		t.Errorf("authorize(read self) = %v, want nil", err)
+	}
+	// Members may update themselves, but not with a read-only key
+	if err := users.authorize(ctx, domain.PermissionUsersUpdate, f.user.ID); !errors.Is(err, domain.ErrUnauthorized) {
+		t.Errorf("authorize(update self) = %v, want %v", err, domain.ErrUnauthorized)
+	}
+}
+
+func TestVerifyAPIKeyRejects(t *testing.T) {
+	tests := []struct {
+		name string
+		key  func(t *testing.T, f *apiKeyFixture) string
+	}{
+		{
+			name: "wrong prefix",
+			key: func(t *testing.T, f *apiKeyFixture) string {
+				plainKey, _ := f.createKey(t, "users:read")
+				return "xx" + plainKey[2:]
+			},
+		},
+		{
+			name: "unknown key",
+			key: func(t *testing.T, f *apiKeyFixture) string {
+				plainKey, _, _ := generateAPIKey()
+				return plainKey
+			},
+		},
+		{
+			name: "expired",
+			key: func(t *testing.T, f *apiKeyFixture) string {
+				plainKey, key := f.createKey(t, "users:read")
+				expired := time.Now().Add(-time.Second)
+				key.ExpiresAt = &expired
+				return plainKey
+			},
+		},
+		{
+			name: "revoked",
+			key: func(t *testing.T, f *apiKeyFixture) string {
+				plainKey, key := f.createKey(t, "users:read")
+				if err := f.service.RevokeAPIKey(f.session, key.ID); err != nil {
+					t.Fatalf("RevokeAPIKey() error = %v", err)
+				}
+				return plainKey
+			},
+		},
+		{
+			name: "deactivated user",
+			key: func(t *testing.T, f *apiKeyFixture) string {
+				plainKey, _ := f.createKey(t, "users:read")
+				f.user.Deactivate()
+				if err := f.users.Update(context.Background(), f.user); err != nil {
+					t.Fatalf("Update() error = %v", err)
+				}
+				return plainKey
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newAPIKeyFixture(t)
+			plainKey := tt.key(t, f)
+
+			principal, err := f.service.VerifyAPIKey(context.Background(), plainKey)
+			if principal != nil {
+				t.Errorf("VerifyAPIKey() principal = %+v, want nil", principal)
+			}
+			if !errors.Is(err, domain.ErrInvalidAPIKey) || !errors.Is(err, auth.ErrAPIKeyRejected) {
+				t.Errorf("VerifyAPIKey() error = %v, want %v and %v", err, domain.ErrInvalidAPIKey, auth.ErrAPIKeyRejected)
+			}
+		})
+	}
+}
+
+func TestVerifyAPIKeyStoreFailure(t *testing.T) {
+	f := newAPIKeyFixture(t)
+	plainKey, _ := f.createKey(t, "users:read")
+	f.keys.findErr = errors.New("connection refused")
+
+	_, err := f.service.VerifyAPIKey(context.Background(), plainKey)
+	if err == nil || errors.Is(err, auth.ErrAPIKeyRejected) {
+		t.Errorf("VerifyAPIKey() error = %v, want a failure that is not a rejection", err)
+	}
+}
+
+func TestVerifyAPIKeyIgnoresTouchFailure(t *testing.T) {
+	f := newAPIKeyFixture(t)
+	plainKey, _ := f.createKey(t, "users:read")
+	f.keys.touchErr = errors.New("connection refused")
+
+	if _, err := f.service.VerifyAPIKey(context.Background(), plainKey); err != nil {
+		t.Fatalf("VerifyAPIKey() error = %v, want nil", err)
+	}
+	if len(f.runner.errs) != 1 {
+		t.Errorf("background errors = %v, want the touch failure", f.runner.errs)
+	}
+}
@@ -0,0 +1,764 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/domains/users/domain"
+	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/platform/mailer"
+	"github.com/google/uuid"
+)
+
+/*
+TokenIssuer is the port used to issue access tokens for authenticated users, and
+the short-lived challenge tokens that bridge the two steps of an MFA login.
+It is implemented outside the domain (e.g. by the platform JWT token manager),
+so the application layer does not depend on a specific token format.
+*/
+type TokenIssuer interface {
+	IssueAccessToken(userID uuid.UUID, email, role string) (string, time.Time, error)
+	IssueMFAChallenge(userID uuid.UUID) (string, time.Time, error)
+	VerifyMFAChallenge(token string) (uuid.UUID, error)
+}
+
+// AuthSettings holds the tunable parameters of the authentication use cases
+type AuthSettings struct {
+	RefreshTokenTTL  time.Duration
+	PasswordResetTTL time.Duration
+	PasswordResetURL string
+	// RequireEmailVerification rejects login until the email address is verified
+	RequireEmailVerification bool
+}
+
+/*
+ResetLimiters throttle password reset requests, so the endpoint cannot be used
+to flood an inbox or to probe many addresses. PerClient counts the requests of
+a client IP and PerEmail the requests for an email, whether or not it is
+registered.
+*/
+type ResetLimiters struct {
+	PerClient AttemptLimiter
+	PerEmail  AttemptLimiter
+}
+
+/*
+AuthService implements the authentication use cases for the users domain.
+It verifies user credentials against the UserRepository, issues access tokens
+through the TokenIssuer port, manages rotating refresh tokens, and runs the
+emailed password reset and email verification flows through the Mailer port.
+Users with TOTP enabled complete login with a second factor via the TOTPAuthenticator.
+*/
+type AuthService struct {
+	userRepo      domain.UserRepository
+	refreshTokens domain.RefreshTokenRepository
+	resetTokens   domain.PasswordResetTokenRepository
+	uow           UnitOfWork
+	events        EventPublisher
+	verifier      *EmailVerifier
+	mfa           *TOTPAuthenticator
+	guard         *LoginGuard
+	passwords     PasswordPolicy
+	hasher        PasswordHasher
+	tokens        TokenIssuer
+	mailer        mailer.Mailer
+	background    BackgroundRunner
+	resetLimiters ResetLimiters
+	settings      AuthSettings
+
+	// dummyHash is checked against when the email is unknown so that login takes
+	// roughly the same time whether or not the account exists
+	dummyHash     string
+	dummyHashOnce sync.Once
+}
+
+/*
+NewAuthService creates a new AuthService instance.
+Requires a UserRepository to look up credentials, repositories for refresh and
+password reset tokens, a UnitOfWork for transaction boundaries, an EventPublisher
+for domain events, an EmailVerifier for verification links, a TOTPAuthenticator
+for two-factor login, a LoginGuard against brute force, the PasswordPolicy for reset
+passwords, a PasswordHasher, a TokenIssuer to sign access and challenge tokens, a
+Mailer to deliver reset links, a BackgroundRunner to send them outside the request,
+ResetLimiters to throttle reset requests, and the AuthSettings.
+*/
+func NewAuthService(
+	userRepo domain.UserRepository,
+	refreshTokens domain.RefreshTokenRepository,
+	resetTokens domain.PasswordResetTokenRepository,
+	uow UnitOfWork,
+	events EventPublisher,
+	verifier *EmailVerifier,
+	mfa *TOTPAuthenticator,
+	guard *LoginGuard,
+	passwords PasswordPolicy,
+	hasher PasswordHasher,
+	tokens TokenIssuer,
+	mail mailer.Mailer,
+	background BackgroundRunner,
+	resetLimiters ResetLimiters,
+	settings AuthSettings,
+) *AuthService {
+	return &AuthService{
+		userRepo:      userRepo,
+		refreshTokens: refreshTokens,
+		resetTokens:   resetTokens,
+		uow:           uow,
+		events:        events,
+		verifier:      verifier,
+		mfa:           mfa,
+		guard:         guard,
+		passwords:     passwords,
+		hasher:        hasher,
+		tokens:        tokens,
+		mailer:        mail,
+		background:    background,
+		resetLimiters: resetLimiters,
+		settings:      settings,
+	}
+}
+
+/*
+Login authenticates a user by email and password.
+This use case:
+ 1. Rejects clients with too many recent failures
+ 2. Looks up the user by email (including deactivated accounts)
+ 3. Rejects locked accounts
+ 4. Verifies the password against the stored hash, counting failures
+ 5. Rejects deactivated accounts
+ 6. Rejects unverified email addresses when verification is required
+ 7. Rehashes the password if its stored hash uses outdated settings
+ 8. For users with TOTP enabled, returns an MFA challenge to be completed with
+    CompleteMFALogin instead of tokens
+ 9. Otherwise clears failed attempts and issues a signed access token and a
+    refresh token starting a new token family
+
+Returns ErrInvalidCredentials for an unknown email or wrong password, so callers
+cannot tell which one was wrong. Returns ErrUserInactive only after the password
+has been verified, so deactivated accounts are not disclosed to guessers.
+Likewise ErrEmailNotVerified is only returned after the password check.
+Returns ErrAccountLocked or ErrTooManyLoginAttempts (as a domain.LockoutError)
+when attempts are being throttled.
+*/
+func (s *AuthService) Login(ctx context.Context, dto LoginDTO) (*LoginResultDTO, error) {
+	now := time.Now()
+
+	if err := s.guard.checkClient(dto.ClientIP, now); err != nil {
+		return nil, err
+	}
+
+	email, err := domain.NewEmail(dto.Email)
+	if err != nil {
+		s.guard.recordClientFailure(dto.ClientIP, now)
+		return nil, domain.ErrInvalidCredentials
+	}
+
+	user, err := s.userRepo.FindByEmailIncludingInactive(ctx, email)
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			s.verifyDummyPassword(dto.Password)
+			s.guard.recordClientFailure(dto.ClientIP, now)
+			return nil, domain.ErrInvalidCredentials
+		}
+		return nil, fmt.Errorf("failed to find user: %w", err)
+	}
+
+	if err := s.guard.checkUser(user, now); err != nil {
+		return nil, err
+	}
+
+	if err := s.hasher.Verify(user.PasswordHash, dto.Password); err != nil {
+		if err := s.guard.recordFailure(ctx, user, dto.ClientIP, now); err != nil {
+			return nil, err
+		}
+		return nil, domain.ErrInvalidCredentials
+	}
+
+	if !user.IsActive {
+		return nil, domain.ErrUserInactive
+	}
+
+	if s.settings.RequireEmailVerification && !user.IsEmailVerified() {
+		return nil, domain.ErrEmailNotVerified
+	}
+
+	if err := s.rehashPassword(ctx, user, dto.Password); err != nil {
+		return nil, err
+	}
+
+	mfaEnabled, err := s.mfa.isEnabled(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Failed attempts are only cleared once the second factor is passed too
+	if mfaEnabled {
+		challenge, expiresAt, err := s.tokens.IssueMFAChallenge(user.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to issue mfa challenge: %w", err)
+		}
+		return &LoginResultDTO{
+			MFAChallenge: &MFAChallengeDTO{MFAToken: challenge, ExpiresAt: expiresAt},
+		}, nil
+	}
+
+	var tokens *TokenResponseDTO
+	err = s.uow.Do(ctx, func(ctx context.Context) error {
+		if err := s.guard.recordSuccess(ctx, user); err != nil {
+			return err
+		}
+
+		tokens, err = s.issueTokens(ctx, user, uuid.New())
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &LoginResultDTO{Tokens: tokens}, nil
+}
+
+/*
+CompleteMFALogin completes a two-factor login started by Login.
+This use case:
+ 1. Verifies the MFA challenge token from the password step
+ 2. Rejects throttled clients and locked accounts
+ 3. Verifies the TOTP or recovery code, counting failures like wrong passwords
+ 4. Clears failed attempts and issues a signed access token and a refresh token
+    starting a new token family
+
+Steps 3 and 4 run in one transaction, so a recovery code is only used up when
+the login succeeds. Failures are recorded after the transaction has rolled back.
+
+Returns ErrInvalidMFAChallenge for an invalid or expired challenge token, and
+ErrInvalidMFACode for a wrong, replayed or already used code.
+*/
+func (s *AuthService) CompleteMFALogin(ctx context.Context, dto MFALoginDTO) (*TokenResponseDTO, error) {
+	now := time.Now()
+
+	if err := s.guard.checkClient(dto.ClientIP, now); err != nil {
+		return nil, err
+	}
+
+	userID, err := s.tokens.VerifyMFAChallenge(dto.MFAToken)
+	if err != nil {
+		return nil, domain.ErrInvalidMFAChallenge
+	}
+
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return nil, domain.ErrInvalidMFAChallenge
+		}
+		return nil, fmt.Errorf("failed to find user: %w", err)
+	}
+
+	if err := s.guard.checkUser(user, now); err != nil {
+		return nil, err
+	}
+
+	var tokens *TokenResponseDTO
+	err = s.uow.Do(ctx, func(ctx context.Context) error {
+		if err := s.mfa.verify(ctx, user.ID, dto.Code); err != nil {
+			return err
+		}
+
+		if err := s.guard.recordSuccess(ctx, user); err != nil {
+			return err
+		}
+
+		tokens, err = s.issueTokens(ctx, user, uuid.New())
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidMFACode) {
+			if err := s.guard.recordFailure(ctx, user, dto.ClientIP, now); err != nil {
+				return nil, err
+			}
+		}
+		return nil, err
+	}
+
+	return tokens, nil
+}
+
+/*
+EnrollTOTP starts TOTP enrolment for the authenticated user.
+Returns the secret and an otpauth:// URI to add to an authenticator app; the
+enrolment only takes effect once confirmed with ConfirmTOTP.
+Returns ErrMFAAlreadyEnabled if TOTP is already enabled.
+*/
+func (s *AuthService) EnrollTOTP(ctx context.Context) (*TOTPEnrollmentDTO, error) {
+	user, err := s.currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.mfa.enroll(ctx, user)
+}
+
+/*
+ConfirmTOTP enables TOTP for the authenticated user once they prove their
+authenticator app produces valid codes.
+The factor is confirmed and the recovery codes are stored in one transaction.
+Returns the recovery codes, which are only shown this once.
+*/
+func (s *AuthService) ConfirmTOTP(ctx context.Context, dto ConfirmTOTPDTO) (*RecoveryCodesDTO, error) {
+	user, err := s.currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var codes *RecoveryCodesDTO
+	err = s.uow.Do(ctx, func(ctx context.Context) error {
+		codes, err = s.mfa.confirm(ctx, user.ID, dto.Code)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+/*
+Refresh exchanges a refresh token for a new access token and refresh token.
+This use case:
+ 1. Looks up the presented token by its hash
+ 2. Detects reuse of an already-rotated token and revokes the whole family
+ 3. Rejects expired or revoked tokens and tokens of deactivated users
+ 4. Marks the presented token as rotated and issues a new pair in the same family,
+    in one transaction
+
+Returns ErrInvalidRefreshToken for unknown, expired or revoked tokens, and
+ErrRefreshTokenReused when a rotated token is presented again.
+*/
+func (s *AuthService) Refresh(ctx context.Context, dto RefreshTokenDTO) (*TokenResponseDTO, error) {
+	current, err := s.refreshTokens.FindByHash(ctx, hashOpaqueToken(dto.RefreshToken))
+	if err != nil {
+		if errors.Is(err, domain.ErrRefreshTokenNotFound) {
+			return nil, domain.ErrInvalidRefreshToken
+		}
+		return nil, fmt.Errorf("failed to find refresh token: %w", err)
+	}
+
+	now := time.Now()
+
+	if current.IsRevoked() || current.IsExpired(now) {
+		return nil, domain.ErrInvalidRefreshToken
+	}
+
+	if current.IsRotated() {
+		return nil, s.revokeReusedFamily(ctx, current.FamilyID)
+	}
+
+	user, err := s.userRepo.FindByID(ctx, current.UserID)
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			if err := s.refreshTokens.RevokeFamily(ctx, current.FamilyID, now); err != nil {
+				return nil, fmt.Errorf("failed to revoke refresh tokens: %w", err)
+			}
+			return nil, domain.ErrInvalidRefreshToken
+		}
+		return nil, fmt.Errorf("failed to find user: %w", err)
+	}
+
+	var tokens *TokenResponseDTO
+	err = s.uow.Do(ctx, func(ctx context.Context) error {
+		// Rotate; losing a concurrent race for the same token counts as reuse
+		if err := s.refreshTokens.MarkRotated(ctx, current.ID, now); err != nil {
+			if errors.Is(err, domain.ErrRefreshTokenReused) {
+				return err
+			}
+			return fmt.Errorf("failed to rotate refresh token: %w", err)
+		}
+
+		tokens, err = s.issueTokens(ctx, user, current.FamilyID)
+		return err
+	})
+	if err != nil {
+		// The family is revoked outside the rolled back transaction
+		if errors.Is(err, domain.ErrRefreshTokenReused) {
+			return nil, s.revokeReusedFamily(ctx, current.FamilyID)
+		}
+		return nil, err
+	}
+
+	return tokens, nil
+}
+
+/*
+Logout revokes the presented refresh token together with its whole family.
+Unknown tokens are ignored so logout is idempotent.
+*/
+func (s *AuthService) Logout(ctx context.Context, dto RefreshTokenDTO) error {
+	current, err := s.refreshTokens.FindByHash(ctx, hashOpaqueToken(dto.RefreshToken))
+	if err != nil {
+		if errors.Is(err, domain.ErrRefreshTokenNotFound) {
+			return nil
+		}
+		return fmt.Errorf("failed to find refresh token: %w", err)
+	}
+
+	if err := s.refreshTokens.RevokeFamily(ctx, current.FamilyID, time.Now()); err != nil {
+		return fmt.Errorf("failed to revoke refresh tokens: %w", err)
+	}
+
+	return nil
+}
+
+/*
+ForgotPassword starts the password reset flow for the given email.
+This use case:
+ 1. Rejects the request if the client or the email has made too many requests
+ 2. Hands the rest of the flow (see sendPasswordReset) to the BackgroundRunner
+
+To avoid disclosing which emails are registered, the call behaves the same for
+unknown and registered emails: both are throttled alike, and the lookup and
+email are done in the background, so neither their timing nor their failures
+reach the caller.
+Returns ErrTooManyResetRequests (as a domain.LockoutError) when throttled.
+*/
+func (s *AuthService) ForgotPassword(ctx context.Context, dto ForgotPasswordDTO) error {
+	email, err := domain.NewEmail(dto.Email)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+
+	if err := s.throttleReset(dto.ClientIP, email.Value(), now); err != nil {
+		return err
+	}
+
+	s.background.Go(ctx, "send password reset email", func(ctx context.Context) error {
+		return s.sendPasswordReset(ctx, email, now)
+	})
+
+	return nil
+}
+
+/*
+throttleReset counts a password reset request against the client and the email.
+Returns ErrTooManyResetRequests (as a LockoutError) if either has reached its limit.
+*/
+func (s *AuthService) throttleReset(clientIP, email string, now time.Time) error {
+	checks := []struct {
+		limiter AttemptLimiter
+		key     string
+	}{
+		{s.resetLimiters.PerClient, clientIP},
+		{s.resetLimiters.PerEmail, email},
+	}
+
+	for _, check := range checks {
+		if check.key == "" {
+			continue
+		}
+		if retryAfter, ok := check.limiter.Allow(check.key, now); !ok {
+			return &domain.LockoutError{Err: domain.ErrTooManyResetRequests, RetryAfter: retryAfter}
+		}
+	}
+
+	for _, check := range checks {
+		if check.key != "" {
+			check.limiter.RecordFailure(check.key, now)
+		}
+	}
+	return nil
+}
+
+/*
+sendPasswordReset emails a reset link to the active user with the given email.
+It:
+ 1. Looks up the active user by email; an unknown email sends nothing
+ 2. Invalidates any earlier reset tokens of that user
+ 3. Stores the hash of a new single-use, expiring token
+ 4. Emails a reset link containing the plain token
+
+Steps 2 and 3 run in one transaction; the email is sent once it has committed.
+*/
+func (s *AuthService) sendPasswordReset(ctx context.Context, email domain.Email, now time.Time) error {
+	user, err := s.userRepo.FindByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return nil
+		}
+		return fmt.Errorf("failed to find user: %w", err)
+	}
+
+	plainToken, tokenHash, err := generateOpaqueToken()
+	if err != nil {
+		return fmt.Errorf("failed to generate reset token: %w", err)
+	}
+
+	resetToken, err := domain.NewPasswordResetToken(user.ID, tokenHash, s.settings.PasswordResetTTL)
+	if err != nil {
+		return fmt.Errorf("failed to create reset token entity: %w", err)
+	}
+
+	err = s.uow.Do(ctx, func(ctx context.Context) error {
+		if err := s.resetTokens.InvalidateAllForUser(ctx, user.ID, now); err != nil {
+			return fmt.Errorf("failed to invalidate previous reset tokens: %w", err)
+		}
+
+		if err := s.resetTokens.Save(ctx, resetToken); err != nil {
+			return fmt.Errorf("failed to save reset token: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	msg := mailer.Message{
+		To:      user.Email.Value(),
+		Subject: "Reset your password",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nWe received a request to reset your password. Use the link below to choose a new one:\n\n%s?token=%s\n\nThe link expires in %s and can be used once. If you did not request a reset, you can ignore this email.\n",
+			user.Name, s.settings.PasswordResetURL, plainToken, s.settings.PasswordResetTTL,
+		),
+	}
+	if err := s.mailer.Send(ctx, msg); err != nil {
+		return fmt.Errorf("failed to send reset email: %w", err)
+	}
+
+	return nil
+}
+
+/*
+ResetPassword sets a new password using an emailed reset token.
+This use case:
+ 1. Looks up the token by its hash and checks it is unused and unexpired
+ 2. Validates and hashes the new password
+ 3. Marks the token as used (only one concurrent request can succeed)
+ 4. Stores the new password
+ 5. Revokes all refresh tokens so existing sessions must log in again
+ 6. Publishes UserPasswordChanged
+
+Steps 3 to 5 run in one transaction, so the token is only used up once the
+password has changed and every session has been revoked.
+
+Since the reset link was delivered by email, a successful reset also marks the
+email address as verified.
+
+Returns ErrInvalidResetToken for unknown, used or expired tokens.
+*/
+func (s *AuthService) ResetPassword(ctx context.Context, dto ResetPasswordDTO) error {
+	resetToken, err := s.resetTokens.FindByHash(ctx, hashOpaqueToken(dto.Token))
+	if err != nil {
+		if errors.Is(err, domain.ErrPasswordResetTokenNotFound) {
+			return domain.ErrInvalidResetToken
+		}
+		return fmt.Errorf("failed to find reset token: %w", err)
+	}
+
+	now := time.Now()
+
+	if !resetToken.IsUsable(now) {
+		return domain.ErrInvalidResetToken
+	}
+
+	user, err := s.userRepo.FindByID(ctx, resetToken.UserID)
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return domain.ErrInvalidResetToken
+		}
+		return fmt.Errorf("failed to find user: %w", err)
+	}
+
+	if err := s.passwords.Validate("new_password", dto.NewPassword, user.Email.Value(), user.Name); err != nil {
+		return err
+	}
+
+	passwordHash, err := s.hasher.Hash(dto.NewPassword)
+	if err != nil {
+		return fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	err = s.uow.Do(ctx, func(ctx context.Context) error {
+		if err := s.resetTokens.MarkUsed(ctx, resetToken.ID, now); err != nil {
+			return err
+		}
+
+		// Reload the user so a retried transaction starts from the stored state
+		user, err = s.userRepo.FindByID(ctx, resetToken.UserID)
+		if err != nil {
+			if errors.Is(err, domain.ErrUserNotFound) {
+				return domain.ErrInvalidResetToken
+			}
+			return fmt.Errorf("failed to find user: %w", err)
+		}
+
+		if err := user.ChangePassword(passwordHash); err != nil {
+			return err
+		}
+		user.VerifyEmail()
+
+		if err := s.userRepo.Update(ctx, user); err != nil {
+			return fmt.Errorf("failed to update password: %w", err)
+		}
+
+		if err := s.refreshTokens.RevokeAllForUser(ctx, user.ID, now); err != nil {
+			return fmt.Errorf("failed to revoke refresh tokens: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	publishEvents(ctx, s.events, user)
+
+	return nil
+}
+
+/*
+VerifyEmail marks a user's email address as verified using an emailed token.
+This use case:
+ 1. Looks up the token by its hash and checks it is unused and unexpired
+ 2. Marks the token as used
+ 3. Records the verification on the user
+
+Steps 2 and 3 run in one transaction.
+
+Returns ErrInvalidVerificationToken for unknown, used or expired tokens.
+*/
+func (s *AuthService) VerifyEmail(ctx context.Context, dto VerifyEmailDTO) error {
+	return s.uow.Do(ctx, func(ctx context.Context) error {
+		token, err := s.verifier.consume(ctx, dto.Token)
+		if err != nil {
+			return err
+		}
+
+		user, err := s.userRepo.FindByID(ctx, token.UserID)
+		if err != nil {
+			if errors.Is(err, domain.ErrUserNotFound) {
+				return domain.ErrInvalidVerificationToken
+			}
+			return fmt.Errorf("failed to find user: %w", err)
+		}
+
+		if user.IsEmailVerified() {
+			return nil
+		}
+
+		user.VerifyEmail()
+
+		if err := s.userRepo.Update(ctx, user); err != nil {
+			return fmt.Errorf("failed to update user: %w", err)
+		}
+		return nil
+	})
+}
+
+/*
+ResendVerificationEmail emails a new verification link to an unverified user.
+To avoid disclosing which emails are registered, unknown and already verified
+emails are not errors: the call succeeds without sending anything.
+*/
+func (s *AuthService) ResendVerificationEmail(ctx context.Context, dto ResendVerificationDTO) error {
+	email, err := domain.NewEmail(dto.Email)
+	if err != nil {
+		return err
+	}
+
+	user, err := s.userRepo.FindByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return nil
+		}
+		return fmt.Errorf("failed to find user: %w", err)
+	}
+
+	if user.IsEmailVerified() {
+		return nil
+	}
+
+	return s.verifier.SendVerification(ctx, user)
+}
+
+// Helper methods
+
+// issueTokens issues an access token and a new refresh token in the given family
+func (s *AuthService) issueTokens(ctx context.Context, user *domain.User, familyID uuid.UUID) (*TokenResponseDTO, error) {
+	accessToken, expiresAt, err := s.tokens.IssueAccessToken(user.ID, user.Email.Value(), user.Role.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to issue access token: %w", err)
+	}
+
+	plainRefreshToken, refreshTokenHash, err := generateOpaqueToken()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate refresh token: %w", err)
+	}
+
+	refreshToken, err := domain.NewRefreshToken(user.ID, familyID, refreshTokenHash, s.settings.RefreshTokenTTL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create refresh token entity: %w", err)
+	}
+
+	if err := s.refreshTokens.Save(ctx, refreshToken); err != nil {
+		return nil, fmt.Errorf("failed to save refresh token: %w", err)
+	}
+
+	return &TokenResponseDTO{
+		AccessToken:           accessToken,
+		TokenType:             "Bearer",
+		ExpiresAt:             expiresAt,
+		RefreshToken:          plainRefreshToken,
+		RefreshTokenExpiresAt: refreshToken.ExpiresAt,
+	}, nil
+}
+
+// currentUser loads the authenticated user found in the context; API keys are refused
+func (s *AuthService) currentUser(ctx context.Context) (*domain.User, error) {
+	principal, err := interactivePrincipal(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.userRepo.FindByID(ctx, principal.UserID)
+}
+
+/*
+rehashPassword replaces a stored hash made with an outdated algorithm or
+parameters by a fresh hash of the password. It must only be called after the
+password has been verified against the stored hash.
+*/
+func (s *AuthService) rehashPassword(ctx context.Context, user *domain.User, password string) error {
+	if !s.hasher.NeedsRehash(user.PasswordHash) {
+		return nil
+	}
+
+	passwordHash, err := s.hasher.Hash(password)
+	if err != nil {
+		return fmt.Errorf("failed to rehash password: %w", err)
+	}
+
+	if err := user.RehashPassword(passwordHash); err != nil {
+		return err
+	}
+
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return fmt.Errorf("failed to update password hash: %w", err)
+	}
+
+	return nil
+}
+
+// verifyDummyPassword spends as long as a real password check against a hash with current settings
+func (s *AuthService) verifyDummyPassword(password string) {
+	s.dummyHashOnce.Do(func() {
+		s.dummyHash, _ = s.hasher.Hash("dummy password for unknown accounts")
+	})
+	_ = s.hasher.Verify(s.dummyHash, password)
+}
+
+// revokeReusedFamily revokes a token family after reuse was detected
+func (s *AuthService) revokeReusedFamily(ctx context.Context, familyID uuid.UUID) error {
+	if err := s.refreshTokens.RevokeFamily(ctx, familyID, time.Now()); err != nil {
+		return fmt.Errorf("failed to revoke reused refresh token family: %w", err)
+	}
+	return domain.ErrRefreshTokenReused
+}
@@ -0,0 +1,206 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/domains/users/domain"
+	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/domains/users/infrastructure/memory"
+	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/platform/mailer"
+	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/platform/ratelimit"
+)
+
+// syncRunner runs background work inline and keeps its errors
+type syncRunner struct {
+	errs []error
+}
+
+func (r *syncRunner) Go(ctx context.Context, name string, fn func(ctx context.Context) error) {
+	if err := fn(ctx); err != nil {
+		r.errs = append(r.errs, err)
+	}
+}
+
+// inlineUnitOfWork runs fn without a transaction
+type inlineUnitOfWork struct{}
+
+func (inlineUnitOfWork) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+// resetTokenStore keeps reset tokens in memory
+type resetTokenStore struct {
+	saved []*domain.PasswordResetToken
+}
+
+func (s *resetTokenStore) Save(ctx context.Context, token *domain.PasswordResetToken) error {
+	s.saved = append(s.saved, token)
+	return nil
+}
+
+func (s *resetTokenStore) FindByHash(ctx context.Context, tokenHash string) (*domain.PasswordResetToken, error) {
+	return nil, domain.ErrPasswordResetTokenNotFound
+}
+
+func (s *resetTokenStore) MarkUsed(ctx context.Context, id uuid.UUID, at time.Time) error {
+	return nil
+}
+
+func (s *resetTokenStore) InvalidateAllForUser(ctx context.Context, userID uuid.UUID, at time.Time) error {
+	return nil
+}
+
+// failingMailer counts messages and fails to send them
+type failingMailer struct {
+	sent int
+}
+
+func (m *failingMailer) Send(ctx context.Context, msg mailer.Message) error {
+	m.sent++
+	return errors.New("smtp unavailable")
+}
+
+type forgotPasswordFixture struct {
+	service *AuthService
+	tokens  *resetTokenStore
+	mail    *failingMailer
+	runner  *syncRunner
+}
+
+func newForgotPasswordFixture(t *testing.T, limiters ResetLimiters) *forgotPasswordFixture {
+	t.Helper()
+
+	users := memory.NewUserRepository()
+	email, _ := domain.NewEmail("known@example.com")
+	user, err := domain.NewUser(email, "Known User", "hash")
+	if err != nil {
+		t.Fatalf("NewUser() error = %v", err)
+	}
+	if err := users.Save(context.Background(), user); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	if limiters.PerClient == nil {
+		limiters.PerClient = ratelimit.NewFailureLimiter(0, time.Hour)
+	}
+	if limiters.PerEmail == nil {
+		limiters.PerEmail = ratelimit.NewFailureLimiter(0, time.Hour)
+	}
+
+	f := &forgotPasswordFixture{
+		tokens: &resetTokenStore{},
+		mail:   &failingMailer{},
+		runner: &syncRunner{},
+	}
+	f.service = &AuthService{
+		userRepo:      users,
+		resetTokens:   f.tokens,
+		uow:           inlineUnitOfWork{},
+		mailer:        f.mail,
+		background:    f.runner,
+		resetLimiters: limiters,
+		settings: AuthSettings{
+			PasswordResetTTL: time.Hour,
+			PasswordResetURL: "http://localhost/reset",
+		},
+	}
+	return f
+}
+
+func TestForgotPasswordHidesRegisteredEmails(t *testing.T) {
+	f := newForgotPasswordFixture(t, ResetLimiters{})
+	ctx := context.Background()
+
+	knownErr := f.service.ForgotPassword(ctx, ForgotPasswordDTO{Email: "known@example.com", ClientIP: "192.0.2.1"})
+	unknownErr := f.service.ForgotPassword(ctx, ForgotPasswordDTO{Email: "unknown@example.com", ClientIP: "192.0.2.1"})
+
+	if knownErr != nil {
+		t.Errorf("ForgotPassword(known) = %v, want nil", knownErr)
+	}
+	if unknownErr != nil {
+		t.Errorf("ForgotPassword(unknown) = %v, want nil", unknownErr)
+	}
+
+	// The mail failure only reaches the background runner
+	if f.mail.sent != 1 {
+		t.Errorf("emails sent = %d, want 1", f.mail.sent)
+	}
+	if len(f.tokens.saved) != 1 {
+		t.Errorf("reset tokens saved = %d, want 1", len(f.tokens.saved))
+	}
+	if len(f.runner.errs) != 1 {
+		t.Errorf("background errors = %v, want the mail failure only", f.runner.errs)
+	}
+}
+
+func TestForgotPasswordRejectsInvalidEmail(t *testing.T) {
+	f := newForgotPasswordFixture(t, ResetLimiters{})
+
+	err := f.service.ForgotPassword(context.Background(), ForgotPasswordDTO{Email: "not-an-email"})
+	if !errors.Is(err, domain.ErrInvalidEmail) {
+		t.Errorf("ForgotPassword() = %v, want %v", err, domain.ErrInvalidEmail)
+	}
+}
+
+func TestForgotPasswordThrottling(t *testing.T) {
+	tests := []struct {
+		name     string
+		limiters ResetLimiters
+		requests []ForgotPasswordDTO
+	}{
+		{
+			name:     "per email, known",
+			limiters: ResetLimiters{PerEmail: ratelimit.NewFailureLimiter(2, time.Hour)},
+			requests: []ForgotPasswordDTO{
+				{Email: "known@example.com", ClientIP: "192.0.2.1"},
+				{Email: "KNOWN@example.com", ClientIP: "192.0.2.2"},
+				{Email: "known@example.com", ClientIP: "192.0.2.3"},
+			},
+		},
+		{
+			name:     "per email, unknown",
+			limiters: ResetLimiters{PerEmail: ratelimit.NewFailureLimiter(2, time.Hour)},
+			requests: []ForgotPasswordDTO{
+				{Email: "unknown@example.com", ClientIP: "192.0.2.1"},
+				{Email: "UNKNOWN@example.com", ClientIP: "192.0.2.2"},
+				{Email: "unknown@example.com", ClientIP: "192.0.2.3"},
+			},
+		},
+		{
+			name:     "per client",
+			limiters: ResetLimiters{PerClient: ratelimit.NewFailureLimiter(2, time.Hour)},
+			requests: []ForgotPasswordDTO{
+				{Email: "known@example.com", ClientIP: "192.0.2.1"},
+				{Email: "unknown@example.com", ClientIP: "192.0.2.1"},
+				{Email: "other@example.com", ClientIP: "192.0.2.1"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newForgotPasswordFixture(t, tt.limiters)
+			ctx := context.Background()
+
+			last := len(tt.requests) - 1
+			for i, dto := range tt.requests[:last] {
+				if err := f.service.ForgotPassword(ctx, dto); err != nil {
+					t.Fatalf("request %d: ForgotPassword() = %v, want nil", i, err)
+				}
+			}
+
+			err := f.service.ForgotPassword(ctx, tt.requests[last])
+			if !errors.Is(err, domain.ErrTooManyResetRequests) {
+				t.Fatalf("ForgotPassword() = %v, want %v", err, domain.ErrTooManyResetRequests)
+			}
+			var lockout *domain.LockoutError
+			if !errors.As(err, &lockout) || lockout.RetryAfter <= 0 {
+				t.Errorf("ForgotPassword() = %v, want a LockoutError with RetryAfter", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,13 @@
+package application
+
+import "context"
+
+/*
+BackgroundRunner is the port used to run work after a use case has returned.
+It is implemented outside the domain (e.g. by the platform background Runner).
+The work must not depend on the caller's cancellation, and its errors are only
+reported by the runner, never to the caller.
+*/
+type BackgroundRunner interface {
+	Go(ctx context.Context, name string, fn func(ctx context.Context) error)
+}
@@ -21,25 +21,36 @@ type CreateUserDTO struct {
 
 // UpdateUserDTO represents the input data for updating a user
 type UpdateUserDTO struct {
-	Name  string `json:"name"`
-	Email string `json:"email"`
+	Name            string `json:"name"`
+	Email           string `json:"email"`
+	ExpectedVersion *int   `json:"-"` // Optional; the update fails with ErrConcurrentModification if the user has another version
 }
 
 // ChangePasswordDTO represents the input data for changing a user's password
 type ChangePasswordDTO struct {
 	OldPassword string `json:"old_password"`
 	NewPassword string `json:"new_password"`
+	ClientIP    string `json:"-"` // Used to throttle failed attempts
+}
+
+// ChangeRoleDTO represents the input data for changing a user's role
+type ChangeRoleDTO struct {
+	Role string `json:"role"`
 }
 
 // UserResponseDTO represents the output data for a user
 // This is what gets returned to clients (handlers, APIs)
 type UserResponseDTO struct {
-	ID        uuid.UUID `json:"id"`
-	Email     string    `json:"email"`
-	Name      string    `json:"name"`
-	IsActive  bool      `json:"is_active"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID              uuid.UUID  `json:"id"`
+	Email           string     `json:"email"`
+	Name            string     `json:"name"`
+	Role            string     `json:"role"`
+	IsActive        bool       `json:"is_active"`
+	EmailVerifiedAt *time.Time `json:"email_verified_at,omitempty"`
+	LockedUntil     *time.Time `json:"locked_until,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+	Version         int        `json:"version"`
 }
 
 // UserListResponseDTO represents a paginated list of users
@@ -50,3 +61,149 @@ type UserListResponseDTO struct {
 	Offset  int               `json:"offset"`
 	HasMore bool              `json:"has_more"`
 }
+
+// AuditLogQueryDTO represents the filters and pagination for reading a user's audit log
+type AuditLogQueryDTO struct {
+	Action  string     `json:"action"`   // Optional; only entries with this action
+	ActorID *uuid.UUID `json:"actor_id"` // Optional; only changes made by this user
+	Field   string     `json:"field"`    // Optional; only entries that changed this field
+	From    *time.Time `json:"from"`     // Optional; only entries made at or after this time
+	To      *time.Time `json:"to"`       // Optional; only entries made before this time
+	Limit   int        `json:"limit"`
+	Offset  int        `json:"offset"`
+}
+
+// FieldChangeDTO represents the value of a field before and after a change
+type FieldChangeDTO struct {
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+// AuditEntryDTO represents one audit log entry
+type AuditEntryDTO struct {
+	ID           uuid.UUID                 `json:"id"`
+	ActorID      *uuid.UUID                `json:"actor_id"`
+	Action       string                    `json:"action"`
+	TargetUserID uuid.UUID                 `json:"target_user_id"`
+	Changes      map[string]FieldChangeDTO `json:"changes"`
+	RequestID    string                    `json:"request_id,omitempty"`
+	IPAddress    string                    `json:"ip_address,omitempty"`
+	CreatedAt    time.Time                 `json:"created_at"`
+}
+
+// AuditLogResponseDTO represents a paginated list of audit log entries, newest first
+type AuditLogResponseDTO struct {
+	Entries []AuditEntryDTO `json:"entries"`
+	Total   int64           `json:"total"`
+	Limit   int             `json:"limit"`
+	Offset  int             `json:"offset"`
+	HasMore bool            `json:"has_more"`
+}
+
+// LoginDTO represents the credentials submitted to log in
+type LoginDTO struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+	ClientIP string `json:"-"` // Used to throttle failed attempts
+}
+
+// RefreshTokenDTO represents a refresh token submitted for rotation or revocation
+type RefreshTokenDTO struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+// TokenResponseDTO represents the tokens issued after a successful login or refresh
+type TokenResponseDTO struct {
+	AccessToken           string    `json:"access_token"`
+	TokenType             string    `json:"token_type"`
+	ExpiresAt             time.Time `json:"expires_at"`
+	RefreshToken          string    `json:"refresh_token"`
+	RefreshTokenExpiresAt time.Time `json:"refresh_token_expires_at"`
+}
+
+// ForgotPasswordDTO represents a request to email a password reset link
+type ForgotPasswordDTO struct {
+	Email    string `json:"email"`
+	ClientIP string `json:"-"` // Used to throttle requests
+}
+
+// ResetPasswordDTO represents the input data for resetting a password with an emailed token
+type ResetPasswordDTO struct {
+	Token       string `json:"token"`
+	NewPassword string `json:"new_password"`
+}
+
+// VerifyEmailDTO represents the input for verifying an email address
+type VerifyEmailDTO struct {
+	Token string `json:"token"`
+}
+
+// ResendVerificationDTO represents the input for resending a verification email
+type ResendVerificationDTO struct {
+	Email string `json:"email"`
+}
+
+// LoginResultDTO represents the outcome of the password step of a login.
+// Exactly one of Tokens (login complete) or MFAChallenge (a second factor is required) is set.
+type LoginResultDTO struct {
+	Tokens       *TokenResponseDTO
+	MFAChallenge *MFAChallengeDTO
+}
+
+// MFAChallengeDTO represents the short-lived token that must be completed with a code
+type MFAChallengeDTO struct {
+	MFAToken  string    `json:"mfa_token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// MFALoginDTO represents the second step of a two-factor login
+type MFALoginDTO struct {
+	MFAToken string `json:"mfa_token"`
+	Code     string `json:"code"` // TOTP code or recovery code
+	ClientIP string `json:"-"`    // Used to throttle failed attempts
+}
+
+// TOTPEnrollmentDTO represents a started TOTP enrolment
+type TOTPEnrollmentDTO struct {
+	Secret     string `json:"secret"`
+	OTPAuthURI string `json:"otpauth_uri"`
+}
+
+// ConfirmTOTPDTO represents the code that confirms a TOTP enrolment
+type ConfirmTOTPDTO struct {
+	Code string `json:"code"`
+}
+
+// RecoveryCodesDTO represents newly generated recovery codes, shown to the user once
+type RecoveryCodesDTO struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// CreateAPIKeyDTO represents the input data for creating an API key
+type CreateAPIKeyDTO struct {
+	Name      string     `json:"name"`
+	Scopes    []string   `json:"scopes"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"` // Optional; the key never expires if nil
+}
+
+// APIKeyDTO represents an API key without its secret value
+type APIKeyDTO struct {
+	ID         uuid.UUID  `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// CreatedAPIKeyDTO represents a newly created API key, whose Key is shown to the user once
+type CreatedAPIKeyDTO struct {
+	APIKeyDTO
+	Key string `json:"key"`
+}
+
+// APIKeyListResponseDTO represents the caller's active API keys
+type APIKeyListResponseDTO struct {
+	APIKeys []APIKeyDTO `json:"api_keys"`
+}
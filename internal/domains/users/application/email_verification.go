@@ -0,0 +1,143 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/domains/users/domain"
+	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/platform/events"
+	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/platform/mailer"
+	"github.com/google/uuid"
+)
+
+/*
+EmailVerifier issues single-use email verification tokens and emails the
+verification link to the user. It is shared by the use cases that (re)send
+verification emails and by the one that consumes the token.
+Links for new and changed addresses are sent by HandleUserEvent once the change
+has been committed.
+*/
+type EmailVerifier struct {
+	users  domain.UserRepository
+	tokens domain.EmailVerificationTokenRepository
+	mailer mailer.Mailer
+	ttl    time.Duration
+	url    string
+}
+
+/*
+NewEmailVerifier creates a new EmailVerifier instance.
+Requires a UserRepository to load the users named by events, an
+EmailVerificationTokenRepository to store token hashes, a Mailer to deliver the
+link, the token lifetime, and the base URL of the verification page (the token
+is appended as ?token=).
+*/
+func NewEmailVerifier(users domain.UserRepository, tokens domain.EmailVerificationTokenRepository, mail mailer.Mailer, ttl time.Duration, url string) *EmailVerifier {
+	return &EmailVerifier{
+		users:  users,
+		tokens: tokens,
+		mailer: mail,
+		ttl:    ttl,
+		url:    url,
+	}
+}
+
+/*
+SendVerification emails a new verification link to the user.
+Earlier tokens of the user are invalidated so only the latest link works.
+*/
+func (v *EmailVerifier) SendVerification(ctx context.Context, user *domain.User) error {
+	if err := v.tokens.InvalidateAllForUser(ctx, user.ID, time.Now()); err != nil {
+		return fmt.Errorf("failed to invalidate previous verification tokens: %w", err)
+	}
+
+	plainToken, tokenHash, err := generateOpaqueToken()
+	if err != nil {
+		return fmt.Errorf("failed to generate verification token: %w", err)
+	}
+
+	token, err := domain.NewEmailVerificationToken(user.ID, tokenHash, v.ttl)
+	if err != nil {
+		return fmt.Errorf("failed to create verification token entity: %w", err)
+	}
+
+	if err := v.tokens.Save(ctx, token); err != nil {
+		return fmt.Errorf("failed to save verification token: %w", err)
+	}
+
+	msg := mailer.Message{
+		To:      user.Email.Value(),
+		Subject: "Verify your email address",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nPlease confirm your email address by opening the link below:\n\n%s?token=%s\n\nThe link expires in %s. If you did not create an account, you can ignore this email.\n",
+			user.Name, v.url, plainToken, v.ttl,
+		),
+	}
+	if err := v.mailer.Send(ctx, msg); err != nil {
+		return fmt.Errorf("failed to send verification email: %w", err)
+	}
+
+	return nil
+}
+
+/*
+HandleUserEvent emails a verification link when a user registers or changes
+their email address. Subscribe it to EventUserRegistered and EventUserEmailChanged.
+It runs after the change has been committed, so a failure is only logged by the
+dispatcher and never fails the request; the user can ask for a new link with
+ResendVerificationEmail.
+*/
+func (v *EmailVerifier) HandleUserEvent(ctx context.Context, event events.Event) error {
+	var userID uuid.UUID
+	switch e := event.(type) {
+	case domain.UserRegistered:
+		userID = e.UserID
+	case domain.UserEmailChanged:
+		userID = e.UserID
+	default:
+		return nil
+	}
+
+	user, err := v.users.FindByID(ctx, userID)
+	if err != nil {
+		// The user may have been deleted in the meantime
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return nil
+		}
+		return fmt.Errorf("failed to find user: %w", err)
+	}
+
+	if user.IsEmailVerified() {
+		return nil
+	}
+
+	return v.SendVerification(ctx, user)
+}
+
+/*
+consume looks up a verification token by its plain value and marks it as used.
+Returns ErrInvalidVerificationToken for unknown, used or expired tokens.
+*/
+func (v *EmailVerifier) consume(ctx context.Context, plainToken string) (*domain.EmailVerificationToken, error) {
+	token, err := v.tokens.FindByHash(ctx, hashOpaqueToken(plainToken))
+	if err != nil {
+		if errors.Is(err, domain.ErrEmailVerificationTokenNotFound) {
+			return nil, domain.ErrInvalidVerificationToken
+		}
+		return nil, fmt.Errorf("failed to find verification token: %w", err)
+	}
+
+	now := time.Now()
+
+	if !token.IsUsable(now) {
+		return nil, domain.ErrInvalidVerificationToken
+	}
+
+	if err := v.tokens.MarkUsed(ctx, token.ID, now); err != nil {
+		return nil, err
+	}
+
+	return token, nil
+}
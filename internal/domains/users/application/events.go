@@ -0,0 +1,35 @@
+package application
+
+import (
+	"context"
+
+	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/domains/users/domain"
+	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/platform/events"
+)
+
+/*
+EventPublisher is the port used to publish the domain events recorded by aggregates.
+It is implemented outside the domain (e.g. by the platform events Dispatcher).
+Publish is only called once the changes that raised the events are persisted,
+so subscribers never see events of a change that was rolled back.
+*/
+type EventPublisher interface {
+	Publish(ctx context.Context, events ...events.Event)
+}
+
+/*
+publishEvents publishes and clears the events recorded by the user.
+Call it after the user's changes have been committed.
+*/
+func publishEvents(ctx context.Context, publisher EventPublisher, user *domain.User) {
+	recorded := user.PullEvents()
+	if len(recorded) == 0 {
+		return
+	}
+
+	published := make([]events.Event, len(recorded))
+	for i, event := range recorded {
+		published[i] = event
+	}
+	publisher.Publish(ctx, published...)
+}
@@ -0,0 +1,151 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/domains/users/domain"
+)
+
+/*
+maxAttemptUpdateRetries bounds how often recording an attempt is retried when
+the account is modified concurrently (e.g. by parallel logins).
+*/
+const maxAttemptUpdateRetries = 3
+
+/*
+AttemptLimiter is the port used to throttle attempts per key, such as failed
+logins per client IP or password reset requests per email.
+It is implemented outside the domain (e.g. by the platform in-memory FailureLimiter).
+*/
+type AttemptLimiter interface {
+	Allow(key string, now time.Time) (time.Duration, bool)
+	RecordFailure(key string, now time.Time)
+}
+
+/*
+LoginGuard protects password and second-factor checks against brute force.
+It tracks failures in two ways:
+  - Per user, persisted on the account: after the LockoutPolicy threshold the
+    account is locked for an escalating period
+  - Per client IP, through the AttemptLimiter: this also covers guesses against
+    unknown emails and spread over many accounts
+*/
+type LoginGuard struct {
+	userRepo domain.UserRepository
+	limiter  AttemptLimiter
+	policy   domain.LockoutPolicy
+}
+
+/*
+NewLoginGuard creates a new LoginGuard instance.
+Requires a UserRepository to persist per-user failures, an AttemptLimiter for
+per-client throttling, and the LockoutPolicy.
+*/
+func NewLoginGuard(userRepo domain.UserRepository, limiter AttemptLimiter, policy domain.LockoutPolicy) *LoginGuard {
+	return &LoginGuard{
+		userRepo: userRepo,
+		limiter:  limiter,
+		policy:   policy,
+	}
+}
+
+/*
+checkClient returns ErrTooManyLoginAttempts (as a LockoutError) if the client IP
+has failed too often recently. Requests without a known IP are not throttled.
+*/
+func (g *LoginGuard) checkClient(clientIP string, now time.Time) error {
+	if clientIP == "" {
+		return nil
+	}
+
+	if retryAfter, ok := g.limiter.Allow(clientIP, now); !ok {
+		return &domain.LockoutError{Err: domain.ErrTooManyLoginAttempts, RetryAfter: retryAfter}
+	}
+
+	return nil
+}
+
+/*
+checkUser returns ErrAccountLocked (as a LockoutError) if the account is locked.
+*/
+func (g *LoginGuard) checkUser(user *domain.User, now time.Time) error {
+	if user.IsLocked(now) {
+		return &domain.LockoutError{Err: domain.ErrAccountLocked, RetryAfter: user.LockedUntil.Sub(now)}
+	}
+
+	return nil
+}
+
+/*
+recordClientFailure counts a failed attempt from a client that did not match an account.
+*/
+func (g *LoginGuard) recordClientFailure(clientIP string, now time.Time) {
+	if clientIP != "" {
+		g.limiter.RecordFailure(clientIP, now)
+	}
+}
+
+/*
+recordFailure counts a failed attempt against both the account and the client,
+locking the account once the policy threshold is reached.
+*/
+func (g *LoginGuard) recordFailure(ctx context.Context, user *domain.User, clientIP string, now time.Time) error {
+	g.recordClientFailure(clientIP, now)
+
+	if !g.policy.Enabled() {
+		return nil
+	}
+
+	err := g.updateUser(ctx, user, func(u *domain.User) bool {
+		u.RecordFailedLogin(g.policy, now)
+		return true
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record failed login: %w", err)
+	}
+
+	return nil
+}
+
+/*
+recordSuccess clears the account's failed attempts after a complete, successful login.
+*/
+func (g *LoginGuard) recordSuccess(ctx context.Context, user *domain.User) error {
+	err := g.updateUser(ctx, user, func(u *domain.User) bool {
+		return u.RecordSuccessfulLogin()
+	})
+	if err != nil {
+		return fmt.Errorf("failed to reset failed logins: %w", err)
+	}
+
+	return nil
+}
+
+/*
+updateUser applies change to the user and persists it if change reports a modification.
+Failed and successful attempts must be counted even when the account was
+modified concurrently, so on ErrConcurrentModification the user is reloaded
+and the change reapplied, up to maxAttemptUpdateRetries times.
+On success *user holds the persisted state.
+*/
+func (g *LoginGuard) updateUser(ctx context.Context, user *domain.User, change func(*domain.User) bool) error {
+	for retry := 0; ; retry++ {
+		if !change(user) {
+			return nil
+		}
+
+		err := g.userRepo.Update(ctx, user)
+		if !errors.Is(err, domain.ErrConcurrentModification) || retry == maxAttemptUpdateRetries {
+			return err
+		}
+
+		current, err := g.userRepo.FindByIDIncludingInactive(ctx, user.ID)
+		if err != nil {
+			return err
+		}
+		*user = *current
+	}
+}
@@ -0,0 +1,177 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/domains/users/domain"
+	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/domains/users/infrastructure/memory"
+	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/platform/ratelimit"
+)
+
+var testLockoutPolicy = domain.LockoutPolicy{Threshold: 3, BaseDuration: time.Minute, MaxDuration: time.Hour}
+
+// countingUserRepository counts updates and can make every update conflict
+type countingUserRepository struct {
+	*memory.UserRepository
+	updates  int
+	conflict bool
+}
+
+func (r *countingUserRepository) Update(ctx context.Context, user *domain.User) error {
+	r.updates++
+	if r.conflict {
+		return domain.ErrConcurrentModification
+	}
+	return r.UserRepository.Update(ctx, user)
+}
+
+// newGuardFixture stores a user and returns a guard over a counting repository
+func newGuardFixture(t *testing.T, policy domain.LockoutPolicy) (*LoginGuard, *countingUserRepository, *domain.User) {
+	t.Helper()
+
+	repo := &countingUserRepository{UserRepository: memory.NewUserRepository()}
+	email, _ := domain.NewEmail("guarded@example.com")
+	user, err := domain.NewUser(email, "Guarded User", "hash")
+	if err != nil {
+		t.Fatalf("NewUser() error = %v", err)
+	}
+	if err := repo.Save(context.Background(), user); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	guard := NewLoginGuard(repo, ratelimit.NewFailureLimiter(2, time.Minute), policy)
+	return guard, repo, user
+}
+
+func TestLoginGuardRecordFailureRetriesConcurrentModification(t *testing.T) {
+	ctx := context.Background()
+	guard, repo, user := newGuardFixture(t, testLockoutPolicy)
+
+	// Another request updates the account after this one loaded it
+	other, err := repo.FindByIDIncludingInactive(ctx, user.ID)
+	if err != nil {
+		t.Fatalf("FindByIDIncludingInactive() error = %v", err)
+	}
+	if err := other.UpdateProfile("Renamed", other.Email); err != nil {
+		t.Fatalf("UpdateProfile() error = %v", err)
+	}
+	if err := repo.Update(ctx, other); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	repo.updates = 0
+
+	if err := guard.recordFailure(ctx, user, "", time.Now()); err != nil {
+		t.Fatalf("recordFailure() error = %v", err)
+	}
+
+	stored, err := repo.FindByIDIncludingInactive(ctx, user.ID)
+	if err != nil {
+		t.Fatalf("FindByIDIncludingInactive() error = %v", err)
+	}
+	if stored.FailedLoginAttempts != 1 || stored.Name != "Renamed" {
+		t.Errorf("stored attempts, name = %d, %q, want 1, %q", stored.FailedLoginAttempts, stored.Name, "Renamed")
+	}
+	if repo.updates != 2 {
+		t.Errorf("updates = %d, want 2 (one conflict, one retry)", repo.updates)
+	}
+	if user.Version != stored.Version || user.Name != "Renamed" {
+		t.Errorf("user = version %d, name %q, want the persisted state", user.Version, user.Name)
+	}
+}
+
+func TestLoginGuardGivesUpAfterRetries(t *testing.T) {
+	guard, repo, user := newGuardFixture(t, testLockoutPolicy)
+	repo.conflict = true
+
+	err := guard.recordFailure(context.Background(), user, "", time.Now())
+	if !errors.Is(err, domain.ErrConcurrentModification) {
+		t.Errorf("recordFailure() error = %v, want %v", err, domain.ErrConcurrentModification)
+	}
+	if want := maxAttemptUpdateRetries + 1; repo.updates != want {
+		t.Errorf("updates = %d, want %d", repo.updates, want)
+	}
+}
+
+func TestLoginGuardLocksAccount(t *testing.T) {
+	ctx := context.Background()
+	guard, _, user := newGuardFixture(t, testLockoutPolicy)
+	now := time.Now()
+
+	for i := 0; i < testLockoutPolicy.Threshold; i++ {
+		if err := guard.checkUser(user, now); err != nil {
+			t.Fatalf("checkUser() after %d failures = %v, want nil", i, err)
+		}
+		if err := guard.recordFailure(ctx, user, "", now); err != nil {
+			t.Fatalf("recordFailure() error = %v", err)
+		}
+	}
+
+	err := guard.checkUser(user, now)
+	var lockout *domain.LockoutError
+	if !errors.Is(err, domain.ErrAccountLocked) || !errors.As(err, &lockout) || lockout.RetryAfter != time.Minute {
+		t.Fatalf("checkUser() = %v, want ErrAccountLocked retrying after 1m", err)
+	}
+
+	if err := guard.recordSuccess(ctx, user); err != nil {
+		t.Fatalf("recordSuccess() error = %v", err)
+	}
+	if err := guard.checkUser(user, now); err != nil {
+		t.Errorf("checkUser() after success = %v, want nil", err)
+	}
+}
+
+func TestLoginGuardRecordSuccessSkipsNeedlessWrite(t *testing.T) {
+	guard, repo, user := newGuardFixture(t, testLockoutPolicy)
+
+	if err := guard.recordSuccess(context.Background(), user); err != nil {
+		t.Fatalf("recordSuccess() error = %v", err)
+	}
+	if repo.updates != 0 {
+		t.Errorf("updates = %d, want 0", repo.updates)
+	}
+}
+
+func TestLoginGuardDisabledPolicy(t *testing.T) {
+	guard, repo, user := newGuardFixture(t, domain.LockoutPolicy{})
+	now := time.Now()
+
+	for i := 0; i < 10; i++ {
+		if err := guard.recordFailure(context.Background(), user, "", now); err != nil {
+			t.Fatalf("recordFailure() error = %v", err)
+		}
+	}
+
+	if repo.updates != 0 || user.IsLocked(now) {
+		t.Errorf("updates = %d, locked = %v, want no lockout", repo.updates, user.IsLocked(now))
+	}
+}
+
+func TestLoginGuardThrottlesClients(t *testing.T) {
+	ctx := context.Background()
+	guard, _, user := newGuardFixture(t, domain.LockoutPolicy{})
+	now := time.Now()
+
+	// Failures against unknown and known accounts count alike
+	guard.recordClientFailure("203.0.113.7", now)
+	if err := guard.recordFailure(ctx, user, "203.0.113.7", now); err != nil {
+		t.Fatalf("recordFailure() error = %v", err)
+	}
+
+	err := guard.checkClient("203.0.113.7", now)
+	if !errors.Is(err, domain.ErrTooManyLoginAttempts) {
+		t.Errorf("checkClient() = %v, want %v", err, domain.ErrTooManyLoginAttempts)
+	}
+	if err := guard.checkClient("198.51.100.1", now); err != nil {
+		t.Errorf("checkClient() for another client = %v, want nil", err)
+	}
+
+	// Requests without a known IP are not throttled
+	guard.recordClientFailure("", now)
+	guard.recordClientFailure("", now)
+	if err := guard.checkClient("", now); err != nil {
+		t.Errorf("checkClient() without IP = %v, want nil", err)
+	}
+}
@@ -0,0 +1,245 @@
+package application
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/domains/users/domain"
+	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/platform/totp"
+	"github.com/google/uuid"
+)
+
+// recoveryCodeCount is the number of recovery codes generated on enrolment
+const recoveryCodeCount = 10
+
+// recoveryCodeEncoding renders recovery codes in lowercase base32 without padding
+var recoveryCodeEncoding = base32.NewEncoding("abcdefghijklmnopqrstuvwxyz234567").WithPadding(base32.NoPadding)
+
+/*
+SecretCipher is the port used to encrypt secrets before they are stored.
+It is implemented outside the domain (e.g. by the platform AES-GCM cipher).
+*/
+type SecretCipher interface {
+	Encrypt(plaintext string) (string, error)
+	Decrypt(ciphertext string) (string, error)
+}
+
+/*
+TOTPAuthenticator manages TOTP two-factor authentication: enrolment, confirmation,
+recovery codes, and verification of codes during login.
+TOTP secrets are encrypted with the SecretCipher before they reach the repository,
+and recovery codes are stored hashed.
+*/
+type TOTPAuthenticator struct {
+	factors       domain.TOTPFactorRepository
+	recoveryCodes domain.RecoveryCodeRepository
+	cipher        SecretCipher
+	issuer        string
+}
+
+/*
+NewTOTPAuthenticator creates a new TOTPAuthenticator instance.
+Requires repositories for TOTP factors and recovery codes, a SecretCipher to
+encrypt secrets at rest, and the issuer name shown in authenticator apps.
+*/
+func NewTOTPAuthenticator(
+	factors domain.TOTPFactorRepository,
+	recoveryCodes domain.RecoveryCodeRepository,
+	cipher SecretCipher,
+	issuer string,
+) *TOTPAuthenticator {
+	return &TOTPAuthenticator{
+		factors:       factors,
+		recoveryCodes: recoveryCodes,
+		cipher:        cipher,
+		issuer:        issuer,
+	}
+}
+
+/*
+enroll starts (or restarts) TOTP enrolment for the user with a new secret.
+Returns ErrMFAAlreadyEnabled if the user already has a confirmed factor.
+*/
+func (a *TOTPAuthenticator) enroll(ctx context.Context, user *domain.User) (*TOTPEnrollmentDTO, error) {
+	existing, err := a.factors.FindByUserID(ctx, user.ID)
+	if err != nil && !errors.Is(err, domain.ErrMFANotEnrolled) {
+		return nil, fmt.Errorf("failed to find totp factor: %w", err)
+	}
+	if existing != nil && existing.IsConfirmed() {
+		return nil, domain.ErrMFAAlreadyEnabled
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	encryptedSecret, err := a.cipher.Encrypt(secret)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt totp secret: %w", err)
+	}
+
+	factor, err := domain.NewTOTPFactor(user.ID, encryptedSecret)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create totp factor entity: %w", err)
+	}
+
+	if err := a.factors.Save(ctx, factor); err != nil {
+		return nil, fmt.Errorf("failed to save totp factor: %w", err)
+	}
+
+	return &TOTPEnrollmentDTO{
+		Secret:     secret,
+		OTPAuthURI: totp.URI(a.issuer, user.Email.Value(), secret),
+	}, nil
+}
+
+/*
+confirm completes enrolment with a code from the authenticator app and issues a
+fresh set of recovery codes.
+Returns ErrMFANotEnrolled if enrolment was not started, ErrMFAAlreadyEnabled if it
+was already confirmed, and ErrInvalidMFACode if the code is wrong.
+*/
+func (a *TOTPAuthenticator) confirm(ctx context.Context, userID uuid.UUID, code string) (*RecoveryCodesDTO, error) {
+	factor, err := a.factors.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if factor.IsConfirmed() {
+		return nil, domain.ErrMFAAlreadyEnabled
+	}
+
+	step, err := a.checkTOTP(factor, code)
+	if err != nil {
+		return nil, err
+	}
+
+	factor.Confirm(step)
+
+	if err := a.factors.Save(ctx, factor); err != nil {
+		return nil, fmt.Errorf("failed to save totp factor: %w", err)
+	}
+
+	codes, err := a.regenerateRecoveryCodes(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RecoveryCodesDTO{RecoveryCodes: codes}, nil
+}
+
+/*
+isEnabled reports whether the user has a confirmed TOTP factor.
+*/
+func (a *TOTPAuthenticator) isEnabled(ctx context.Context, userID uuid.UUID) (bool, error) {
+	factor, err := a.factors.FindByUserID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, domain.ErrMFANotEnrolled) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to find totp factor: %w", err)
+	}
+
+	return factor.IsConfirmed(), nil
+}
+
+/*
+verify checks a second-factor code for the user during login.
+A six digit code is checked as a TOTP code and may only be used once; anything
+else is checked as a recovery code, which is consumed.
+Returns ErrInvalidMFACode if the code is not accepted.
+*/
+func (a *TOTPAuthenticator) verify(ctx context.Context, userID uuid.UUID, code string) error {
+	code = strings.TrimSpace(code)
+
+	if !isTOTPCode(code) {
+		return a.recoveryCodes.Use(ctx, userID, hashRecoveryCode(code), time.Now())
+	}
+
+	factor, err := a.factors.FindByUserID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, domain.ErrMFANotEnrolled) {
+			return domain.ErrInvalidMFACode
+		}
+		return fmt.Errorf("failed to find totp factor: %w", err)
+	}
+	if !factor.IsConfirmed() {
+		return domain.ErrInvalidMFACode
+	}
+
+	step, err := a.checkTOTP(factor, code)
+	if err != nil {
+		return err
+	}
+
+	return a.factors.MarkStepUsed(ctx, userID, step, time.Now())
+}
+
+// checkTOTP validates a TOTP code against the factor and returns its time step
+func (a *TOTPAuthenticator) checkTOTP(factor *domain.TOTPFactor, code string) (int64, error) {
+	secret, err := a.cipher.Decrypt(factor.EncryptedSecret)
+	if err != nil {
+		return 0, fmt.Errorf("failed to decrypt totp secret: %w", err)
+	}
+
+	step, ok := totp.Validate(secret, strings.TrimSpace(code), time.Now())
+	if !ok || step <= factor.LastUsedStep {
+		return 0, domain.ErrInvalidMFACode
+	}
+
+	return step, nil
+}
+
+// regenerateRecoveryCodes replaces the user's recovery codes and returns the plain values
+func (a *TOTPAuthenticator) regenerateRecoveryCodes(ctx context.Context, userID uuid.UUID) ([]string, error) {
+	plainCodes := make([]string, 0, recoveryCodeCount)
+	codes := make([]*domain.RecoveryCode, 0, recoveryCodeCount)
+
+	for i := 0; i < recoveryCodeCount; i++ {
+		buf := make([]byte, 5)
+		if _, err := rand.Read(buf); err != nil {
+			return nil, fmt.Errorf("failed to generate recovery code: %w", err)
+		}
+
+		encoded := recoveryCodeEncoding.EncodeToString(buf)
+		plain := encoded[:4] + "-" + encoded[4:]
+
+		code, err := domain.NewRecoveryCode(userID, hashRecoveryCode(plain))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create recovery code entity: %w", err)
+		}
+
+		plainCodes = append(plainCodes, plain)
+		codes = append(codes, code)
+	}
+
+	if err := a.recoveryCodes.ReplaceForUser(ctx, userID, codes); err != nil {
+		return nil, fmt.Errorf("failed to save recovery codes: %w", err)
+	}
+
+	return plainCodes, nil
+}
+
+// isTOTPCode reports whether the code looks like a TOTP code (digits only, of the right length)
+func isTOTPCode(code string) bool {
+	if len(code) != totp.Digits {
+		return false
+	}
+	for _, r := range code {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// hashRecoveryCode hashes a recovery code after removing formatting, so "ABCD-EFGH" and "abcdefgh" match
+func hashRecoveryCode(code string) string {
+	normalized := strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+	return hashOpaqueToken(normalized)
+}
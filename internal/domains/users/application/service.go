@@ -4,36 +4,88 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/domains/users/domain"
+	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/platform/auth"
 	"github.com/google/uuid"
-	"golang.org/x/crypto/bcrypt"
 )
 
+/*
+PasswordPolicy is the port used to check new passwords against the configured
+password rules. It is implemented outside the domain (e.g. by the platform
+password policy).
+Validate returns *errors.ValidationErrors naming field for every rule broken;
+personalInfo holds values the password must not contain, such as the user's
+email address and name.
+*/
+type PasswordPolicy interface {
+	Validate(field, password string, personalInfo ...string) error
+}
+
+/*
+PasswordHasher is the port used to hash and verify passwords.
+It is implemented outside the domain (e.g. by the platform password hasher).
+Hashes carry their algorithm and parameters, so Verify accepts hashes made with
+older settings and NeedsRehash reports those that should be upgraded.
+*/
+type PasswordHasher interface {
+	Hash(password string) (string, error)
+	Verify(passwordHash, password string) error
+	NeedsRehash(passwordHash string) bool
+}
+
+/*
+UnitOfWork is the port used to run a use case atomically.
+It is implemented outside the domain (e.g. by the platform database TxManager).
+Repository calls made with the context passed to fn take part in one transaction,
+which is committed if fn returns nil and rolled back otherwise.
+*/
+type UnitOfWork interface {
+	Do(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
 /*
 UserService implements the application use cases for the users domain.
 It orchestrates domain objects and coordinates business workflows.
 This layer depends only on the domain layer (not on infrastructure).
 
 The service:
+  - Authorizes the caller found in the context against the domain access policy
   - Validates input data
   - Coordinates domain entities
   - Defines transaction boundaries
+  - Publishes the domain events recorded by users once their changes are persisted
   - Maps between DTOs and domain entities
-  - Handles password hashing (application concern, not domain)
+  - Hashes passwords through the PasswordHasher port (application concern, not domain)
 */
 type UserService struct {
-	userRepo domain.UserRepository
+	userRepo  domain.UserRepository
+	auditLog  domain.AuditLogRepository
+	uow       UnitOfWork
+	events    EventPublisher
+	guard     *LoginGuard
+	passwords PasswordPolicy
+	hasher    PasswordHasher
 }
 
 /*
 NewUserService creates a new UserService instance.
-Requires a UserRepository implementation (provided by infrastructure layer).
+Requires a UserRepository implementation (provided by infrastructure layer),
+an AuditLogRepository to read the changes recorded by it, a UnitOfWork for transaction boundaries, an EventPublisher for domain events,
+a LoginGuard to throttle password guesses, the PasswordPolicy for new passwords,
+and a PasswordHasher.
 This follows dependency injection pattern.
 */
-func NewUserService(userRepo domain.UserRepository) *UserService {
+func NewUserService(userRepo domain.UserRepository, auditLog domain.AuditLogRepository, uow UnitOfWork, events EventPublisher, guard *LoginGuard, passwords PasswordPolicy, hasher PasswordHasher) *UserService {
 	return &UserService{
-		userRepo: userRepo,
+		userRepo:  userRepo,
+		auditLog:  auditLog,
+		uow:       uow,
+		events:    events,
+		guard:     guard,
+		passwords: passwords,
+		hasher:    hasher,
 	}
 }
 
@@ -41,10 +93,10 @@ func NewUserService(userRepo domain.UserRepository) *UserService {
 CreateUser creates a new user account.
 This use case:
  1. Validates input data
- 2. Checks if email already exists
- 3. Hashes the password
- 4. Creates domain entity
- 5. Persists to repository
+ 2. Hashes the password
+ 3. Checks if email already exists and persists the new entity in one transaction
+ 4. Publishes UserRegistered, which emails a verification link to the new
+    address (see EmailVerifier.HandleUserEvent)
 
 Returns the created user or an error if:
   - Email is invalid
@@ -64,17 +116,8 @@ func (s *UserService) CreateUser(ctx context.Context, dto CreateUserDTO) (*UserR
 		return nil, err
 	}
 
-	// Check if email already exists
-	existingUser, err := s.userRepo.FindByEmail(ctx, email)
-	if err != nil && !errors.Is(err, domain.ErrUserNotFound) {
-		return nil, fmt.Errorf("failed to check email existence: %w", err)
-	}
-	if existingUser != nil {
-		return nil, domain.ErrEmailAlreadyExists
-	}
-
 	// Hash password
-	passwordHash, err := s.hashPassword(dto.Password)
+	passwordHash, err := s.hasher.Hash(dto.Password)
 	if err != nil {
 		return nil, fmt.Errorf("failed to hash password: %w", err)
 	}
@@ -85,10 +128,26 @@ func (s *UserService) CreateUser(ctx context.Context, dto CreateUserDTO) (*UserR
 		return nil, fmt.Errorf("failed to create user entity: %w", err)
 	}
 
-	// Persist to repository
-	if err := s.userRepo.Save(ctx, user); err != nil {
-		return nil, fmt.Errorf("failed to save user: %w", err)
+	err = s.uow.Do(ctx, func(ctx context.Context) error {
+		// Check if email already exists
+		existingUser, err := s.userRepo.FindByEmail(ctx, email)
+		if err != nil && !errors.Is(err, domain.ErrUserNotFound) {
+			return fmt.Errorf("failed to check email existence: %w", err)
+		}
+		if existingUser != nil {
+			return domain.ErrEmailAlreadyExists
+		}
+
+		// Persist to repository
+		if err := s.userRepo.Save(ctx, user); err != nil {
+			return fmt.Errorf("failed to save user: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
+	publishEvents(ctx, s.events, user)
 
 	// Map to response DTO
 	return s.toUserResponseDTO(user), nil
@@ -96,9 +155,14 @@ func (s *UserService) CreateUser(ctx context.Context, dto CreateUserDTO) (*UserR
 
 /*
 GetUser retrieves a user by ID.
+Callers may read their own account; reading others requires PermissionUsersRead.
 Returns the user or ErrUserNotFound if not found.
 */
 func (s *UserService) GetUser(ctx context.Context, id uuid.UUID) (*UserResponseDTO, error) {
+	if err := s.authorize(ctx, domain.PermissionUsersRead, id); err != nil {
+		return nil, err
+	}
+
 	user, err := s.userRepo.FindByID(ctx, id)
 	if err != nil {
 		return nil, err
@@ -109,6 +173,7 @@ func (s *UserService) GetUser(ctx context.Context, id uuid.UUID) (*UserResponseD
 
 /*
 GetUserByEmail retrieves a user by email address.
+Callers may look up their own account; looking up others requires PermissionUsersRead.
 Returns the user or ErrUserNotFound if not found.
 */
 func (s *UserService) GetUserByEmail(ctx context.Context, email string) (*UserResponseDTO, error) {
@@ -122,24 +187,33 @@ func (s *UserService) GetUserByEmail(ctx context.Context, email string) (*UserRe
 		return nil, err
 	}
 
+	if err := s.authorize(ctx, domain.PermissionUsersRead, user.ID); err != nil {
+		return nil, err
+	}
+
 	return s.toUserResponseDTO(user), nil
 }
 
 /*
 UpdateUser updates a user's profile information.
 This use case:
- 1. Retrieves the existing user
- 2. Validates new data
+ 0. Authorizes the caller (own account, or PermissionUsersUpdate)
+ 1. Validates new data
+ 2. Retrieves the existing user and checks it has the expected version, if given
  3. Checks if new email conflicts with another user
  4. Updates the domain entity
- 5. Persists changes
+ 5. Persists changes, provided nobody updated the user in the meantime
+ 6. Publishes UserEmailChanged if the email changed, which emails a
+    verification link to the new address
+
+Steps 2 to 5 run in one transaction.
+Returns ErrConcurrentModification if the user does not have the expected version
+or was modified concurrently.
 
 Returns the updated user or an error.
 */
 func (s *UserService) UpdateUser(ctx context.Context, id uuid.UUID, dto UpdateUserDTO) (*UserResponseDTO, error) {
-	// Retrieve existing user
-	user, err := s.userRepo.FindByID(ctx, id)
-	if err != nil {
+	if err := s.authorize(ctx, domain.PermissionUsersUpdate, id); err != nil {
 		return nil, err
 	}
 
@@ -154,26 +228,44 @@ func (s *UserService) UpdateUser(ctx context.Context, id uuid.UUID, dto UpdateUs
 		return nil, err
 	}
 
-	// Check if new email conflicts with another user
-	if newEmail.Value() != user.Email.Value() {
-		existingUser, err := s.userRepo.FindByEmail(ctx, newEmail)
-		if err != nil && !errors.Is(err, domain.ErrUserNotFound) {
-			return nil, fmt.Errorf("failed to check email existence: %w", err)
+	var user *domain.User
+	err = s.uow.Do(ctx, func(ctx context.Context) error {
+		// Retrieve existing user
+		var err error
+		user, err = s.userRepo.FindByID(ctx, id)
+		if err != nil {
+			return err
 		}
-		if existingUser != nil && existingUser.ID != user.ID {
-			return nil, domain.ErrEmailAlreadyExists
+		if err := checkVersion(user, dto.ExpectedVersion); err != nil {
+			return err
 		}
-	}
 
-	// Update domain entity
-	if err := user.UpdateProfile(dto.Name, newEmail); err != nil {
-		return nil, err
-	}
+		// Check if new email conflicts with another user
+		if newEmail.Value() != user.Email.Value() {
+			existingUser, err := s.userRepo.FindByEmail(ctx, newEmail)
+			if err != nil && !errors.Is(err, domain.ErrUserNotFound) {
+				return fmt.Errorf("failed to check email existence: %w", err)
+			}
+			if existingUser != nil && existingUser.ID != user.ID {
+				return domain.ErrEmailAlreadyExists
+			}
+		}
 
-	// Persist changes
-	if err := s.userRepo.Update(ctx, user); err != nil {
-		return nil, fmt.Errorf("failed to update user: %w", err)
+		// Update domain entity
+		if err := user.UpdateProfile(dto.Name, newEmail); err != nil {
+			return err
+		}
+
+		// Persist changes
+		if err := s.userRepo.Update(ctx, user); err != nil {
+			return fmt.Errorf("failed to update user: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
+	publishEvents(ctx, s.events, user)
 
 	return s.toUserResponseDTO(user), nil
 }
@@ -181,91 +273,270 @@ func (s *UserService) UpdateUser(ctx context.Context, id uuid.UUID, dto UpdateUs
 /*
 DeleteUser soft deletes a user account.
 The user record remains in the database but is marked as inactive.
+Callers may delete their own account; deleting others requires PermissionUsersDeactivate.
+If expectedVersion is given, the user is only deleted if it still has that
+version; otherwise ErrConcurrentModification is returned.
+Publishes UserDeactivated.
 */
-func (s *UserService) DeleteUser(ctx context.Context, id uuid.UUID) error {
-	return s.userRepo.Delete(ctx, id)
+func (s *UserService) DeleteUser(ctx context.Context, id uuid.UUID, expectedVersion *int) error {
+	if err := s.authorize(ctx, domain.PermissionUsersDeactivate, id); err != nil {
+		return err
+	}
+
+	var user *domain.User
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		var err error
+		user, err = s.userRepo.FindByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := checkVersion(user, expectedVersion); err != nil {
+			return err
+		}
+
+		user.Deactivate()
+
+		if err := s.userRepo.Update(ctx, user); err != nil {
+			return fmt.Errorf("failed to delete user: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	publishEvents(ctx, s.events, user)
+
+	return nil
 }
 
 /*
-ActivateUser activates a previously deactivated user account.
+ActivateUser activates a previously deactivated user account, restoring soft-deleted users.
+Reactivation is never self-service: it always requires PermissionUsersDeactivate.
+Activating an account that is already active is a no-op.
+Publishes UserReactivated.
 */
 func (s *UserService) ActivateUser(ctx context.Context, id uuid.UUID) (*UserResponseDTO, error) {
-	user, err := s.userRepo.FindByID(ctx, id)
-	if err != nil {
+	if err := s.authorize(ctx, domain.PermissionUsersDeactivate, uuid.Nil); err != nil {
 		return nil, err
 	}
 
-	user.Activate()
+	var user *domain.User
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		var err error
+		user, err = s.userRepo.FindByIDIncludingInactive(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if user.IsActive {
+			return nil
+		}
+
+		user.Activate()
 
-	if err := s.userRepo.Update(ctx, user); err != nil {
-		return nil, fmt.Errorf("failed to activate user: %w", err)
+		if err := s.userRepo.Update(ctx, user); err != nil {
+			return fmt.Errorf("failed to activate user: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
+	publishEvents(ctx, s.events, user)
 
 	return s.toUserResponseDTO(user), nil
 }
 
 /*
 DeactivateUser deactivates a user account.
+Callers may deactivate their own account; deactivating others requires PermissionUsersDeactivate.
+Publishes UserDeactivated.
 */
 func (s *UserService) DeactivateUser(ctx context.Context, id uuid.UUID) (*UserResponseDTO, error) {
-	user, err := s.userRepo.FindByID(ctx, id)
-	if err != nil {
+	if err := s.authorize(ctx, domain.PermissionUsersDeactivate, id); err != nil {
 		return nil, err
 	}
 
-	user.Deactivate()
+	var user *domain.User
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		var err error
+		user, err = s.userRepo.FindByID(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		user.Deactivate()
 
-	if err := s.userRepo.Update(ctx, user); err != nil {
-		return nil, fmt.Errorf("failed to deactivate user: %w", err)
+		if err := s.userRepo.Update(ctx, user); err != nil {
+			return fmt.Errorf("failed to deactivate user: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
+	publishEvents(ctx, s.events, user)
 
 	return s.toUserResponseDTO(user), nil
 }
 
 /*
 ChangePassword changes a user's password.
-Verifies the old password before setting the new one.
+Only the authenticated owner of the account may change it; any other caller
+gets ErrUnauthorized. Verifies the old password before setting the new one;
+wrong old passwords count towards the account lockout like failed logins.
+The failure is recorded outside the transaction, so it persists although the
+use case fails. Publishes UserPasswordChanged.
 */
 func (s *UserService) ChangePassword(ctx context.Context, id uuid.UUID, dto ChangePasswordDTO) error {
+	// Callers may only change their own password
+	principal, ok := auth.PrincipalFromContext(ctx)
+	if !ok || principal.UserID != id || principal.ViaAPIKey() {
+		return domain.ErrUnauthorized
+	}
+
+	now := time.Now()
+
+	// Throttle repeated failures from the same client
+	if err := s.guard.checkClient(dto.ClientIP, now); err != nil {
+		return err
+	}
+
 	// Retrieve user
 	user, err := s.userRepo.FindByID(ctx, id)
 	if err != nil {
 		return err
 	}
 
+	// Refuse guesses while the account is locked
+	if err := s.guard.checkUser(user, now); err != nil {
+		return err
+	}
+
 	// Verify old password
-	if err := s.verifyPassword(user.PasswordHash, dto.OldPassword); err != nil {
+	if err := s.hasher.Verify(user.PasswordHash, dto.OldPassword); err != nil {
+		if err := s.guard.recordFailure(ctx, user, dto.ClientIP, now); err != nil {
+			return err
+		}
 		return domain.ErrInvalidPassword
 	}
 
 	// Validate new password
-	if err := s.validatePassword(dto.NewPassword); err != nil {
+	if err := s.passwords.Validate("new_password", dto.NewPassword, user.Email.Value(), user.Name); err != nil {
 		return err
 	}
 
 	// Hash new password
-	newPasswordHash, err := s.hashPassword(dto.NewPassword)
+	newPasswordHash, err := s.hasher.Hash(dto.NewPassword)
 	if err != nil {
 		return fmt.Errorf("failed to hash password: %w", err)
 	}
 
-	// Update password
-	if err := user.ChangePassword(newPasswordHash); err != nil {
+	err = s.uow.Do(ctx, func(ctx context.Context) error {
+		// Reload the user so a retried transaction starts from the stored state
+		user, err = s.userRepo.FindByID(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		// Update password
+		if err := user.ChangePassword(newPasswordHash); err != nil {
+			return err
+		}
+		user.RecordSuccessfulLogin()
+
+		// Persist changes
+		if err := s.userRepo.Update(ctx, user); err != nil {
+			return fmt.Errorf("failed to update password: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
 		return err
 	}
+	publishEvents(ctx, s.events, user)
 
-	// Persist changes
-	if err := s.userRepo.Update(ctx, user); err != nil {
-		return fmt.Errorf("failed to update password: %w", err)
+	return nil
+}
+
+/*
+ChangeUserRole assigns a new role to a user.
+Requires PermissionUsersManageRoles. The new role is reflected in the user's
+access tokens from their next login or refresh.
+*/
+func (s *UserService) ChangeUserRole(ctx context.Context, id uuid.UUID, dto ChangeRoleDTO) (*UserResponseDTO, error) {
+	if err := s.authorize(ctx, domain.PermissionUsersManageRoles, uuid.Nil); err != nil {
+		return nil, err
 	}
 
-	return nil
+	role, err := domain.ParseRole(dto.Role)
+	if err != nil {
+		return nil, err
+	}
+
+	var user *domain.User
+	err = s.uow.Do(ctx, func(ctx context.Context) error {
+		user, err = s.userRepo.FindByID(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if err := user.ChangeRole(role); err != nil {
+			return err
+		}
+
+		if err := s.userRepo.Update(ctx, user); err != nil {
+			return fmt.Errorf("failed to change user role: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.toUserResponseDTO(user), nil
+}
+
+/*
+UnlockUser lifts a login lockout and clears the user's failed attempts.
+Requires PermissionUsersUnlock.
+*/
+func (s *UserService) UnlockUser(ctx context.Context, id uuid.UUID) (*UserResponseDTO, error) {
+	if err := s.authorize(ctx, domain.PermissionUsersUnlock, uuid.Nil); err != nil {
+		return nil, err
+	}
+
+	var user *domain.User
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		var err error
+		user, err = s.userRepo.FindByID(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		user.Unlock()
+
+		if err := s.userRepo.Update(ctx, user); err != nil {
+			return fmt.Errorf("failed to unlock user: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.toUserResponseDTO(user), nil
 }
 
 /*
 ListUsers retrieves a paginated list of active users.
+Requires PermissionUsersList.
 */
 func (s *UserService) ListUsers(ctx context.Context, limit, offset int) (*UserListResponseDTO, error) {
+	if err := s.authorize(ctx, domain.PermissionUsersList, uuid.Nil); err != nil {
+		return nil, err
+	}
+
 	// Validate pagination parameters
 	if limit <= 0 {
 		limit = 10 // Default limit
@@ -304,8 +575,88 @@ func (s *UserService) ListUsers(ctx context.Context, limit, offset int) (*UserLi
 	}, nil
 }
 
+/*
+ListAuditLog retrieves a paginated, filtered list of the changes made to a user,
+newest first. Deactivated users keep their audit log.
+Requires PermissionUsersReadAudit; there is no self permission, so users
+cannot read their own log unless their role grants it.
+Returns ErrUserNotFound if the user doesn't exist.
+*/
+func (s *UserService) ListAuditLog(ctx context.Context, id uuid.UUID, query AuditLogQueryDTO) (*AuditLogResponseDTO, error) {
+	if err := s.authorize(ctx, domain.PermissionUsersReadAudit, uuid.Nil); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.userRepo.FindByIDIncludingInactive(ctx, id); err != nil {
+		return nil, err
+	}
+
+	// Validate pagination parameters
+	if query.Limit <= 0 {
+		query.Limit = 10 // Default limit
+	}
+	if query.Limit > 100 {
+		query.Limit = 100 // Max limit
+	}
+	if query.Offset < 0 {
+		query.Offset = 0
+	}
+
+	filter := domain.AuditFilter{
+		Action:  query.Action,
+		ActorID: query.ActorID,
+		Field:   query.Field,
+		From:    query.From,
+		To:      query.To,
+		Limit:   query.Limit,
+		Offset:  query.Offset,
+	}
+
+	entries, err := s.auditLog.ListByTargetUser(ctx, id, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list audit log: %w", err)
+	}
+
+	total, err := s.auditLog.CountByTargetUser(ctx, id, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count audit log: %w", err)
+	}
+
+	entryDTOs := make([]AuditEntryDTO, len(entries))
+	for i, entry := range entries {
+		entryDTOs[i] = toAuditEntryDTO(entry)
+	}
+
+	return &AuditLogResponseDTO{
+		Entries: entryDTOs,
+		Total:   total,
+		Limit:   query.Limit,
+		Offset:  query.Offset,
+		HasMore: int64(query.Offset+query.Limit) < total,
+	}, nil
+}
+
 // Helper methods
 
+/*
+authorize applies the domain access policy to the authenticated caller in ctx.
+Returns ErrUnauthorized if the request is unauthenticated or the policy denies it.
+*/
+func (s *UserService) authorize(ctx context.Context, permission domain.Permission, targetID uuid.UUID) error {
+	principal, ok := auth.PrincipalFromContext(ctx)
+	if !ok {
+		return domain.ErrUnauthorized
+	}
+
+	actor := domain.Actor{
+		UserID: principal.UserID,
+		Role:   domain.Role(principal.Role),
+		Scopes: scopePermissions(principal.Scopes),
+	}
+
+	return domain.Authorize(actor, permission, targetID)
+}
+
 func (s *UserService) validateCreateUserInput(dto CreateUserDTO) error {
 	if dto.Email == "" {
 		return errors.New("email is required")
@@ -316,36 +667,46 @@ func (s *UserService) validateCreateUserInput(dto CreateUserDTO) error {
 	if dto.Password == "" {
 		return errors.New("password is required")
 	}
-	return s.validatePassword(dto.Password)
+	return s.passwords.Validate("password", dto.Password, dto.Email, dto.Name)
 }
 
-func (s *UserService) validatePassword(password string) error {
-	if len(password) < 8 {
-		return errors.New("password must be at least 8 characters")
+// checkVersion returns ErrConcurrentModification if the user does not have the expected version
+func checkVersion(user *domain.User, expectedVersion *int) error {
+	if expectedVersion != nil && *expectedVersion != user.Version {
+		return domain.ErrConcurrentModification
 	}
-	// Add more password validation rules as needed
 	return nil
 }
 
-func (s *UserService) hashPassword(password string) (string, error) {
-	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
-	if err != nil {
-		return "", err
+func toAuditEntryDTO(entry *domain.AuditEntry) AuditEntryDTO {
+	changes := make(map[string]FieldChangeDTO, len(entry.Changes))
+	for field, change := range entry.Changes {
+		changes[field] = FieldChangeDTO{Before: change.Before, After: change.After}
 	}
-	return string(hash), nil
-}
 
-func (s *UserService) verifyPassword(hash, password string) error {
-	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+	return AuditEntryDTO{
+		ID:           entry.ID,
+		ActorID:      entry.ActorID,
+		Action:       entry.Action,
+		TargetUserID: entry.TargetUserID,
+		Changes:      changes,
+		RequestID:    entry.RequestID,
+		IPAddress:    entry.IPAddress,
+		CreatedAt:    entry.CreatedAt,
+	}
 }
 
 func (s *UserService) toUserResponseDTO(user *domain.User) *UserResponseDTO {
 	return &UserResponseDTO{
-		ID:        user.ID,
-		Email:     user.Email.Value(),
-		Name:      user.Name,
-		IsActive:  user.IsActive,
-		CreatedAt: user.CreatedAt,
-		UpdatedAt: user.UpdatedAt,
+		ID:              user.ID,
+		Email:           user.Email.Value(),
+		Name:            user.Name,
+		Role:            user.Role.String(),
+		IsActive:        user.IsActive,
+		EmailVerifiedAt: user.EmailVerifiedAt,
+		LockedUntil:     user.LockedUntil,
+		CreatedAt:       user.CreatedAt,
+		UpdatedAt:       user.UpdatedAt,
+		Version:         user.Version,
 	}
 }
@@ -0,0 +1,31 @@
+package application
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+)
+
+/*
+generateOpaqueToken creates a random, URL-safe token with 256 bits of entropy.
+Returns the plain value (given to the client once) and its SHA-256 hash (stored).
+*/
+func generateOpaqueToken() (string, string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", err
+	}
+
+	token := base64.RawURLEncoding.EncodeToString(buf)
+	return token, hashOpaqueToken(token), nil
+}
+
+/*
+hashOpaqueToken returns the hex-encoded SHA-256 hash of an opaque token.
+A fast hash is sufficient because the tokens are high-entropy random values.
+*/
+func hashOpaqueToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,79 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+/*
+APIKey represents a personal, long-lived credential that lets scripts and services
+act as a user without an interactive login. Only a hash of the key is stored,
+together with a short non-secret prefix that identifies the key in listings.
+
+An API key is limited to its scopes: it can only be used for actions whose
+permission is one of the scopes and that the user's role allows.
+*/
+type APIKey struct {
+	ID         uuid.UUID
+	UserID     uuid.UUID
+	Name       string
+	Prefix     string
+	KeyHash    string
+	Scopes     []Permission
+	ExpiresAt  *time.Time
+	LastUsedAt *time.Time
+	RevokedAt  *time.Time
+	CreatedAt  time.Time
+}
+
+/*
+NewAPIKey creates a new APIKey entity for the given user.
+expiresAt is optional; a nil value creates a key that never expires.
+Returns ErrInvalidAPIKeyName if the name is empty or longer than 100 characters,
+ErrInvalidScope if no scopes are given, or an error if the prefix or hash is empty.
+*/
+func NewAPIKey(userID uuid.UUID, name, prefix, keyHash string, scopes []Permission, expiresAt *time.Time) (*APIKey, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || len(name) > 100 {
+		return nil, ErrInvalidAPIKeyName
+	}
+
+	if len(scopes) == 0 {
+		return nil, ErrInvalidScope
+	}
+
+	if prefix == "" || keyHash == "" {
+		return nil, errors.New("api key prefix and hash cannot be empty")
+	}
+
+	now := time.Now()
+
+	if expiresAt != nil && !expiresAt.After(now) {
+		return nil, ErrInvalidAPIKeyExpiry
+	}
+
+	return &APIKey{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Name:      name,
+		Prefix:    prefix,
+		KeyHash:   keyHash,
+		Scopes:    scopes,
+		ExpiresAt: expiresAt,
+		CreatedAt: now,
+	}, nil
+}
+
+/*
+IsUsable reports whether the key may authenticate requests at the given instant,
+i.e. it has been neither revoked nor has it expired.
+*/
+func (k *APIKey) IsUsable(now time.Time) bool {
+	if k.RevokedAt != nil {
+		return false
+	}
+	return k.ExpiresAt == nil || now.Before(*k.ExpiresAt)
+}
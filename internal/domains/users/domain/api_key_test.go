@@ -0,0 +1,78 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestNewAPIKey(t *testing.T) {
+	future := time.Now().Add(time.Hour)
+	past := time.Now().Add(-time.Hour)
+	scopes := []Permission{PermissionUsersRead}
+
+	tests := []struct {
+		name      string
+		keyName   string
+		prefix    string
+		keyHash   string
+		scopes    []Permission
+		expiresAt *time.Time
+		wantErr   error
+	}{
+		{name: "valid", keyName: "ci", prefix: "ak_1", keyHash: "hash", scopes: scopes},
+		{name: "valid with expiry", keyName: "ci", prefix: "ak_1", keyHash: "hash", scopes: scopes, expiresAt: &future},
+		{name: "blank name", keyName: "  ", prefix: "ak_1", keyHash: "hash", scopes: scopes, wantErr: ErrInvalidAPIKeyName},
+		{name: "long name", keyName: strings.Repeat("a", 101), prefix: "ak_1", keyHash: "hash", scopes: scopes, wantErr: ErrInvalidAPIKeyName},
+		{name: "no scopes", keyName: "ci", prefix: "ak_1", keyHash: "hash", wantErr: ErrInvalidScope},
+		{name: "expiry in the past", keyName: "ci", prefix: "ak_1", keyHash: "hash", scopes: scopes, expiresAt: &past, wantErr: ErrInvalidAPIKeyExpiry},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, err := NewAPIKey(uuid.New(), tt.keyName, tt.prefix, tt.keyHash, tt.scopes, tt.expiresAt)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("NewAPIKey() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && !key.IsUsable(time.Now()) {
+				t.Error("NewAPIKey() key is not usable")
+			}
+		})
+	}
+
+	if _, err := NewAPIKey(uuid.New(), "ci", "", "", scopes, nil); err == nil {
+		t.Error("NewAPIKey() without prefix and hash error = nil, want an error")
+	}
+}
+
+func TestAPIKeyIsUsable(t *testing.T) {
+	now := time.Now()
+	later := now.Add(time.Minute)
+	earlier := now.Add(-time.Minute)
+
+	tests := []struct {
+		name      string
+		expiresAt *time.Time
+		revokedAt *time.Time
+		want      bool
+	}{
+		{name: "no expiry", want: true},
+		{name: "expires later", expiresAt: &later, want: true},
+		{name: "expires now", expiresAt: &now, want: false},
+		{name: "expired", expiresAt: &earlier, want: false},
+		{name: "revoked", revokedAt: &earlier, want: false},
+		{name: "revoked before expiry", expiresAt: &later, revokedAt: &earlier, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key := &APIKey{ExpiresAt: tt.expiresAt, RevokedAt: tt.revokedAt}
+			if got := key.IsUsable(now); got != tt.want {
+				t.Errorf("IsUsable() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
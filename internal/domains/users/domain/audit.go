@@ -0,0 +1,186 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Actions recorded in the audit log
+const (
+	AuditActionUserCreated = "user.created"
+	AuditActionUserUpdated = "user.updated"
+	AuditActionUserDeleted = "user.deleted"
+	AuditActionUserLockout = "user.lockout" // Password login was locked or unlocked
+)
+
+// AuditRedacted stands in for the value of a secret field in the audit log
+const AuditRedacted = "[redacted]"
+
+/*
+AuditedFields lists the user fields whose changes are recorded in the audit log.
+"password" only records that the password changed: its hash is never logged.
+The failed login counters are left out, as they change on every failed login.
+*/
+var AuditedFields = []string{
+	"email",
+	"name",
+	"role",
+	"is_active",
+	"email_verified_at",
+	"locked_until",
+	"password",
+}
+
+// lockoutFields are the audited fields changed by locking and unlocking an account
+var lockoutFields = map[string]bool{
+	"locked_until": true,
+}
+
+// FieldChange holds the value of a field before and after a change
+type FieldChange struct {
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+/*
+AuditEntry records one change to a user account: who made it, from which
+request, and the fields that changed. Entries are append-only.
+ActorID is nil when the change was not made by an authenticated user, such as a
+sign-up, a password reset or a failed login.
+*/
+type AuditEntry struct {
+	ID           uuid.UUID
+	ActorID      *uuid.UUID
+	Action       string
+	TargetUserID uuid.UUID
+	Changes      map[string]FieldChange
+	RequestID    string
+	IPAddress    string
+	CreatedAt    time.Time
+}
+
+/*
+NewAuditEntry creates a new AuditEntry for a change to the target user.
+*/
+func NewAuditEntry(actorID *uuid.UUID, action string, targetUserID uuid.UUID, changes map[string]FieldChange, requestID, ipAddress string) *AuditEntry {
+	return &AuditEntry{
+		ID:           uuid.New(),
+		ActorID:      actorID,
+		Action:       action,
+		TargetUserID: targetUserID,
+		Changes:      changes,
+		RequestID:    requestID,
+		IPAddress:    ipAddress,
+		CreatedAt:    time.Now(),
+	}
+}
+
+/*
+AuditFilter narrows down the audit log of a user.
+Zero values do not filter; entries are returned newest first.
+*/
+type AuditFilter struct {
+	Action  string     // Only entries with this action
+	ActorID *uuid.UUID // Only changes made by this user
+	Field   string     // Only entries that changed this field
+	From    *time.Time // Only entries made at or after this time
+	To      *time.Time // Only entries made before this time
+	Limit   int
+	Offset  int
+}
+
+/*
+DiffUsers returns the audited fields that differ between two states of a user.
+before is nil for a new user, in which case every field is reported.
+A changed password hash is reported as "password" with redacted values, unless
+it was only rehashed (see User.RehashPassword).
+*/
+func DiffUsers(before, after *User) map[string]FieldChange {
+	changes := make(map[string]FieldChange)
+
+	for _, field := range AuditedFields {
+		if field == "password" && before != nil && after.passwordRehashed {
+			continue
+		}
+
+		var beforeValue interface{}
+		if before != nil {
+			beforeValue = auditValue(before, field)
+		}
+		afterValue := auditValue(after, field)
+
+		if before != nil && beforeValue == afterValue {
+			continue
+		}
+		changes[field] = FieldChange{Before: beforeValue, After: afterValue}
+	}
+
+	return changes
+}
+
+/*
+UpdateAuditAction returns the action under which an update with the given
+changes is recorded: AuditActionUserLockout if it only locked or unlocked the
+account, AuditActionUserUpdated otherwise.
+*/
+func UpdateAuditAction(changes map[string]FieldChange) string {
+	for field := range changes {
+		if !lockoutFields[field] {
+			return AuditActionUserUpdated
+		}
+	}
+	return AuditActionUserLockout
+}
+
+// auditValue returns a comparable, loggable value of an audited field
+func auditValue(u *User, field string) interface{} {
+	switch field {
+	case "email":
+		return u.Email.Value()
+	case "name":
+		return u.Name
+	case "role":
+		return u.Role.String()
+	case "is_active":
+		return u.IsActive
+	case "email_verified_at":
+		return optionalTime(u.EmailVerifiedAt)
+	case "locked_until":
+		return optionalTime(u.LockedUntil)
+	case "password":
+		// Compared by hash, but only ever logged as redacted
+		if u.PasswordHash == "" {
+			return nil
+		}
+		return passwordMarker{hash: u.PasswordHash}
+	default:
+		return nil
+	}
+}
+
+// optionalTime formats a time for the audit log, or returns nil if it is not set
+func optionalTime(t *time.Time) interface{} {
+	if t == nil {
+		return nil
+	}
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+/*
+passwordMarker carries a password hash for comparison only: it always
+encodes as AuditRedacted, so the hash cannot end up in the audit log.
+*/
+type passwordMarker struct {
+	hash string
+}
+
+// MarshalJSON encodes the marker as AuditRedacted
+func (passwordMarker) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + AuditRedacted + `"`), nil
+}
+
+// String returns AuditRedacted
+func (passwordMarker) String() string {
+	return AuditRedacted
+}
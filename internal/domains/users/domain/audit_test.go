@@ -0,0 +1,164 @@
+package domain
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+// newAuditTestUser creates a user as loaded from the repository
+func newAuditTestUser(t *testing.T) *User {
+	t.Helper()
+
+	email, _ := NewEmail("audit@example.com")
+	user, err := NewUser(email, "Audit User", "$2a$10$originalhash")
+	if err != nil {
+		t.Fatalf("NewUser() error = %v", err)
+	}
+	user.PullEvents()
+	return user
+}
+
+func TestDiffUsers(t *testing.T) {
+	lockout := LockoutPolicy{Threshold: 2, BaseDuration: time.Minute, MaxDuration: time.Hour}
+
+	tests := []struct {
+		name   string
+		change func(t *testing.T, u *User)
+		want   []string
+	}{
+		{
+			name:   "no change",
+			change: func(t *testing.T, u *User) {},
+		},
+		{
+			name: "profile",
+			change: func(t *testing.T, u *User) {
+				email, _ := NewEmail("renamed@example.com")
+				if err := u.UpdateProfile("Renamed", email); err != nil {
+					t.Fatalf("UpdateProfile() error = %v", err)
+				}
+			},
+			want: []string{"email", "name"},
+		},
+		{
+			name: "password change",
+			change: func(t *testing.T, u *User) {
+				if err := u.ChangePassword("$2a$10$newhash"); err != nil {
+					t.Fatalf("ChangePassword() error = %v", err)
+				}
+			},
+			want: []string{"password"},
+		},
+		{
+			name: "rehash",
+			change: func(t *testing.T, u *User) {
+				if err := u.RehashPassword("$2a$12$rehashed"); err != nil {
+					t.Fatalf("RehashPassword() error = %v", err)
+				}
+			},
+		},
+		{
+			name: "password change after a rehash",
+			change: func(t *testing.T, u *User) {
+				if err := u.RehashPassword("$2a$12$rehashed"); err != nil {
+					t.Fatalf("RehashPassword() error = %v", err)
+				}
+				if err := u.ChangePassword("$2a$12$newhash"); err != nil {
+					t.Fatalf("ChangePassword() error = %v", err)
+				}
+			},
+			want: []string{"password"},
+		},
+		{
+			name: "failed login below the threshold",
+			change: func(t *testing.T, u *User) {
+				u.RecordFailedLogin(lockout, time.Now())
+			},
+		},
+		{
+			name: "lockout",
+			change: func(t *testing.T, u *User) {
+				u.RecordFailedLogin(lockout, time.Now())
+				u.RecordFailedLogin(lockout, time.Now())
+			},
+			want: []string{"locked_until"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := newAuditTestUser(t)
+			after := *before
+			tt.change(t, &after)
+
+			changes := DiffUsers(before, &after)
+			if len(changes) != len(tt.want) {
+				t.Fatalf("DiffUsers() = %v, want changes to %v", changes, tt.want)
+			}
+			for _, field := range tt.want {
+				if _, ok := changes[field]; !ok {
+					t.Errorf("DiffUsers() = %v, want a change to %s", changes, field)
+				}
+			}
+		})
+	}
+}
+
+func TestDiffUsersNewUser(t *testing.T) {
+	user := newAuditTestUser(t)
+
+	changes := DiffUsers(nil, user)
+	if len(changes) != len(AuditedFields) {
+		t.Errorf("DiffUsers(nil) = %v, want every audited field", changes)
+	}
+	for field, change := range changes {
+		if change.Before != nil {
+			t.Errorf("%s.Before = %v, want nil", field, change.Before)
+		}
+	}
+}
+
+func TestDiffUsersRedactsPassword(t *testing.T) {
+	before := newAuditTestUser(t)
+	after := *before
+	if err := after.ChangePassword("$2a$10$newhash"); err != nil {
+		t.Fatalf("ChangePassword() error = %v", err)
+	}
+
+	encoded, err := json.Marshal(DiffUsers(before, &after))
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	want := `{"password":{"before":"[redacted]","after":"[redacted]"}}`
+	if string(encoded) != want {
+		t.Errorf("json.Marshal() = %s, want %s", encoded, want)
+	}
+}
+
+func TestUpdateAuditAction(t *testing.T) {
+	tests := []struct {
+		name    string
+		changes map[string]FieldChange
+		want    string
+	}{
+		{name: "lock", changes: map[string]FieldChange{"locked_until": {Before: nil, After: "2026-01-01T00:00:00Z"}}, want: AuditActionUserLockout},
+		{name: "profile", changes: map[string]FieldChange{"name": {Before: "A", After: "B"}}, want: AuditActionUserUpdated},
+		{
+			name: "unlock with another change",
+			changes: map[string]FieldChange{
+				"locked_until": {Before: "2026-01-01T00:00:00Z", After: nil},
+				"role":         {Before: "member", After: "admin"},
+			},
+			want: AuditActionUserUpdated,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := UpdateAuditAction(tt.changes); got != tt.want {
+				t.Errorf("UpdateAuditAction() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,437 @@
+package domaintest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/domains/users/domain"
+	"github.com/google/uuid"
+)
+
+/*
+NewUserRepositoryFunc creates the UserRepository under test for one subtest,
+along with the context to call it with. Each call must return a repository
+that does not see the users saved by other subtests; implementations backed
+by a database can return a context carrying a transaction that is rolled back
+with t.Cleanup. Since a failed statement aborts such a transaction, a write
+that is expected to fail is always the last call of its subtest.
+*/
+type NewUserRepositoryFunc func(t *testing.T) (context.Context, domain.UserRepository)
+
+/*
+TestUserRepository checks that a domain.UserRepository implementation behaves
+like the contract documented on the interface, so every implementation can be
+swapped for another. Call it from the implementation's own tests:
+
+	func TestUserRepository(t *testing.T) {
+	    domaintest.TestUserRepository(t, func(t *testing.T) (context.Context, domain.UserRepository) {
+	        return context.Background(), memory.NewUserRepository()
+	    })
+	}
+*/
+func TestUserRepository(t *testing.T, newRepository NewUserRepositoryFunc) {
+	tests := []struct {
+		name string
+		run  func(t *testing.T, ctx context.Context, repo domain.UserRepository)
+	}{
+		{"SaveAndFindByID", testSaveAndFindByID},
+		{"SaveDuplicateEmail", testSaveDuplicateEmail},
+		{"FindByIDNotFound", testFindByIDNotFound},
+		{"FindByIDSkipsInactive", testFindByIDSkipsInactive},
+		{"FindByEmail", testFindByEmail},
+		{"FindByEmailSkipsInactive", testFindByEmailSkipsInactive},
+		{"Update", testUpdate},
+		{"UpdateStaleVersion", testUpdateStaleVersion},
+		{"UpdateNotFound", testUpdateNotFound},
+		{"UpdateDuplicateEmail", testUpdateDuplicateEmail},
+		{"UpdateInactive", testUpdateInactive},
+		{"Delete", testDelete},
+		{"DeleteNotFound", testDeleteNotFound},
+		{"ListAndCount", testListAndCount},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, repo := newRepository(t)
+			tt.run(t, ctx, repo)
+		})
+	}
+}
+
+func testSaveAndFindByID(t *testing.T, ctx context.Context, repo domain.UserRepository) {
+	// Every field differs from its default, so a field that is not stored shows up
+	user := newUser(t, "alice@example.com", time.Now())
+	verifiedAt := time.Now().Add(-time.Hour)
+	lockedUntil := time.Now().Add(time.Hour)
+	user.Role = domain.RoleSupport
+	user.EmailVerifiedAt = &verifiedAt
+	user.FailedLoginAttempts = 3
+	user.LockoutCount = 2
+	user.LockedUntil = &lockedUntil
+	mustSave(t, ctx, repo, user)
+
+	found, err := repo.FindByID(ctx, user.ID)
+	if err != nil {
+		t.Fatalf("FindByID() error = %v", err)
+	}
+	assertSameUser(t, found, user)
+	if found.Version != 1 {
+		t.Errorf("Version = %d, want 1", found.Version)
+	}
+	if len(found.Events()) != 0 {
+		t.Errorf("loaded user has %d recorded events, want none", len(found.Events()))
+	}
+}
+
+func testSaveDuplicateEmail(t *testing.T, ctx context.Context, repo domain.UserRepository) {
+	mustSave(t, ctx, repo, newUser(t, "alice@example.com", time.Now()))
+
+	err := repo.Save(ctx, newUser(t, "ALICE@Example.com", time.Now()))
+	if !errors.Is(err, domain.ErrEmailAlreadyExists) {
+		t.Fatalf("Save() with a taken email error = %v, want %v", err, domain.ErrEmailAlreadyExists)
+	}
+}
+
+func testFindByIDNotFound(t *testing.T, ctx context.Context, repo domain.UserRepository) {
+	if _, err := repo.FindByID(ctx, uuid.New()); !errors.Is(err, domain.ErrUserNotFound) {
+		t.Errorf("FindByID() error = %v, want %v", err, domain.ErrUserNotFound)
+	}
+	if _, err := repo.FindByIDIncludingInactive(ctx, uuid.New()); !errors.Is(err, domain.ErrUserNotFound) {
+		t.Errorf("FindByIDIncludingInactive() error = %v, want %v", err, domain.ErrUserNotFound)
+	}
+}
+
+func testFindByIDSkipsInactive(t *testing.T, ctx context.Context, repo domain.UserRepository) {
+	user := newUser(t, "alice@example.com", time.Now())
+	user.IsActive = false
+	mustSave(t, ctx, repo, user)
+
+	if _, err := repo.FindByID(ctx, user.ID); !errors.Is(err, domain.ErrUserNotFound) {
+		t.Errorf("FindByID() of an inactive user error = %v, want %v", err, domain.ErrUserNotFound)
+	}
+
+	found, err := repo.FindByIDIncludingInactive(ctx, user.ID)
+	if err != nil {
+		t.Fatalf("FindByIDIncludingInactive() error = %v", err)
+	}
+	assertSameUser(t, found, user)
+}
+
+func testFindByEmail(t *testing.T, ctx context.Context, repo domain.UserRepository) {
+	user := newUser(t, "alice@example.com", time.Now())
+	mustSave(t, ctx, repo, user)
+	mustSave(t, ctx, repo, newUser(t, "bob@example.com", time.Now()))
+
+	found, err := repo.FindByEmail(ctx, mustEmail(t, "Alice@EXAMPLE.com"))
+	if err != nil {
+		t.Fatalf("FindByEmail() error = %v", err)
+	}
+	assertSameUser(t, found, user)
+
+	if _, err := repo.FindByEmail(ctx, mustEmail(t, "carol@example.com")); !errors.Is(err, domain.ErrUserNotFound) {
+		t.Errorf("FindByEmail() of an unknown email error = %v, want %v", err, domain.ErrUserNotFound)
+	}
+	if _, err := repo.FindByEmailIncludingInactive(ctx, mustEmail(t, "carol@example.com")); !errors.Is(err, domain.ErrUserNotFound) {
+		t.Errorf("FindByEmailIncludingInactive() of an unknown email error = %v, want %v", err, domain.ErrUserNotFound)
+	}
+}
+
+func testFindByEmailSkipsInactive(t *testing.T, ctx context.Context, repo domain.UserRepository) {
+	user := newUser(t, "alice@example.com", time.Now())
+	user.IsActive = false
+	mustSave(t, ctx, repo, user)
+
+	if _, err := repo.FindByEmail(ctx, user.Email); !errors.Is(err, domain.ErrUserNotFound) {
+		t.Errorf("FindByEmail() of an inactive user error = %v, want %v", err, domain.ErrUserNotFound)
+	}
+
+	found, err := repo.FindByEmailIncludingInactive(ctx, user.Email)
+	if err != nil {
+		t.Fatalf("FindByEmailIncludingInactive() error = %v", err)
+	}
+	assertSameUser(t, found, user)
+}
+
+func testUpdate(t *testing.T, ctx context.Context, repo domain.UserRepository) {
+	user := newUser(t, "alice@example.com", time.Now())
+	mustSave(t, ctx, repo, user)
+
+	loaded := mustFindByID(t, ctx, repo, user.ID)
+	if err := loaded.UpdateProfile("Alice Cooper", mustEmail(t, "alice.cooper@example.com")); err != nil {
+		t.Fatalf("UpdateProfile() error = %v", err)
+	}
+	loaded.VerifyEmail()
+	if err := repo.Update(ctx, loaded); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if loaded.Version != 2 {
+		t.Errorf("Version after Update() = %d, want 2", loaded.Version)
+	}
+
+	found := mustFindByID(t, ctx, repo, user.ID)
+	assertSameUser(t, found, loaded)
+	if !sameTime(found.CreatedAt, user.CreatedAt) {
+		t.Errorf("CreatedAt = %v, want it unchanged at %v", found.CreatedAt, user.CreatedAt)
+	}
+
+	if _, err := repo.FindByEmail(ctx, user.Email); !errors.Is(err, domain.ErrUserNotFound) {
+		t.Errorf("FindByEmail() of the old email error = %v, want %v", err, domain.ErrUserNotFound)
+	}
+}
+
+func testUpdateStaleVersion(t *testing.T, ctx context.Context, repo domain.UserRepository) {
+	user := newUser(t, "alice@example.com", time.Now())
+	mustSave(t, ctx, repo, user)
+
+	first := mustFindByID(t, ctx, repo, user.ID)
+	second := mustFindByID(t, ctx, repo, user.ID)
+
+	first.Name = "First"
+	if err := repo.Update(ctx, first); err != nil {
+		t.Fatalf("first Update() error = %v", err)
+	}
+
+	second.Name = "Second"
+	if err := repo.Update(ctx, second); !errors.Is(err, domain.ErrConcurrentModification) {
+		t.Fatalf("Update() of a stale user error = %v, want %v", err, domain.ErrConcurrentModification)
+	}
+	if second.Version != 1 {
+		t.Errorf("Version after a failed Update() = %d, want it unchanged at 1", second.Version)
+	}
+
+	if found := mustFindByID(t, ctx, repo, user.ID); found.Name != "First" {
+		t.Errorf("Name = %q, want %q", found.Name, "First")
+	}
+}
+
+func testUpdateNotFound(t *testing.T, ctx context.Context, repo domain.UserRepository) {
+	user := newUser(t, "alice@example.com", time.Now())
+
+	if err := repo.Update(ctx, user); !errors.Is(err, domain.ErrUserNotFound) {
+		t.Errorf("Update() of an unsaved user error = %v, want %v", err, domain.ErrUserNotFound)
+	}
+}
+
+func testUpdateDuplicateEmail(t *testing.T, ctx context.Context, repo domain.UserRepository) {
+	mustSave(t, ctx, repo, newUser(t, "alice@example.com", time.Now()))
+	user := newUser(t, "bob@example.com", time.Now())
+	mustSave(t, ctx, repo, user)
+
+	loaded := mustFindByID(t, ctx, repo, user.ID)
+	loaded.Email = mustEmail(t, "ALICE@example.com")
+	if err := repo.Update(ctx, loaded); !errors.Is(err, domain.ErrEmailAlreadyExists) {
+		t.Errorf("Update() to a taken email error = %v, want %v", err, domain.ErrEmailAlreadyExists)
+	}
+}
+
+func testUpdateInactive(t *testing.T, ctx context.Context, repo domain.UserRepository) {
+	user := newUser(t, "alice@example.com", time.Now())
+	user.IsActive = false
+	mustSave(t, ctx, repo, user)
+
+	loaded, err := repo.FindByIDIncludingInactive(ctx, user.ID)
+	if err != nil {
+		t.Fatalf("FindByIDIncludingInactive() error = %v", err)
+	}
+	loaded.Activate()
+	if err := repo.Update(ctx, loaded); err != nil {
+		t.Fatalf("Update() of an inactive user error = %v", err)
+	}
+
+	if found := mustFindByID(t, ctx, repo, user.ID); !found.IsActive {
+		t.Error("IsActive = false after reactivation, want true")
+	}
+}
+
+func testDelete(t *testing.T, ctx context.Context, repo domain.UserRepository) {
+	user := newUser(t, "alice@example.com", time.Now())
+	mustSave(t, ctx, repo, user)
+
+	if err := repo.Delete(ctx, user.ID); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+
+	if _, err := repo.FindByID(ctx, user.ID); !errors.Is(err, domain.ErrUserNotFound) {
+		t.Errorf("FindByID() of a deleted user error = %v, want %v", err, domain.ErrUserNotFound)
+	}
+
+	found, err := repo.FindByIDIncludingInactive(ctx, user.ID)
+	if err != nil {
+		t.Fatalf("FindByIDIncludingInactive() of a deleted user error = %v", err)
+	}
+	if found.IsActive {
+		t.Error("IsActive = true after Delete(), want false")
+	}
+	if found.Version != 2 {
+		t.Errorf("Version after Delete() = %d, want 2", found.Version)
+	}
+
+	// The email stays taken by the deleted user
+	if err := repo.Save(ctx, newUser(t, "alice@example.com", time.Now())); !errors.Is(err, domain.ErrEmailAlreadyExists) {
+		t.Errorf("Save() with a deleted user's email error = %v, want %v", err, domain.ErrEmailAlreadyExists)
+	}
+}
+
+func testDeleteNotFound(t *testing.T, ctx context.Context, repo domain.UserRepository) {
+	if err := repo.Delete(ctx, uuid.New()); !errors.Is(err, domain.ErrUserNotFound) {
+		t.Errorf("Delete() of an unknown user error = %v, want %v", err, domain.ErrUserNotFound)
+	}
+}
+
+func testListAndCount(t *testing.T, ctx context.Context, repo domain.UserRepository) {
+	baseline, err := repo.Count(ctx)
+	if err != nil {
+		t.Fatalf("Count() error = %v", err)
+	}
+
+	// Created in the future so they come before any user already stored
+	start := time.Now().Add(24 * time.Hour)
+	var want []*domain.User
+	for i := 0; i < 5; i++ {
+		user := newUser(t, fmt.Sprintf("user%d@example.com", i), start.Add(time.Duration(i)*time.Minute))
+		mustSave(t, ctx, repo, user)
+		want = append([]*domain.User{user}, want...) // newest first
+	}
+
+	inactive := newUser(t, "inactive@example.com", start.Add(time.Hour))
+	inactive.IsActive = false
+	mustSave(t, ctx, repo, inactive)
+
+	count, err := repo.Count(ctx)
+	if err != nil {
+		t.Fatalf("Count() error = %v", err)
+	}
+	if count != baseline+5 {
+		t.Errorf("Count() = %d, want %d", count, baseline+5)
+	}
+
+	firstPage, err := repo.List(ctx, 3, 0)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	secondPage, err := repo.List(ctx, 2, 3)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+
+	got := append(firstPage, secondPage...)
+	if len(got) != len(want) {
+		t.Fatalf("List() returned %d users, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i].ID != want[i].ID {
+			t.Errorf("List()[%d] = %s, want %s (newest first, active only)", i, got[i].Email, want[i].Email)
+		}
+	}
+
+	empty, err := repo.List(ctx, 0, 0)
+	if err != nil {
+		t.Fatalf("List() with limit 0 error = %v", err)
+	}
+	if len(empty) != 0 {
+		t.Errorf("List() with limit 0 returned %d users, want none", len(empty))
+	}
+}
+
+// newUser creates a user with the given email, created at the given time
+func newUser(t *testing.T, email string, createdAt time.Time) *domain.User {
+	t.Helper()
+
+	user, err := domain.NewUser(mustEmail(t, email), "Test User", "$2a$10$abcdefghijklmnopqrstuv")
+	if err != nil {
+		t.Fatalf("NewUser() error = %v", err)
+	}
+	user.CreatedAt = createdAt
+	user.UpdatedAt = createdAt
+	return user
+}
+
+func mustEmail(t *testing.T, email string) domain.Email {
+	t.Helper()
+
+	e, err := domain.NewEmail(email)
+	if err != nil {
+		t.Fatalf("NewEmail(%q) error = %v", email, err)
+	}
+	return e
+}
+
+func mustSave(t *testing.T, ctx context.Context, repo domain.UserRepository, user *domain.User) {
+	t.Helper()
+
+	if err := repo.Save(ctx, user); err != nil {
+		t.Fatalf("Save(%s) error = %v", user.Email, err)
+	}
+}
+
+func mustFindByID(t *testing.T, ctx context.Context, repo domain.UserRepository, id uuid.UUID) *domain.User {
+	t.Helper()
+
+	user, err := repo.FindByID(ctx, id)
+	if err != nil {
+		t.Fatalf("FindByID(%s) error = %v", id, err)
+	}
+	return user
+}
+
+// assertSameUser compares the stored fields of two users, except the version
+func assertSameUser(t *testing.T, got, want *domain.User) {
+	t.Helper()
+
+	if got.ID != want.ID {
+		t.Errorf("ID = %s, want %s", got.ID, want.ID)
+	}
+	if got.Email != want.Email {
+		t.Errorf("Email = %s, want %s", got.Email, want.Email)
+	}
+	if got.Name != want.Name {
+		t.Errorf("Name = %q, want %q", got.Name, want.Name)
+	}
+	if got.PasswordHash != want.PasswordHash {
+		t.Errorf("PasswordHash = %q, want %q", got.PasswordHash, want.PasswordHash)
+	}
+	if got.Role != want.Role {
+		t.Errorf("Role = %s, want %s", got.Role, want.Role)
+	}
+	if got.IsActive != want.IsActive {
+		t.Errorf("IsActive = %v, want %v", got.IsActive, want.IsActive)
+	}
+	if got.FailedLoginAttempts != want.FailedLoginAttempts {
+		t.Errorf("FailedLoginAttempts = %d, want %d", got.FailedLoginAttempts, want.FailedLoginAttempts)
+	}
+	if got.LockoutCount != want.LockoutCount {
+		t.Errorf("LockoutCount = %d, want %d", got.LockoutCount, want.LockoutCount)
+	}
+	if !sameTimePtr(got.EmailVerifiedAt, want.EmailVerifiedAt) {
+		t.Errorf("EmailVerifiedAt = %v, want %v", got.EmailVerifiedAt, want.EmailVerifiedAt)
+	}
+	if !sameTimePtr(got.LockedUntil, want.LockedUntil) {
+		t.Errorf("LockedUntil = %v, want %v", got.LockedUntil, want.LockedUntil)
+	}
+	if !sameTime(got.CreatedAt, want.CreatedAt) {
+		t.Errorf("CreatedAt = %v, want %v", got.CreatedAt, want.CreatedAt)
+	}
+	if !sameTime(got.UpdatedAt, want.UpdatedAt) {
+		t.Errorf("UpdatedAt = %v, want %v", got.UpdatedAt, want.UpdatedAt)
+	}
+}
+
+/*
+sameTime compares two times the way a TIMESTAMP column stores them:
+by wall clock, to the microsecond, without the time zone.
+*/
+func sameTime(a, b time.Time) bool {
+	const layout = "2006-01-02T15:04:05.000000"
+	return a.Format(layout) == b.Format(layout)
+}
+
+// sameTimePtr is sameTime for optional times
+func sameTimePtr(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return sameTime(*a, *b)
+}
@@ -0,0 +1,55 @@
+package domain
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+/*
+EmailVerificationToken represents a single-use token proving that a user controls
+their email address. The plain token is emailed to the user; only its hash is
+stored. A token can be used once and expires after a configurable time.
+*/
+type EmailVerificationToken struct {
+	ID        uuid.UUID
+	UserID    uuid.UUID
+	TokenHash string
+	ExpiresAt time.Time
+	CreatedAt time.Time
+	UsedAt    *time.Time
+}
+
+/*
+NewEmailVerificationToken creates a new EmailVerificationToken entity for the given user.
+The token expires ttl after creation.
+Returns an error if the token hash is empty or the ttl is not positive.
+*/
+func NewEmailVerificationToken(userID uuid.UUID, tokenHash string, ttl time.Duration) (*EmailVerificationToken, error) {
+	if tokenHash == "" {
+		return nil, errors.New("token hash cannot be empty")
+	}
+
+	if ttl <= 0 {
+		return nil, errors.New("email verification token ttl must be positive")
+	}
+
+	now := time.Now()
+
+	return &EmailVerificationToken{
+		ID:        uuid.New(),
+		UserID:    userID,
+		TokenHash: tokenHash,
+		ExpiresAt: now.Add(ttl),
+		CreatedAt: now,
+	}, nil
+}
+
+/*
+IsUsable reports whether the token can still be used at the given instant:
+it has not been used (or invalidated) and has not expired.
+*/
+func (t *EmailVerificationToken) IsUsable(now time.Time) bool {
+	return t.UsedAt == nil && now.Before(t.ExpiresAt)
+}
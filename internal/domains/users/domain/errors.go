@@ -23,10 +23,82 @@ var (
 	// ErrInvalidPassword indicates that the provided password does not meet requirements
 	ErrInvalidPassword = errors.New("invalid password")
 
+	// ErrConcurrentModification indicates that the user was changed by someone else
+	// since it was loaded, so the update was rejected rather than overwriting that change
+	ErrConcurrentModification = errors.New("user was modified concurrently")
+
 	// ErrUserInactive indicates that the user account is deactivated
 	// Operations on inactive users may be restricted
 	ErrUserInactive = errors.New("user is inactive")
 
 	// ErrUnauthorized indicates that the user is not authorized to perform the action
 	ErrUnauthorized = errors.New("unauthorized")
+
+	// ErrInvalidRole indicates that the provided role is not a known role
+	ErrInvalidRole = errors.New("invalid role")
+
+	// ErrInvalidCredentials indicates that the email/password combination is wrong
+	// It deliberately does not reveal which of the two was incorrect
+	ErrInvalidCredentials = errors.New("invalid credentials")
+
+	// ErrRefreshTokenNotFound indicates that no refresh token matches the given hash
+	ErrRefreshTokenNotFound = errors.New("refresh token not found")
+
+	// ErrInvalidRefreshToken indicates that a refresh token is unknown, expired or revoked
+	ErrInvalidRefreshToken = errors.New("invalid refresh token")
+
+	// ErrRefreshTokenReused indicates that an already-rotated refresh token was presented again
+	// The whole token family is revoked when this happens
+	ErrRefreshTokenReused = errors.New("refresh token reused")
+
+	// ErrPasswordResetTokenNotFound indicates that no password reset token matches the given hash
+	ErrPasswordResetTokenNotFound = errors.New("password reset token not found")
+
+	// ErrInvalidResetToken indicates that a password reset token is unknown, used or expired
+	ErrInvalidResetToken = errors.New("invalid password reset token")
+
+	// ErrEmailNotVerified indicates that the user has not verified their email address yet
+	ErrEmailNotVerified = errors.New("email not verified")
+
+	// ErrEmailVerificationTokenNotFound indicates that no email verification token matches the given hash
+	ErrEmailVerificationTokenNotFound = errors.New("email verification token not found")
+
+	// ErrInvalidVerificationToken indicates that an email verification token is unknown, used or expired
+	ErrInvalidVerificationToken = errors.New("invalid email verification token")
+
+	// ErrMFANotEnrolled indicates that the user has not started TOTP enrolment
+	ErrMFANotEnrolled = errors.New("mfa not enrolled")
+
+	// ErrMFAAlreadyEnabled indicates that the user already has a confirmed TOTP factor
+	ErrMFAAlreadyEnabled = errors.New("mfa already enabled")
+
+	// ErrInvalidMFACode indicates that a TOTP or recovery code is wrong, expired or already used
+	ErrInvalidMFACode = errors.New("invalid mfa code")
+
+	// ErrInvalidMFAChallenge indicates that an MFA challenge token is invalid or expired
+	ErrInvalidMFAChallenge = errors.New("invalid mfa challenge")
+
+	// ErrAccountLocked indicates that the account is temporarily locked after repeated failed attempts
+	ErrAccountLocked = errors.New("account locked")
+
+	// ErrTooManyLoginAttempts indicates that too many failed attempts came from the same client
+	ErrTooManyLoginAttempts = errors.New("too many login attempts")
+
+	// ErrTooManyResetRequests indicates that too many password resets were requested for the same client or email
+	ErrTooManyResetRequests = errors.New("too many password reset requests")
+
+	// ErrAPIKeyNotFound indicates that no active API key matches
+	ErrAPIKeyNotFound = errors.New("api key not found")
+
+	// ErrInvalidAPIKey indicates that an API key is unknown, revoked or expired
+	ErrInvalidAPIKey = errors.New("invalid api key")
+
+	// ErrInvalidAPIKeyName indicates that an API key name is empty or too long
+	ErrInvalidAPIKeyName = errors.New("invalid api key name")
+
+	// ErrInvalidAPIKeyExpiry indicates that an API key expiry is not in the future
+	ErrInvalidAPIKeyExpiry = errors.New("invalid api key expiry")
+
+	// ErrInvalidScope indicates that API key scopes are missing or name unknown permissions
+	ErrInvalidScope = errors.New("invalid scope")
 )
@@ -0,0 +1,97 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Names of the events raised by the User aggregate
+const (
+	EventUserRegistered      = "user.registered"
+	EventUserEmailChanged    = "user.email_changed"
+	EventUserPasswordChanged = "user.password_changed"
+	EventUserDeactivated     = "user.deactivated"
+	EventUserReactivated     = "user.reactivated"
+)
+
+/*
+Event is something that happened to an aggregate.
+Aggregates record events as their state changes; the application layer
+publishes them once the change has been persisted.
+Events are immutable and must not carry secrets such as password hashes.
+EventID is unique per event, so consumers can ignore an event delivered twice.
+*/
+type Event interface {
+	EventName() string
+	EventID() uuid.UUID
+	AggregateID() uuid.UUID
+	OccurredAt() time.Time
+}
+
+// UserEvent holds the fields shared by all events raised by the User aggregate
+type UserEvent struct {
+	ID     uuid.UUID `json:"event_id"`
+	UserID uuid.UUID `json:"user_id"`
+	At     time.Time `json:"occurred_at"`
+}
+
+// EventID returns the unique ID of the event
+func (e UserEvent) EventID() uuid.UUID {
+	return e.ID
+}
+
+// AggregateID returns the ID of the user the event happened to
+func (e UserEvent) AggregateID() uuid.UUID {
+	return e.UserID
+}
+
+// OccurredAt returns when the event happened
+func (e UserEvent) OccurredAt() time.Time {
+	return e.At
+}
+
+// UserRegistered is raised when a new user is created
+type UserRegistered struct {
+	UserEvent
+	Email string `json:"email"`
+	Name  string `json:"name"`
+	Role  string `json:"role"`
+}
+
+// EventName returns the name of the event
+func (UserRegistered) EventName() string { return EventUserRegistered }
+
+// UserEmailChanged is raised when a user's email address changes
+type UserEmailChanged struct {
+	UserEvent
+	OldEmail string `json:"old_email"`
+	NewEmail string `json:"new_email"`
+}
+
+// EventName returns the name of the event
+func (UserEmailChanged) EventName() string { return EventUserEmailChanged }
+
+// UserPasswordChanged is raised when a user's password changes (not when it is only rehashed)
+type UserPasswordChanged struct {
+	UserEvent
+}
+
+// EventName returns the name of the event
+func (UserPasswordChanged) EventName() string { return EventUserPasswordChanged }
+
+// UserDeactivated is raised when an active user is deactivated (soft deleted)
+type UserDeactivated struct {
+	UserEvent
+}
+
+// EventName returns the name of the event
+func (UserDeactivated) EventName() string { return EventUserDeactivated }
+
+// UserReactivated is raised when a deactivated user is activated again
+type UserReactivated struct {
+	UserEvent
+}
+
+// EventName returns the name of the event
+func (UserReactivated) EventName() string { return EventUserReactivated }
@@ -0,0 +1,66 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+/*
+LockoutPolicy describes when repeated failed password attempts lock an account
+and for how long.
+After Threshold consecutive failures the account is locked for BaseDuration.
+Each further lockout without a successful login in between doubles the duration,
+up to MaxDuration. A zero Threshold disables lockout.
+*/
+type LockoutPolicy struct {
+	Threshold    int
+	BaseDuration time.Duration
+	MaxDuration  time.Duration
+}
+
+/*
+Enabled reports whether the policy locks accounts at all.
+*/
+func (p LockoutPolicy) Enabled() bool {
+	return p.Threshold > 0 && p.BaseDuration > 0
+}
+
+/*
+LockDuration returns how long the account is locked for its n-th consecutive lockout.
+Without a MaxDuration the doubling stops before the duration would overflow.
+*/
+func (p LockoutPolicy) LockDuration(lockouts int) time.Duration {
+	duration := p.BaseDuration
+	for i := 1; i < lockouts && (p.MaxDuration <= 0 || duration < p.MaxDuration) && duration <= math.MaxInt64/2; i++ {
+		duration *= 2
+	}
+	if p.MaxDuration > 0 && duration > p.MaxDuration {
+		return p.MaxDuration
+	}
+	return duration
+}
+
+/*
+LockoutError is returned when a request is refused because of too many
+attempts. It wraps ErrAccountLocked, ErrTooManyLoginAttempts or
+ErrTooManyResetRequests, so callers can match it with errors.Is, and tells the
+client when to retry.
+*/
+type LockoutError struct {
+	Err        error
+	RetryAfter time.Duration
+}
+
+// Error implements the error interface
+func (e *LockoutError) Error() string {
+	return fmt.Sprintf("%v: retry after %s", e.Err, e.RetryAfter.Round(time.Second))
+}
+
+/*
+Unwrap returns the underlying sentinel error.
+This allows errors.Is() to match the sentinel, e.g. ErrAccountLocked.
+*/
+func (e *LockoutError) Unwrap() error {
+	return e.Err
+}
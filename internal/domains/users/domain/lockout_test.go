@@ -0,0 +1,134 @@
+package domain
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestLockoutPolicyEnabled(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy LockoutPolicy
+		want   bool
+	}{
+		{name: "enabled", policy: LockoutPolicy{Threshold: 5, BaseDuration: time.Minute, MaxDuration: time.Hour}, want: true},
+		{name: "zero threshold", policy: LockoutPolicy{Threshold: 0, BaseDuration: time.Minute, MaxDuration: time.Hour}, want: false},
+		{name: "zero duration", policy: LockoutPolicy{Threshold: 5, MaxDuration: time.Hour}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.policy.Enabled(); got != tt.want {
+				t.Errorf("Enabled() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLockoutPolicyLockDuration(t *testing.T) {
+	capped := LockoutPolicy{Threshold: 5, BaseDuration: time.Minute, MaxDuration: time.Hour}
+	uncapped := LockoutPolicy{Threshold: 5, BaseDuration: time.Minute}
+
+	tests := []struct {
+		name     string
+		policy   LockoutPolicy
+		lockouts int
+		want     time.Duration
+	}{
+		{name: "first lockout", policy: capped, lockouts: 1, want: time.Minute},
+		{name: "second lockout doubles", policy: capped, lockouts: 2, want: 2 * time.Minute},
+		{name: "sixth lockout", policy: capped, lockouts: 6, want: 32 * time.Minute},
+		{name: "capped at the maximum", policy: capped, lockouts: 7, want: time.Hour},
+		{name: "high count stays capped", policy: capped, lockouts: 1000, want: time.Hour},
+		{name: "max count stays capped", policy: capped, lockouts: math.MaxInt, want: time.Hour},
+		{name: "uncapped doubles", policy: uncapped, lockouts: 11, want: 1024 * time.Minute},
+		{name: "uncapped does not overflow", policy: uncapped, lockouts: 1000, want: time.Minute << 27},
+		{name: "zero lockouts", policy: capped, lockouts: 0, want: time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.policy.LockDuration(tt.lockouts); got != tt.want {
+				t.Errorf("LockDuration(%d) = %s, want %s", tt.lockouts, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRecordFailedLogin(t *testing.T) {
+	policy := LockoutPolicy{Threshold: 3, BaseDuration: time.Minute, MaxDuration: time.Hour}
+	now := time.Now()
+
+	email, _ := NewEmail("lockout@example.com")
+	user, err := NewUser(email, "Lockout User", "hash")
+	if err != nil {
+		t.Fatalf("NewUser() error = %v", err)
+	}
+
+	for i := 1; i < policy.Threshold; i++ {
+		user.RecordFailedLogin(policy, now)
+		if user.IsLocked(now) {
+			t.Fatalf("locked after %d failures, want %d", i, policy.Threshold)
+		}
+	}
+
+	user.RecordFailedLogin(policy, now)
+	if !user.IsLocked(now) || user.LockedUntil.Sub(now) != time.Minute {
+		t.Fatalf("LockedUntil = %v, want locked for 1m", user.LockedUntil)
+	}
+	if user.FailedLoginAttempts != 0 || user.LockoutCount != 1 {
+		t.Errorf("attempts, lockouts = %d, %d, want 0, 1", user.FailedLoginAttempts, user.LockoutCount)
+	}
+	if user.IsLocked(now.Add(time.Minute)) {
+		t.Error("still locked after the lock period")
+	}
+
+	// The next lockout without a successful login in between lasts twice as long
+	later := now.Add(time.Minute)
+	for i := 0; i < policy.Threshold; i++ {
+		user.RecordFailedLogin(policy, later)
+	}
+	if user.LockedUntil.Sub(later) != 2*time.Minute {
+		t.Errorf("second lock = %s, want 2m", user.LockedUntil.Sub(later))
+	}
+
+	if !user.RecordSuccessfulLogin() {
+		t.Error("RecordSuccessfulLogin() = false, want true")
+	}
+	if user.LockoutCount != 0 || user.LockedUntil != nil || user.FailedLoginAttempts != 0 {
+		t.Errorf("after success = %+v, want lockout state cleared", user)
+	}
+	if user.RecordSuccessfulLogin() {
+		t.Error("RecordSuccessfulLogin() with nothing to clear = true, want false")
+	}
+}
+
+func TestRecordFailedLoginDisabled(t *testing.T) {
+	email, _ := NewEmail("nolockout@example.com")
+	user, err := NewUser(email, "No Lockout", "hash")
+	if err != nil {
+		t.Fatalf("NewUser() error = %v", err)
+	}
+
+	policy := LockoutPolicy{Threshold: 0, BaseDuration: time.Minute, MaxDuration: time.Hour}
+	for i := 0; i < 100; i++ {
+		user.RecordFailedLogin(policy, time.Now())
+	}
+
+	if user.FailedLoginAttempts != 0 || user.LockedUntil != nil {
+		t.Errorf("attempts, locked until = %d, %v, want nothing recorded", user.FailedLoginAttempts, user.LockedUntil)
+	}
+}
+
+func TestLockoutError(t *testing.T) {
+	err := error(&LockoutError{Err: ErrAccountLocked, RetryAfter: 90 * time.Second})
+
+	if !errors.Is(err, ErrAccountLocked) {
+		t.Errorf("errors.Is(%v, ErrAccountLocked) = false, want true", err)
+	}
+	if got, want := err.Error(), "account locked: retry after 1m30s"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,91 @@
+package domain
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+/*
+TOTPFactor represents a user's authenticator app enrolment for two-factor
+authentication. The shared secret is only ever held encrypted; the domain does
+not know how it is encrypted.
+An enrolment is pending until the user proves possession of the secret by
+confirming it with a valid code; only confirmed factors are enforced at login.
+*/
+type TOTPFactor struct {
+	UserID          uuid.UUID
+	EncryptedSecret string
+	ConfirmedAt     *time.Time
+	LastUsedStep    int64
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+/*
+NewTOTPFactor creates a new, unconfirmed TOTPFactor for the given user.
+Returns an error if the encrypted secret is empty.
+*/
+func NewTOTPFactor(userID uuid.UUID, encryptedSecret string) (*TOTPFactor, error) {
+	if encryptedSecret == "" {
+		return nil, errors.New("encrypted secret cannot be empty")
+	}
+
+	now := time.Now()
+
+	return &TOTPFactor{
+		UserID:          userID,
+		EncryptedSecret: encryptedSecret,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}, nil
+}
+
+/*
+IsConfirmed reports whether enrolment has been confirmed with a valid code.
+*/
+func (f *TOTPFactor) IsConfirmed() bool {
+	return f.ConfirmedAt != nil
+}
+
+/*
+Confirm marks the enrolment as confirmed. The step of the code used to confirm
+is recorded so that the same code cannot be used again to log in.
+UpdatedAt timestamp is automatically updated.
+*/
+func (f *TOTPFactor) Confirm(step int64) {
+	now := time.Now()
+	f.ConfirmedAt = &now
+	f.LastUsedStep = step
+	f.UpdatedAt = now
+}
+
+/*
+RecoveryCode represents a single-use code that can replace a TOTP code when the
+user has lost access to their authenticator app. Only its hash is stored.
+*/
+type RecoveryCode struct {
+	ID        uuid.UUID
+	UserID    uuid.UUID
+	CodeHash  string
+	CreatedAt time.Time
+	UsedAt    *time.Time
+}
+
+/*
+NewRecoveryCode creates a new unused RecoveryCode for the given user.
+Returns an error if the code hash is empty.
+*/
+func NewRecoveryCode(userID uuid.UUID, codeHash string) (*RecoveryCode, error) {
+	if codeHash == "" {
+		return nil, errors.New("code hash cannot be empty")
+	}
+
+	return &RecoveryCode{
+		ID:        uuid.New(),
+		UserID:    userID,
+		CodeHash:  codeHash,
+		CreatedAt: time.Now(),
+	}, nil
+}
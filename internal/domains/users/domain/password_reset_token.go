@@ -0,0 +1,55 @@
+package domain
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+/*
+PasswordResetToken represents a single-use token that lets a user set a new password
+without knowing the old one. The plain token is emailed to the user; only its hash
+is stored. A token can be used once and expires after a short time.
+*/
+type PasswordResetToken struct {
+	ID        uuid.UUID
+	UserID    uuid.UUID
+	TokenHash string
+	ExpiresAt time.Time
+	CreatedAt time.Time
+	UsedAt    *time.Time
+}
+
+/*
+NewPasswordResetToken creates a new PasswordResetToken entity for the given user.
+The token expires ttl after creation.
+Returns an error if the token hash is empty or the ttl is not positive.
+*/
+func NewPasswordResetToken(userID uuid.UUID, tokenHash string, ttl time.Duration) (*PasswordResetToken, error) {
+	if tokenHash == "" {
+		return nil, errors.New("token hash cannot be empty")
+	}
+
+	if ttl <= 0 {
+		return nil, errors.New("password reset token ttl must be positive")
+	}
+
+	now := time.Now()
+
+	return &PasswordResetToken{
+		ID:        uuid.New(),
+		UserID:    userID,
+		TokenHash: tokenHash,
+		ExpiresAt: now.Add(ttl),
+		CreatedAt: now,
+	}, nil
+}
+
+/*
+IsUsable reports whether the token can still be used at the given instant:
+it has not been used (or invalidated) and has not expired.
+*/
+func (t *PasswordResetToken) IsUsable(now time.Time) bool {
+	return t.UsedAt == nil && now.Before(t.ExpiresAt)
+}
@@ -0,0 +1,76 @@
+package domain
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+/*
+RefreshToken represents a long-lived credential that can be exchanged for a new
+access token. Only a hash of the token is stored; the plain value is shown to the
+client once.
+
+Tokens are grouped into families: every login starts a new family, and each refresh
+rotates the presented token into a new one in the same family. Presenting a token that
+was already rotated means it leaked, so the whole family is revoked.
+*/
+type RefreshToken struct {
+	ID        uuid.UUID
+	UserID    uuid.UUID
+	FamilyID  uuid.UUID
+	TokenHash string
+	ExpiresAt time.Time
+	CreatedAt time.Time
+	RotatedAt *time.Time
+	RevokedAt *time.Time
+}
+
+/*
+NewRefreshToken creates a new RefreshToken entity for the given user and family.
+The token expires ttl after creation.
+Returns an error if the token hash is empty or the ttl is not positive.
+*/
+func NewRefreshToken(userID, familyID uuid.UUID, tokenHash string, ttl time.Duration) (*RefreshToken, error) {
+	if tokenHash == "" {
+		return nil, errors.New("token hash cannot be empty")
+	}
+
+	if ttl <= 0 {
+		return nil, errors.New("refresh token ttl must be positive")
+	}
+
+	now := time.Now()
+
+	return &RefreshToken{
+		ID:        uuid.New(),
+		UserID:    userID,
+		FamilyID:  familyID,
+		TokenHash: tokenHash,
+		ExpiresAt: now.Add(ttl),
+		CreatedAt: now,
+	}, nil
+}
+
+/*
+IsExpired reports whether the token's expiry time has passed at the given instant.
+*/
+func (t *RefreshToken) IsExpired(now time.Time) bool {
+	return !now.Before(t.ExpiresAt)
+}
+
+/*
+IsRotated reports whether the token has already been exchanged for a new one.
+A rotated token being presented again indicates token theft.
+*/
+func (t *RefreshToken) IsRotated() bool {
+	return t.RotatedAt != nil
+}
+
+/*
+IsRevoked reports whether the token was revoked by logout or reuse detection.
+*/
+func (t *RefreshToken) IsRevoked() bool {
+	return t.RevokedAt != nil
+}
@@ -2,6 +2,7 @@ package domain
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -19,6 +20,9 @@ All methods accept context.Context as the first parameter for:
   - Request cancellation propagation
   - Timeout handling
   - Request-scoped values (like request ID)
+
+Save, Update and Delete record the change in the audit log (see AuditEntry)
+atomically with the change itself.
 */
 type UserRepository interface {
 	/*
@@ -43,6 +47,17 @@ type UserRepository interface {
 	*/
 	FindByID(ctx context.Context, id uuid.UUID) (*User, error)
 
+	/*
+		FindByIDIncludingInactive retrieves a user by ID regardless of is_active.
+		Returns:
+		  - The user if found (active or deactivated)
+		  - ErrUserNotFound if no user exists with the given ID
+		  - Other errors for database failures
+
+		Used to reactivate soft-deleted accounts.
+	*/
+	FindByIDIncludingInactive(ctx context.Context, id uuid.UUID) (*User, error)
+
 	/*
 		FindByEmail retrieves a user by their email address.
 		Returns:
@@ -55,10 +70,25 @@ type UserRepository interface {
 	*/
 	FindByEmail(ctx context.Context, email Email) (*User, error)
 
+	/*
+		FindByEmailIncludingInactive retrieves a user by email regardless of is_active.
+		Returns:
+		  - The user if found (active or deactivated)
+		  - ErrUserNotFound if no user exists with the given email
+		  - Other errors for database failures
+
+		Used by authentication so deactivated accounts can be told apart
+		from unknown emails.
+	*/
+	FindByEmailIncludingInactive(ctx context.Context, email Email) (*User, error)
+
 	/*
 		Update modifies an existing user in the repository.
+		The update only applies if the stored version still equals user.Version
+		(optimistic concurrency control); on success user.Version is incremented.
 		Returns an error if:
 		  - The user does not exist (ErrUserNotFound)
+		  - The user was updated since it was loaded (ErrConcurrentModification)
 		  - The new email conflicts with another user (ErrEmailAlreadyExists)
 		  - Database connection fails
 		  - Validation fails
@@ -69,7 +99,8 @@ type UserRepository interface {
 
 	/*
 		Delete removes a user from the repository (soft delete).
-		This sets is_active = false rather than physically deleting the record.
+		This sets is_active = false rather than physically deleting the record,
+		and increments the version.
 		Returns an error if:
 		  - The user does not exist (ErrUserNotFound)
 		  - Database connection fails
@@ -105,3 +136,197 @@ type UserRepository interface {
 	*/
 	Count(ctx context.Context) (int64, error)
 }
+
+/*
+RefreshTokenRepository defines the contract for refresh token persistence.
+Only token hashes are stored; the repository never sees plain token values.
+*/
+type RefreshTokenRepository interface {
+	/*
+		Save persists a newly issued refresh token.
+	*/
+	Save(ctx context.Context, token *RefreshToken) error
+
+	/*
+		FindByHash retrieves a refresh token by the hash of its value.
+		Returns ErrRefreshTokenNotFound if no token matches, including
+		rotated and revoked tokens (callers inspect those states themselves).
+	*/
+	FindByHash(ctx context.Context, tokenHash string) (*RefreshToken, error)
+
+	/*
+		MarkRotated records that the token was exchanged for a new one.
+		The update only applies if the token has not been rotated or revoked yet,
+		so two concurrent refreshes with the same token cannot both succeed.
+		Returns ErrRefreshTokenReused if the token was no longer active.
+	*/
+	MarkRotated(ctx context.Context, id uuid.UUID, at time.Time) error
+
+	/*
+		RevokeFamily revokes every still-active token in the given family.
+		Used on logout and when token reuse is detected.
+	*/
+	RevokeFamily(ctx context.Context, familyID uuid.UUID, at time.Time) error
+
+	/*
+		RevokeAllForUser revokes every still-active token of the given user.
+		Used when the user's password is reset so existing sessions end.
+	*/
+	RevokeAllForUser(ctx context.Context, userID uuid.UUID, at time.Time) error
+}
+
+/*
+PasswordResetTokenRepository defines the contract for password reset token persistence.
+Only token hashes are stored.
+*/
+type PasswordResetTokenRepository interface {
+	/*
+		Save persists a newly issued password reset token.
+	*/
+	Save(ctx context.Context, token *PasswordResetToken) error
+
+	/*
+		FindByHash retrieves a password reset token by the hash of its value.
+		Returns ErrPasswordResetTokenNotFound if no token matches.
+	*/
+	FindByHash(ctx context.Context, tokenHash string) (*PasswordResetToken, error)
+
+	/*
+		MarkUsed records that the token was used.
+		The update only applies if the token has not been used yet, so the same
+		token cannot reset the password twice.
+		Returns ErrInvalidResetToken if the token was already used.
+	*/
+	MarkUsed(ctx context.Context, id uuid.UUID, at time.Time) error
+
+	/*
+		InvalidateAllForUser marks every unused token of the given user as used.
+		Called before issuing a new token so only the latest emailed link works.
+	*/
+	InvalidateAllForUser(ctx context.Context, userID uuid.UUID, at time.Time) error
+}
+
+/*
+EmailVerificationTokenRepository defines the contract for email verification token persistence.
+Only token hashes are stored.
+*/
+type EmailVerificationTokenRepository interface {
+	/*
+		Save persists a newly issued email verification token.
+	*/
+	Save(ctx context.Context, token *EmailVerificationToken) error
+
+	/*
+		FindByHash retrieves an email verification token by the hash of its value.
+		Returns ErrEmailVerificationTokenNotFound if no token matches.
+	*/
+	FindByHash(ctx context.Context, tokenHash string) (*EmailVerificationToken, error)
+
+	/*
+		MarkUsed records that the token was used.
+		The update only applies if the token has not been used yet.
+		Returns ErrInvalidVerificationToken if the token was already used.
+	*/
+	MarkUsed(ctx context.Context, id uuid.UUID, at time.Time) error
+
+	/*
+		InvalidateAllForUser marks every unused token of the given user as used.
+		Called before issuing a new token so only the latest emailed link works.
+	*/
+	InvalidateAllForUser(ctx context.Context, userID uuid.UUID, at time.Time) error
+}
+
+/*
+TOTPFactorRepository defines the contract for TOTP factor persistence.
+Secrets are stored encrypted.
+*/
+type TOTPFactorRepository interface {
+	/*
+		Save inserts the factor or replaces the user's existing factor.
+	*/
+	Save(ctx context.Context, factor *TOTPFactor) error
+
+	/*
+		FindByUserID retrieves the factor of the given user.
+		Returns ErrMFANotEnrolled if the user has no factor.
+	*/
+	FindByUserID(ctx context.Context, userID uuid.UUID) (*TOTPFactor, error)
+
+	/*
+		MarkStepUsed records the time step of an accepted code.
+		The update only applies if the step is newer than the last accepted one,
+		so a code cannot be replayed, even by concurrent requests.
+		Returns ErrInvalidMFACode if the step was already used.
+	*/
+	MarkStepUsed(ctx context.Context, userID uuid.UUID, step int64, at time.Time) error
+}
+
+/*
+RecoveryCodeRepository defines the contract for MFA recovery code persistence.
+Only code hashes are stored.
+*/
+type RecoveryCodeRepository interface {
+	/*
+		ReplaceForUser atomically deletes the user's existing codes and stores the given ones.
+	*/
+	ReplaceForUser(ctx context.Context, userID uuid.UUID, codes []*RecoveryCode) error
+
+	/*
+		Use marks the unused code with the given hash as used.
+		Returns ErrInvalidMFACode if the user has no unused code with that hash.
+	*/
+	Use(ctx context.Context, userID uuid.UUID, codeHash string, at time.Time) error
+}
+
+/*
+APIKeyRepository defines the contract for API key persistence.
+Only key hashes are stored.
+*/
+type APIKeyRepository interface {
+	/*
+		Save persists a newly created API key.
+	*/
+	Save(ctx context.Context, key *APIKey) error
+
+	/*
+		FindByHash retrieves an API key by the hash of its value, including revoked
+		and expired keys.
+		Returns ErrAPIKeyNotFound if no key matches.
+	*/
+	FindByHash(ctx context.Context, keyHash string) (*APIKey, error)
+
+	/*
+		ListByUserID retrieves the user's keys that have not been revoked, newest first.
+	*/
+	ListByUserID(ctx context.Context, userID uuid.UUID) ([]*APIKey, error)
+
+	/*
+		Revoke revokes one of the user's keys.
+		Returns ErrAPIKeyNotFound if the user has no active key with that ID.
+	*/
+	Revoke(ctx context.Context, id, userID uuid.UUID, at time.Time) error
+
+	/*
+		TouchLastUsed records when the key last authenticated a request.
+	*/
+	TouchLastUsed(ctx context.Context, id uuid.UUID, at time.Time) error
+}
+
+/*
+AuditLogRepository defines the contract for reading the audit log.
+Entries are written by the UserRepository in the same transaction as the
+change they describe, so there is no method to add them here.
+*/
+type AuditLogRepository interface {
+	/*
+		ListByTargetUser retrieves the entries about the given user that match
+		the filter, newest first, honouring the filter's Limit and Offset.
+	*/
+	ListByTargetUser(ctx context.Context, userID uuid.UUID, filter AuditFilter) ([]*AuditEntry, error)
+
+	/*
+		CountByTargetUser returns the number of entries about the given user that
+		match the filter, ignoring its Limit and Offset.
+	*/
+	CountByTargetUser(ctx context.Context, userID uuid.UUID, filter AuditFilter) (int64, error)
+}
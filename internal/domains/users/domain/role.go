@@ -0,0 +1,174 @@
+package domain
+
+import "github.com/google/uuid"
+
+/*
+Role is a value object describing what a user is allowed to do.
+Every user has exactly one role; new users are members.
+*/
+type Role string
+
+const (
+	// RoleAdmin can manage every user account, including roles
+	RoleAdmin Role = "admin"
+
+	// RoleSupport can look up any user account but not change it
+	RoleSupport Role = "support"
+
+	// RoleMember can only read and edit their own account
+	RoleMember Role = "member"
+)
+
+/*
+Permission names an action on user accounts that a role may be granted.
+*/
+type Permission string
+
+const (
+	// PermissionUsersList allows listing all user accounts
+	PermissionUsersList Permission = "users:list"
+
+	// PermissionUsersRead allows reading any user account
+	PermissionUsersRead Permission = "users:read"
+
+	// PermissionUsersUpdate allows editing any user account's profile
+	PermissionUsersUpdate Permission = "users:update"
+
+	// PermissionUsersDeactivate allows deactivating and reactivating any user account
+	PermissionUsersDeactivate Permission = "users:deactivate"
+
+	// PermissionUsersManageRoles allows changing the role of any user account
+	PermissionUsersManageRoles Permission = "users:manage_roles"
+
+	// PermissionUsersUnlock allows lifting the login lockout of any user account
+	PermissionUsersUnlock Permission = "users:unlock"
+
+	// PermissionUsersReadAudit allows reading the audit log of any user account
+	PermissionUsersReadAudit Permission = "users:read_audit"
+)
+
+// rolePermissions maps each role to the permissions it grants on other users' accounts
+var rolePermissions = map[Role][]Permission{
+	RoleAdmin: {
+		PermissionUsersList,
+		PermissionUsersRead,
+		PermissionUsersUpdate,
+		PermissionUsersDeactivate,
+		PermissionUsersManageRoles,
+		PermissionUsersUnlock,
+		PermissionUsersReadAudit,
+	},
+	RoleSupport: {
+		PermissionUsersRead,
+		PermissionUsersReadAudit,
+	},
+	RoleMember: {},
+}
+
+// selfPermissions are granted to every user on their own account regardless of role
+var selfPermissions = map[Permission]bool{
+	PermissionUsersRead:       true,
+	PermissionUsersUpdate:     true,
+	PermissionUsersDeactivate: true,
+}
+
+/*
+ParsePermission converts a string into a Permission, e.g. an API key scope.
+Returns ErrInvalidScope if the string is not a known permission.
+*/
+func ParsePermission(permission string) (Permission, error) {
+	switch p := Permission(permission); p {
+	case PermissionUsersList, PermissionUsersRead, PermissionUsersUpdate,
+		PermissionUsersDeactivate, PermissionUsersManageRoles, PermissionUsersUnlock,
+		PermissionUsersReadAudit:
+		return p, nil
+	default:
+		return "", ErrInvalidScope
+	}
+}
+
+/*
+ParseRole converts a string into a Role.
+Returns ErrInvalidRole if the string is not a known role.
+*/
+func ParseRole(role string) (Role, error) {
+	switch r := Role(role); r {
+	case RoleAdmin, RoleSupport, RoleMember:
+		return r, nil
+	default:
+		return "", ErrInvalidRole
+	}
+}
+
+/*
+String implements the Stringer interface for Role.
+*/
+func (r Role) String() string {
+	return string(r)
+}
+
+/*
+HasPermission reports whether the role grants the permission on any user account.
+*/
+func (r Role) HasPermission(permission Permission) bool {
+	for _, p := range rolePermissions[r] {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+/*
+Actor identifies who is performing an action, for authorization decisions.
+Scopes is nil for interactive sessions; for API keys it lists the only
+permissions the actor may use.
+*/
+type Actor struct {
+	UserID uuid.UUID
+	Role   Role
+	Scopes []Permission
+}
+
+/*
+InScope reports whether the actor's scopes include the permission.
+Actors without scopes are not restricted.
+*/
+func (a Actor) InScope(permission Permission) bool {
+	if a.Scopes == nil {
+		return true
+	}
+	for _, scope := range a.Scopes {
+		if scope == permission {
+			return true
+		}
+	}
+	return false
+}
+
+/*
+Authorize is the access control policy for user accounts.
+The permission must be within the actor's scopes, if any. It then allows the
+action when:
+  - the actor's role grants the permission, or
+  - the actor is acting on their own account and the permission is one every
+    user has on themselves (read, update, deactivate)
+
+Pass uuid.Nil as targetID for actions that are not about a single account (e.g. listing).
+Returns ErrUnauthorized if the action is not allowed.
+*/
+func Authorize(actor Actor, permission Permission, targetID uuid.UUID) error {
+	if !actor.InScope(permission) {
+		return ErrUnauthorized
+	}
+
+	if actor.Role.HasPermission(permission) {
+		return nil
+	}
+
+	if targetID != uuid.Nil && actor.UserID == targetID && selfPermissions[permission] {
+		return nil
+	}
+
+	return ErrUnauthorized
+}
@@ -0,0 +1,169 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestAuthorize(t *testing.T) {
+	self := uuid.New()
+	other := uuid.New()
+
+	admin := Actor{UserID: self, Role: RoleAdmin}
+	support := Actor{UserID: self, Role: RoleSupport}
+	member := Actor{UserID: self, Role: RoleMember}
+
+	tests := []struct {
+		name       string
+		actor      Actor
+		permission Permission
+		target     uuid.UUID
+		allowed    bool
+	}{
+		// Admins are granted every permission on any account
+		{name: "admin lists users", actor: admin, permission: PermissionUsersList, target: uuid.Nil, allowed: true},
+		{name: "admin reads another user", actor: admin, permission: PermissionUsersRead, target: other, allowed: true},
+		{name: "admin updates another user", actor: admin, permission: PermissionUsersUpdate, target: other, allowed: true},
+		{name: "admin deactivates another user", actor: admin, permission: PermissionUsersDeactivate, target: other, allowed: true},
+		{name: "admin manages roles", actor: admin, permission: PermissionUsersManageRoles, target: other, allowed: true},
+		{name: "admin manages own role", actor: admin, permission: PermissionUsersManageRoles, target: self, allowed: true},
+		{name: "admin unlocks another user", actor: admin, permission: PermissionUsersUnlock, target: other, allowed: true},
+		{name: "admin reads audit log", actor: admin, permission: PermissionUsersReadAudit, target: other, allowed: true},
+
+		// Support can look up accounts but not change them
+		{name: "support reads another user", actor: support, permission: PermissionUsersRead, target: other, allowed: true},
+		{name: "support reads audit log", actor: support, permission: PermissionUsersReadAudit, target: other, allowed: true},
+		{name: "support lists users", actor: support, permission: PermissionUsersList, target: uuid.Nil, allowed: false},
+		{name: "support updates another user", actor: support, permission: PermissionUsersUpdate, target: other, allowed: false},
+		{name: "support deactivates another user", actor: support, permission: PermissionUsersDeactivate, target: other, allowed: false},
+		{name: "support manages roles", actor: support, permission: PermissionUsersManageRoles, target: other, allowed: false},
+		{name: "support unlocks another user", actor: support, permission: PermissionUsersUnlock, target: other, allowed: false},
+		{name: "support updates self", actor: support, permission: PermissionUsersUpdate, target: self, allowed: true},
+
+		// Members only have the self permissions, and only on their own account
+		{name: "member reads self", actor: member, permission: PermissionUsersRead, target: self, allowed: true},
+		{name: "member updates self", actor: member, permission: PermissionUsersUpdate, target: self, allowed: true},
+		{name: "member deactivates self", actor: member, permission: PermissionUsersDeactivate, target: self, allowed: true},
+		{name: "member reads another user", actor: member, permission: PermissionUsersRead, target: other, allowed: false},
+		{name: "member updates another user", actor: member, permission: PermissionUsersUpdate, target: other, allowed: false},
+		{name: "member deactivates another user", actor: member, permission: PermissionUsersDeactivate, target: other, allowed: false},
+		{name: "member lists users", actor: member, permission: PermissionUsersList, target: uuid.Nil, allowed: false},
+		{name: "member manages own role", actor: member, permission: PermissionUsersManageRoles, target: self, allowed: false},
+		{name: "member unlocks self", actor: member, permission: PermissionUsersUnlock, target: self, allowed: false},
+		{name: "member reads own audit log", actor: member, permission: PermissionUsersReadAudit, target: self, allowed: false},
+		{name: "self permission without target", actor: member, permission: PermissionUsersRead, target: uuid.Nil, allowed: false},
+
+		// Unknown roles are granted nothing beyond the self permissions
+		{name: "unknown role reads another user", actor: Actor{UserID: self, Role: "guest"}, permission: PermissionUsersRead, target: other, allowed: false},
+		{name: "unknown role reads self", actor: Actor{UserID: self, Role: "guest"}, permission: PermissionUsersRead, target: self, allowed: true},
+
+		// API key scopes narrow what the role allows, never widen it
+		{
+			name:       "scoped admin within scope",
+			actor:      Actor{UserID: self, Role: RoleAdmin, Scopes: []Permission{PermissionUsersRead}},
+			permission: PermissionUsersRead,
+			target:     other,
+			allowed:    true,
+		},
+		{
+			name:       "scoped admin outside scope",
+			actor:      Actor{UserID: self, Role: RoleAdmin, Scopes: []Permission{PermissionUsersRead}},
+			permission: PermissionUsersUpdate,
+			target:     other,
+			allowed:    false,
+		},
+		{
+			name:       "scoped member self permission outside scope",
+			actor:      Actor{UserID: self, Role: RoleMember, Scopes: []Permission{PermissionUsersRead}},
+			permission: PermissionUsersDeactivate,
+			target:     self,
+			allowed:    false,
+		},
+		{
+			name:       "scoped member self permission within scope",
+			actor:      Actor{UserID: self, Role: RoleMember, Scopes: []Permission{PermissionUsersUpdate}},
+			permission: PermissionUsersUpdate,
+			target:     self,
+			allowed:    true,
+		},
+		{
+			name:       "scope beyond the role",
+			actor:      Actor{UserID: self, Role: RoleMember, Scopes: []Permission{PermissionUsersList}},
+			permission: PermissionUsersList,
+			target:     uuid.Nil,
+			allowed:    false,
+		},
+		{
+			name:       "empty scopes allow nothing",
+			actor:      Actor{UserID: self, Role: RoleAdmin, Scopes: []Permission{}},
+			permission: PermissionUsersRead,
+			target:     self,
+			allowed:    false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Authorize(tt.actor, tt.permission, tt.target)
+
+			if tt.allowed && err != nil {
+				t.Errorf("Authorize() = %v, want nil", err)
+			}
+			if !tt.allowed && !errors.Is(err, ErrUnauthorized) {
+				t.Errorf("Authorize() = %v, want %v", err, ErrUnauthorized)
+			}
+		})
+	}
+}
+
+func TestParsePermission(t *testing.T) {
+	for permission := range allPermissions() {
+		got, err := ParsePermission(string(permission))
+		if err != nil || got != permission {
+			t.Errorf("ParsePermission(%q) = %q, %v, want %q", permission, got, err, permission)
+		}
+	}
+
+	for _, invalid := range []string{"", "admin", "users:delete", "USERS:READ"} {
+		if _, err := ParsePermission(invalid); !errors.Is(err, ErrInvalidScope) {
+			t.Errorf("ParsePermission(%q) error = %v, want %v", invalid, err, ErrInvalidScope)
+		}
+	}
+}
+
+func TestParseRole(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    Role
+		wantErr error
+	}{
+		{input: "admin", want: RoleAdmin},
+		{input: "support", want: RoleSupport},
+		{input: "member", want: RoleMember},
+		{input: "Admin", wantErr: ErrInvalidRole},
+		{input: "", wantErr: ErrInvalidRole},
+		{input: "owner", wantErr: ErrInvalidRole},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseRole(tt.input)
+			if got != tt.want || !errors.Is(err, tt.wantErr) {
+				t.Errorf("ParseRole(%q) = %q, %v, want %q, %v", tt.input, got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+// allPermissions returns every permission granted to some role
+func allPermissions() map[Permission]bool {
+	permissions := make(map[Permission]bool)
+	for _, granted := range rolePermissions {
+		for _, permission := range granted {
+			permissions[permission] = true
+		}
+	}
+	return permissions
+}
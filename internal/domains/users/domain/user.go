@@ -14,15 +14,32 @@ import (
 User represents a user entity in the domain.
 This is the core business object with identity and lifecycle.
 Contains business rules and behavior related to users.
+Significant changes are recorded as domain events, see PullEvents.
 */
 type User struct {
-	ID           uuid.UUID
-	Email        Email
-	Name         string
-	PasswordHash string
-	IsActive     bool
-	CreatedAt    time.Time
-	UpdatedAt    time.Time
+	ID              uuid.UUID
+	Email           Email
+	Name            string
+	PasswordHash    string
+	Role            Role
+	IsActive        bool
+	EmailVerifiedAt *time.Time
+	// Failed login tracking, see RecordFailedLogin
+	FailedLoginAttempts int
+	LockoutCount        int
+	LockedUntil         *time.Time
+	CreatedAt           time.Time
+	UpdatedAt           time.Time
+	// Version is incremented by the repository on every update; an update
+	// based on an older version fails with ErrConcurrentModification
+	Version int
+
+	// events recorded since the user was loaded, not yet published
+	events []Event
+
+	// passwordRehashed is set when the hash last changed through RehashPassword,
+	// so the audit log does not report it as a password change
+	passwordRehashed bool
 }
 
 /*
@@ -78,8 +95,12 @@ This is a factory function that enforces business rules:
   - Email must be valid
   - Name must not be empty
   - Password hash must not be empty
+  - New users are members by default
   - New users are active by default
+  - New users have an unverified email address
   - Timestamps are set to current time
+  - The version starts at 1
+  - A UserRegistered event is recorded
 
 Returns an error if any validation fails.
 Example:
@@ -98,15 +119,26 @@ func NewUser(email Email, name, passwordHash string) (*User, error) {
 
 	now := time.Now()
 
-	return &User{
+	user := &User{
 		ID:           uuid.New(),
 		Email:        email,
 		Name:         name,
 		PasswordHash: passwordHash,
+		Role:         RoleMember,
 		IsActive:     true,
 		CreatedAt:    now,
 		UpdatedAt:    now,
-	}, nil
+		Version:      1,
+	}
+
+	user.record(UserRegistered{
+		UserEvent: user.newEvent(now),
+		Email:     email.Value(),
+		Name:      name,
+		Role:      user.Role.String(),
+	})
+
+	return user, nil
 }
 
 /*
@@ -114,6 +146,7 @@ UpdateProfile updates the user's name and email.
 This method enforces business rules:
   - Name must not be empty
   - Email must be valid
+  - Changing the email resets its verification and records a UserEmailChanged event
   - UpdatedAt timestamp is automatically updated
 
 Returns an error if validation fails.
@@ -123,9 +156,20 @@ func (u *User) UpdateProfile(name string, email Email) error {
 		return errors.New("name cannot be empty")
 	}
 
+	now := time.Now()
+
+	if email.Value() != u.Email.Value() {
+		u.EmailVerifiedAt = nil
+		u.record(UserEmailChanged{
+			UserEvent: u.newEvent(now),
+			OldEmail:  u.Email.Value(),
+			NewEmail:  email.Value(),
+		})
+	}
+
 	u.Name = name
 	u.Email = email
-	u.UpdatedAt = time.Now()
+	u.UpdatedAt = now
 
 	return nil
 }
@@ -134,30 +178,156 @@ func (u *User) UpdateProfile(name string, email Email) error {
 Deactivate marks the user as inactive (soft delete).
 This implements the soft delete pattern - the user record remains in the database
 but is marked as inactive and won't appear in normal queries.
+Records a UserDeactivated event if the user was active.
 UpdatedAt timestamp is automatically updated.
 */
 func (u *User) Deactivate() {
+	now := time.Now()
+
+	if u.IsActive {
+		u.record(UserDeactivated{UserEvent: u.newEvent(now)})
+	}
+
 	u.IsActive = false
-	u.UpdatedAt = time.Now()
+	u.UpdatedAt = now
 }
 
 /*
 Activate marks the user as active.
 Used to restore a previously deactivated user.
+Records a UserReactivated event if the user was inactive.
 UpdatedAt timestamp is automatically updated.
 */
 func (u *User) Activate() {
+	now := time.Now()
+
+	if !u.IsActive {
+		u.record(UserReactivated{UserEvent: u.newEvent(now)})
+	}
+
 	u.IsActive = true
+	u.UpdatedAt = now
+}
+
+/*
+VerifyEmail marks the user's current email address as verified.
+Verifying an already verified email keeps the original timestamp.
+UpdatedAt timestamp is automatically updated.
+*/
+func (u *User) VerifyEmail() {
+	if u.EmailVerifiedAt != nil {
+		return
+	}
+
+	now := time.Now()
+	u.EmailVerifiedAt = &now
+	u.UpdatedAt = now
+}
+
+/*
+IsEmailVerified reports whether the user has verified their email address.
+*/
+func (u *User) IsEmailVerified() bool {
+	return u.EmailVerifiedAt != nil
+}
+
+/*
+IsLocked reports whether password login is locked at the given instant.
+*/
+func (u *User) IsLocked(now time.Time) bool {
+	return u.LockedUntil != nil && now.Before(*u.LockedUntil)
+}
+
+/*
+RecordFailedLogin counts a failed password (or second factor) attempt.
+When the policy threshold is reached the account is locked for an escalating
+period and the failure count starts over.
+Does nothing if the policy is disabled.
+*/
+func (u *User) RecordFailedLogin(policy LockoutPolicy, now time.Time) {
+	if !policy.Enabled() {
+		return
+	}
+
+	u.FailedLoginAttempts++
+	u.UpdatedAt = now
+
+	if u.FailedLoginAttempts < policy.Threshold {
+		return
+	}
+
+	u.LockoutCount++
+	lockedUntil := now.Add(policy.LockDuration(u.LockoutCount))
+	u.LockedUntil = &lockedUntil
+	u.FailedLoginAttempts = 0
+}
+
+/*
+RecordSuccessfulLogin clears failed attempts and lockout escalation.
+Returns true if anything changed, so callers can skip a needless write.
+*/
+func (u *User) RecordSuccessfulLogin() bool {
+	if u.FailedLoginAttempts == 0 && u.LockoutCount == 0 && u.LockedUntil == nil {
+		return false
+	}
+
+	u.FailedLoginAttempts = 0
+	u.LockoutCount = 0
+	u.LockedUntil = nil
+	u.UpdatedAt = time.Now()
+
+	return true
+}
+
+/*
+Unlock lifts a lockout and clears failed attempts, e.g. when an administrator
+has confirmed the account owner's identity.
+UpdatedAt timestamp is automatically updated.
+*/
+func (u *User) Unlock() {
+	u.FailedLoginAttempts = 0
+	u.LockoutCount = 0
+	u.LockedUntil = nil
 	u.UpdatedAt = time.Now()
 }
 
 /*
 ChangePassword updates the user's password hash.
 The password should already be hashed before calling this method.
+Records a UserPasswordChanged event.
 UpdatedAt timestamp is automatically updated.
 Returns an error if the password hash is empty.
 */
 func (u *User) ChangePassword(passwordHash string) error {
+	if err := u.setPasswordHash(passwordHash); err != nil {
+		return err
+	}
+	u.passwordRehashed = false
+
+	u.record(UserPasswordChanged{UserEvent: u.newEvent(u.UpdatedAt)})
+
+	return nil
+}
+
+/*
+RehashPassword replaces the password hash with a new hash of the same password,
+e.g. after the hashing parameters were strengthened.
+Unlike ChangePassword it records no event and is not audited, since the
+password itself is unchanged.
+UpdatedAt timestamp is automatically updated.
+Returns an error if the password hash is empty.
+*/
+func (u *User) RehashPassword(passwordHash string) error {
+	if err := u.setPasswordHash(passwordHash); err != nil {
+		return err
+	}
+	u.passwordRehashed = true
+
+	return nil
+}
+
+// setPasswordHash stores a new password hash, rejecting an empty one
+func (u *User) setPasswordHash(passwordHash string) error {
 	if passwordHash == "" {
 		return errors.New("password hash cannot be empty")
 	}
@@ -168,6 +338,52 @@ func (u *User) ChangePassword(passwordHash string) error {
 	return nil
 }
 
+/*
+ChangeRole assigns a new role to the user.
+UpdatedAt timestamp is automatically updated.
+Returns ErrInvalidRole if the role is not a known role.
+*/
+func (u *User) ChangeRole(role Role) error {
+	if _, err := ParseRole(string(role)); err != nil {
+		return err
+	}
+
+	u.Role = role
+	u.UpdatedAt = time.Now()
+
+	return nil
+}
+
+/*
+Events returns the events recorded since the user was created or loaded, in
+the order they happened, without clearing them.
+Repositories use it to store the events along with the user's changes.
+*/
+func (u *User) Events() []Event {
+	return u.events
+}
+
+/*
+PullEvents returns the events recorded since the user was created or loaded,
+in the order they happened, and clears them so they are only published once.
+Call it after the changes have been persisted.
+*/
+func (u *User) PullEvents() []Event {
+	events := u.events
+	u.events = nil
+	return events
+}
+
+// record appends a domain event to be published once the user is persisted
+func (u *User) record(event Event) {
+	u.events = append(u.events, event)
+}
+
+// newEvent returns the common fields of an event about this user
+func (u *User) newEvent(now time.Time) UserEvent {
+	return UserEvent{ID: uuid.New(), UserID: u.ID, At: now}
+}
+
 /*
 Validate checks if the user entity is in a valid state.
 This is useful before persisting the user to the database.
@@ -190,6 +406,10 @@ func (u *User) Validate() error {
 		return errors.New("password hash cannot be empty")
 	}
 
+	if _, err := ParseRole(string(u.Role)); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -199,6 +419,6 @@ Useful for logging and debugging.
 Does not include sensitive information like password hash.
 */
 func (u *User) String() string {
-	return fmt.Sprintf("User{ID: %s, Email: %s, Name: %s, IsActive: %t}",
-		u.ID, u.Email, u.Name, u.IsActive)
+	return fmt.Sprintf("User{ID: %s, Email: %s, Name: %s, Role: %s, IsActive: %t, EmailVerified: %t}",
+		u.ID, u.Email, u.Name, u.Role, u.IsActive, u.IsEmailVerified())
 }
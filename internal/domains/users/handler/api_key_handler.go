@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/domains/users/application"
+	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/platform/logger"
+	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/platform/problem"
+	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/platform/validation"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+/*
+APIKeyHandler handles HTTP requests for managing the caller's personal API keys.
+It delegates to the application APIKeyService.
+*/
+type APIKeyHandler struct {
+	apiKeyService *application.APIKeyService
+	logger        *logger.Logger
+}
+
+/*
+NewAPIKeyHandler creates a new APIKeyHandler instance.
+Requires an APIKeyService for API key logic and a Logger for request logging.
+*/
+func NewAPIKeyHandler(apiKeyService *application.APIKeyService, log *logger.Logger) *APIKeyHandler {
+	return &APIKeyHandler{
+		apiKeyService: apiKeyService,
+		logger:        log,
+	}
+}
+
+/*
+CreateAPIKey handles POST /api-keys - Create an API key for the authenticated user.
+The secret key is only returned in this response.
+Request body: CreateAPIKeyRequest
+Response: 201 Created with APIKeyResponse including the key
+Errors: 400 Bad Request (invalid name, scopes or expiry), 401 Unauthorized, 403 Forbidden (called with an API key), 422 Unprocessable Entity (validation failed), 500 Internal Server Error
+*/
+func (h *APIKeyHandler) CreateAPIKey(c *fiber.Ctx) error {
+	var req CreateAPIKeyRequest
+
+	// Parse request body
+	if err := c.BodyParser(&req); err != nil {
+		return problem.New(http.StatusBadRequest, "invalid_request", "Invalid request body")
+	}
+
+	// Validate request body
+	if err := validation.Struct(&req); err != nil {
+		return handleError(c, err)
+	}
+
+	// Call service
+	created, err := h.apiKeyService.CreateAPIKey(c.UserContext(), application.CreateAPIKeyDTO{
+		Name:      req.Name,
+		Scopes:    req.Scopes,
+		ExpiresAt: req.ExpiresAt,
+	})
+	if err != nil {
+		return handleError(c, err)
+	}
+
+	// Return response with the key, which cannot be retrieved again
+	response := toAPIKeyResponse(&created.APIKeyDTO)
+	response.Key = created.Key
+	return c.Status(http.StatusCreated).JSON(response)
+}
+
+/*
+ListAPIKeys handles GET /api-keys - List the authenticated user's active API keys.
+Response: 200 OK with APIKeyListResponse
+Errors: 401 Unauthorized, 403 Forbidden (called with an API key), 500 Internal Server Error
+*/
+func (h *APIKeyHandler) ListAPIKeys(c *fiber.Ctx) error {
+	// Call service
+	keys, err := h.apiKeyService.ListAPIKeys(c.UserContext())
+	if err != nil {
+		return handleError(c, err)
+	}
+
+	// Return response
+	return c.Status(http.StatusOK).JSON(toAPIKeyListResponse(keys))
+}
+
+/*
+RevokeAPIKey handles DELETE /api-keys/:id - Revoke one of the authenticated user's API keys.
+Path parameter: id (UUID)
+Response: 204 No Content
+Errors: 400 Bad Request, 401 Unauthorized, 403 Forbidden (called with an API key), 404 Not Found, 500 Internal Server Error
+*/
+func (h *APIKeyHandler) RevokeAPIKey(c *fiber.Ctx) error {
+	// Parse ID from path
+	idStr := c.Params("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return problem.New(http.StatusBadRequest, "invalid_id", "Invalid API key ID format")
+	}
+
+	// Call service
+	if err := h.apiKeyService.RevokeAPIKey(c.UserContext(), id); err != nil {
+		return handleError(c, err)
+	}
+
+	// Return no content
+	return c.SendStatus(http.StatusNoContent)
+}
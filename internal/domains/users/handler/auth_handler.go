@@ -0,0 +1,352 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/domains/users/application"
+	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/platform/logger"
+	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/platform/problem"
+	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/platform/validation"
+	"github.com/gofiber/fiber/v2"
+)
+
+/*
+AuthHandler handles HTTP requests for authentication endpoints.
+It delegates credential checks and token issuing to the application AuthService.
+*/
+type AuthHandler struct {
+	authService *application.AuthService
+	logger      *logger.Logger
+}
+
+/*
+NewAuthHandler creates a new AuthHandler instance.
+Requires an AuthService for authentication logic and a Logger for request logging.
+*/
+func NewAuthHandler(authService *application.AuthService, log *logger.Logger) *AuthHandler {
+	return &AuthHandler{
+		authService: authService,
+		logger:      log,
+	}
+}
+
+/*
+Login handles POST /auth/login - Authenticate with email and password.
+Users with two-factor authentication enabled receive an MFA challenge instead of
+tokens, to be completed with POST /auth/login/mfa.
+Request body: LoginRequest
+Response: 200 OK with TokenResponse, or MFAChallengeResponse when a second factor is required
+Errors: 400 Bad Request, 401 Unauthorized (invalid credentials), 403 Forbidden (inactive user or unverified email), 422 Unprocessable Entity (validation failed), 423 Locked (account locked), 429 Too Many Requests, 500 Internal Server Error
+*/
+func (h *AuthHandler) Login(c *fiber.Ctx) error {
+	var req LoginRequest
+
+	// Parse request body
+	if err := c.BodyParser(&req); err != nil {
+		return problem.New(http.StatusBadRequest, "invalid_request", "Invalid request body")
+	}
+
+	// Validate request body
+	if err := validation.Struct(&req); err != nil {
+		return handleError(c, err)
+	}
+
+	// Convert to DTO
+	dto := application.LoginDTO{
+		Email:    req.Email,
+		Password: req.Password,
+		ClientIP: c.IP(),
+	}
+
+	// Call service
+	result, err := h.authService.Login(c.UserContext(), dto)
+	if err != nil {
+		return handleError(c, err)
+	}
+
+	// Return response
+	if result.MFAChallenge != nil {
+		return c.Status(http.StatusOK).JSON(toMFAChallengeResponse(result.MFAChallenge))
+	}
+	return c.Status(http.StatusOK).JSON(toTokenResponse(result.Tokens))
+}
+
+/*
+LoginMFA handles POST /auth/login/mfa - Complete a two-factor login with a TOTP or recovery code.
+Request body: MFALoginRequest
+Response: 200 OK with TokenResponse
+Errors: 400 Bad Request, 401 Unauthorized (invalid challenge or code), 422 Unprocessable Entity (validation failed), 423 Locked (account locked), 429 Too Many Requests, 500 Internal Server Error
+*/
+func (h *AuthHandler) LoginMFA(c *fiber.Ctx) error {
+	var req MFALoginRequest
+
+	// Parse request body
+	if err := c.BodyParser(&req); err != nil {
+		return problem.New(http.StatusBadRequest, "invalid_request", "Invalid request body")
+	}
+
+	// Validate request body
+	if err := validation.Struct(&req); err != nil {
+		return handleError(c, err)
+	}
+
+	// Call service
+	tokens, err := h.authService.CompleteMFALogin(c.UserContext(), application.MFALoginDTO{
+		MFAToken: req.MFAToken,
+		Code:     req.Code,
+		ClientIP: c.IP(),
+	})
+	if err != nil {
+		return handleError(c, err)
+	}
+
+	// Return response
+	return c.Status(http.StatusOK).JSON(toTokenResponse(tokens))
+}
+
+/*
+Refresh handles POST /auth/refresh - Exchange a refresh token for new tokens.
+The presented refresh token is rotated: it cannot be used again.
+Request body: RefreshTokenRequest
+Response: 200 OK with TokenResponse
+Errors: 400 Bad Request, 401 Unauthorized (invalid or reused refresh token), 422 Unprocessable Entity (validation failed), 500 Internal Server Error
+*/
+func (h *AuthHandler) Refresh(c *fiber.Ctx) error {
+	var req RefreshTokenRequest
+
+	// Parse request body
+	if err := c.BodyParser(&req); err != nil {
+		return problem.New(http.StatusBadRequest, "invalid_request", "Invalid request body")
+	}
+
+	// Validate request body
+	if err := validation.Struct(&req); err != nil {
+		return handleError(c, err)
+	}
+
+	// Call service
+	tokens, err := h.authService.Refresh(c.UserContext(), application.RefreshTokenDTO{
+		RefreshToken: req.RefreshToken,
+	})
+	if err != nil {
+		return handleError(c, err)
+	}
+
+	// Return response
+	return c.Status(http.StatusOK).JSON(toTokenResponse(tokens))
+}
+
+/*
+Logout handles POST /auth/logout - Revoke a refresh token and its token family.
+Request body: RefreshTokenRequest
+Response: 204 No Content
+Errors: 400 Bad Request, 422 Unprocessable Entity (validation failed), 500 Internal Server Error
+*/
+func (h *AuthHandler) Logout(c *fiber.Ctx) error {
+	var req RefreshTokenRequest
+
+	// Parse request body
+	if err := c.BodyParser(&req); err != nil {
+		return problem.New(http.StatusBadRequest, "invalid_request", "Invalid request body")
+	}
+
+	// Validate request body
+	if err := validation.Struct(&req); err != nil {
+		return handleError(c, err)
+	}
+
+	// Call service
+	if err := h.authService.Logout(c.UserContext(), application.RefreshTokenDTO{
+		RefreshToken: req.RefreshToken,
+	}); err != nil {
+		return handleError(c, err)
+	}
+
+	// Return no content
+	return c.SendStatus(http.StatusNoContent)
+}
+
+/*
+ForgotPassword handles POST /auth/password/forgot - Email a password reset link.
+The response is the same whether or not the email is registered.
+Request body: ForgotPasswordRequest
+Response: 202 Accepted with success message
+Errors: 400 Bad Request, 422 Unprocessable Entity (validation failed), 429 Too Many Requests, 500 Internal Server Error
+*/
+func (h *AuthHandler) ForgotPassword(c *fiber.Ctx) error {
+	var req ForgotPasswordRequest
+
+	// Parse request body
+	if err := c.BodyParser(&req); err != nil {
+		return problem.New(http.StatusBadRequest, "invalid_request", "Invalid request body")
+	}
+
+	// Validate request body
+	if err := validation.Struct(&req); err != nil {
+		return handleError(c, err)
+	}
+
+	// Call service
+	if err := h.authService.ForgotPassword(c.UserContext(), application.ForgotPasswordDTO{
+		Email:    req.Email,
+		ClientIP: c.IP(),
+	}); err != nil {
+		return handleError(c, err)
+	}
+
+	// Return the same response for registered and unknown emails
+	return c.Status(http.StatusAccepted).JSON(SuccessResponse{
+		Message: "If the email is registered, a password reset link has been sent",
+	})
+}
+
+/*
+ResetPassword handles POST /auth/password/reset - Set a new password with a reset token.
+Request body: ResetPasswordRequest
+Response: 200 OK with success message
+Errors: 400 Bad Request (invalid or expired token), 422 Unprocessable Entity (validation failed or weak password), 500 Internal Server Error
+*/
+func (h *AuthHandler) ResetPassword(c *fiber.Ctx) error {
+	var req ResetPasswordRequest
+
+	// Parse request body
+	if err := c.BodyParser(&req); err != nil {
+		return problem.New(http.StatusBadRequest, "invalid_request", "Invalid request body")
+	}
+
+	// Validate request body
+	if err := validation.Struct(&req); err != nil {
+		return handleError(c, err)
+	}
+
+	// Call service
+	if err := h.authService.ResetPassword(c.UserContext(), application.ResetPasswordDTO{
+		Token:       req.Token,
+		NewPassword: req.NewPassword,
+	}); err != nil {
+		return handleError(c, err)
+	}
+
+	// Return success
+	return c.Status(http.StatusOK).JSON(SuccessResponse{
+		Message: "Password has been reset",
+	})
+}
+
+/*
+VerifyEmail handles POST /auth/verify-email - Verify an email address with an emailed token.
+Request body: VerifyEmailRequest
+Response: 200 OK with success message
+Errors: 400 Bad Request (invalid or expired token), 422 Unprocessable Entity (validation failed), 500 Internal Server Error
+*/
+func (h *AuthHandler) VerifyEmail(c *fiber.Ctx) error {
+	var req VerifyEmailRequest
+
+	// Parse request body
+	if err := c.BodyParser(&req); err != nil {
+		return problem.New(http.StatusBadRequest, "invalid_request", "Invalid request body")
+	}
+
+	// Validate request body
+	if err := validation.Struct(&req); err != nil {
+		return handleError(c, err)
+	}
+
+	// Call service
+	if err := h.authService.VerifyEmail(c.UserContext(), application.VerifyEmailDTO{
+		Token: req.Token,
+	}); err != nil {
+		return handleError(c, err)
+	}
+
+	// Return success
+	return c.Status(http.StatusOK).JSON(SuccessResponse{
+		Message: "Email address has been verified",
+	})
+}
+
+/*
+ResendVerification handles POST /auth/verify-email/resend - Email a new verification link.
+The response is the same whether or not the email is registered or already verified.
+Request body: ResendVerificationRequest
+Response: 202 Accepted with success message
+Errors: 400 Bad Request, 422 Unprocessable Entity (validation failed), 500 Internal Server Error
+*/
+func (h *AuthHandler) ResendVerification(c *fiber.Ctx) error {
+	var req ResendVerificationRequest
+
+	// Parse request body
+	if err := c.BodyParser(&req); err != nil {
+		return problem.New(http.StatusBadRequest, "invalid_request", "Invalid request body")
+	}
+
+	// Validate request body
+	if err := validation.Struct(&req); err != nil {
+		return handleError(c, err)
+	}
+
+	// Call service
+	if err := h.authService.ResendVerificationEmail(c.UserContext(), application.ResendVerificationDTO{
+		Email: req.Email,
+	}); err != nil {
+		return handleError(c, err)
+	}
+
+	// Return the same response for every email
+	return c.Status(http.StatusAccepted).JSON(SuccessResponse{
+		Message: "If the email is registered and unverified, a verification link has been sent",
+	})
+}
+
+/*
+EnrollTOTP handles POST /auth/mfa/totp/enroll - Start TOTP enrolment for the authenticated user.
+Restarting an unconfirmed enrolment replaces its secret.
+Response: 200 OK with TOTPEnrollmentResponse
+Errors: 401 Unauthorized, 409 Conflict (already enabled), 500 Internal Server Error
+*/
+func (h *AuthHandler) EnrollTOTP(c *fiber.Ctx) error {
+	// Call service
+	enrollment, err := h.authService.EnrollTOTP(c.UserContext())
+	if err != nil {
+		return handleError(c, err)
+	}
+
+	// Return response
+	return c.Status(http.StatusOK).JSON(TOTPEnrollmentResponse{
+		Secret:     enrollment.Secret,
+		OTPAuthURI: enrollment.OTPAuthURI,
+	})
+}
+
+/*
+ConfirmTOTP handles POST /auth/mfa/totp/confirm - Enable TOTP with a code from the authenticator app.
+Request body: ConfirmTOTPRequest
+Response: 200 OK with RecoveryCodesResponse
+Errors: 400 Bad Request, 401 Unauthorized (invalid code), 409 Conflict (not enrolled or already enabled), 422 Unprocessable Entity (validation failed), 500 Internal Server Error
+*/
+func (h *AuthHandler) ConfirmTOTP(c *fiber.Ctx) error {
+	var req ConfirmTOTPRequest
+
+	// Parse request body
+	if err := c.BodyParser(&req); err != nil {
+		return problem.New(http.StatusBadRequest, "invalid_request", "Invalid request body")
+	}
+
+	// Validate request body
+	if err := validation.Struct(&req); err != nil {
+		return handleError(c, err)
+	}
+
+	// Call service
+	codes, err := h.authService.ConfirmTOTP(c.UserContext(), application.ConfirmTOTPDTO{
+		Code: req.Code,
+	})
+	if err != nil {
+		return handleError(c, err)
+	}
+
+	// Return response
+	return c.Status(http.StatusOK).JSON(RecoveryCodesResponse{
+		RecoveryCodes: codes.RecoveryCodes,
+	})
+}
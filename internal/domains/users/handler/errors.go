@@ -0,0 +1,42 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/domains/users/domain"
+	apperrors "github.com/dzikrisyairozi/go-ddd-clean-starter/internal/platform/errors"
+)
+
+/*
+RegisterErrors registers the users domain errors with the error registry.
+Each sentinel gets a stable code, the HTTP status it is reported with and a
+client-facing message; errors wrapping them are rendered accordingly.
+Codes are part of the API contract and must not change once published.
+*/
+func RegisterErrors(registry *apperrors.Registry) {
+	registry.Register(domain.ErrUserNotFound, apperrors.Definition{Code: "user_not_found", Status: http.StatusNotFound, Message: "User not found"})
+	registry.Register(domain.ErrConcurrentModification, apperrors.Definition{Code: "concurrent_modification", Status: http.StatusConflict, Message: "User was modified by another request; reload it and try again"})
+	registry.Register(domain.ErrEmailAlreadyExists, apperrors.Definition{Code: "email_already_exists", Status: http.StatusConflict, Message: "Email already exists"})
+	registry.Register(domain.ErrInvalidEmail, apperrors.Definition{Code: "invalid_email", Status: http.StatusBadRequest, Message: "Invalid email format"})
+	registry.Register(domain.ErrInvalidRole, apperrors.Definition{Code: "invalid_role", Status: http.StatusBadRequest, Message: "Role must be one of: admin, support, member"})
+	registry.Register(domain.ErrInvalidPassword, apperrors.Definition{Code: "invalid_password", Status: http.StatusUnauthorized, Message: "Invalid password"})
+	registry.Register(domain.ErrAccountLocked, apperrors.Definition{Code: "account_locked", Status: http.StatusLocked, Message: "Account is temporarily locked after too many failed attempts"})
+	registry.Register(domain.ErrTooManyLoginAttempts, apperrors.Definition{Code: "too_many_attempts", Status: http.StatusTooManyRequests, Message: "Too many failed attempts, please try again later"})
+	registry.Register(domain.ErrTooManyResetRequests, apperrors.Definition{Code: "too_many_requests", Status: http.StatusTooManyRequests, Message: "Too many password reset requests, please try again later"})
+	registry.Register(domain.ErrInvalidCredentials, apperrors.Definition{Code: "invalid_credentials", Status: http.StatusUnauthorized, Message: "Invalid email or password"})
+	registry.Register(domain.ErrInvalidRefreshToken, apperrors.Definition{Code: "invalid_refresh_token", Status: http.StatusUnauthorized, Message: "Refresh token is invalid or expired"})
+	registry.Register(domain.ErrRefreshTokenReused, apperrors.Definition{Code: "refresh_token_reused", Status: http.StatusUnauthorized, Message: "Refresh token was already used; please log in again"})
+	registry.Register(domain.ErrInvalidResetToken, apperrors.Definition{Code: "invalid_reset_token", Status: http.StatusBadRequest, Message: "Password reset link is invalid or has expired"})
+	registry.Register(domain.ErrInvalidVerificationToken, apperrors.Definition{Code: "invalid_verification_token", Status: http.StatusBadRequest, Message: "Verification link is invalid or has expired"})
+	registry.Register(domain.ErrEmailNotVerified, apperrors.Definition{Code: "email_not_verified", Status: http.StatusForbidden, Message: "Email address has not been verified"})
+	registry.Register(domain.ErrInvalidMFACode, apperrors.Definition{Code: "invalid_mfa_code", Status: http.StatusUnauthorized, Message: "Authentication code is invalid"})
+	registry.Register(domain.ErrInvalidMFAChallenge, apperrors.Definition{Code: "invalid_mfa_token", Status: http.StatusUnauthorized, Message: "MFA challenge is invalid or has expired, please log in again"})
+	registry.Register(domain.ErrMFANotEnrolled, apperrors.Definition{Code: "mfa_not_enrolled", Status: http.StatusConflict, Message: "Two-factor enrolment has not been started"})
+	registry.Register(domain.ErrMFAAlreadyEnabled, apperrors.Definition{Code: "mfa_already_enabled", Status: http.StatusConflict, Message: "Two-factor authentication is already enabled"})
+	registry.Register(domain.ErrAPIKeyNotFound, apperrors.Definition{Code: "api_key_not_found", Status: http.StatusNotFound, Message: "API key not found"})
+	registry.Register(domain.ErrInvalidAPIKeyName, apperrors.Definition{Code: "invalid_api_key_name", Status: http.StatusBadRequest, Message: "API key name must be between 1 and 100 characters"})
+	registry.Register(domain.ErrInvalidAPIKeyExpiry, apperrors.Definition{Code: "invalid_api_key_expiry", Status: http.StatusBadRequest, Message: "API key expiry must be in the future"})
+	registry.Register(domain.ErrInvalidScope, apperrors.Definition{Code: "invalid_scope", Status: http.StatusBadRequest, Message: "Scopes must name at least one known permission"})
+	registry.Register(domain.ErrUnauthorized, apperrors.Definition{Code: "forbidden", Status: http.StatusForbidden, Message: "You are not allowed to perform this action"})
+	registry.Register(domain.ErrUserInactive, apperrors.Definition{Code: "user_inactive", Status: http.StatusForbidden, Message: "User account is inactive"})
+}
@@ -0,0 +1,53 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/platform/problem"
+	"github.com/gofiber/fiber/v2"
+)
+
+/*
+ETags for user resources.
+A user's ETag is its version as a quoted string (e.g. "3"). Clients send it back
+in If-Match on update and delete, so a change based on a stale copy of the user
+fails with 409 Conflict instead of overwriting someone else's change.
+*/
+
+// setETag sets the ETag header to the user's version
+func setETag(c *fiber.Ctx, version int) {
+	c.Set(fiber.HeaderETag, strconv.Quote(strconv.Itoa(version)))
+}
+
+/*
+ifMatchVersion returns the user version required by the If-Match header.
+Returns nil if the header is absent or "*", since both allow any version.
+Weak ETags (W/"3") are accepted as well. Returns a 400 problem if the header is
+not a single user ETag.
+*/
+func ifMatchVersion(c *fiber.Ctx) (*int, error) {
+	header := strings.TrimSpace(c.Get(fiber.HeaderIfMatch))
+	if header == "" || header == "*" {
+		return nil, nil
+	}
+
+	tag := strings.TrimPrefix(header, "W/")
+	unquoted, err := strconv.Unquote(tag)
+	if err != nil || !strings.HasPrefix(tag, `"`) {
+		return nil, invalidIfMatch()
+	}
+
+	version, err := strconv.Atoi(unquoted)
+	if err != nil || version < 1 {
+		return nil, invalidIfMatch()
+	}
+
+	return &version, nil
+}
+
+// invalidIfMatch is the problem returned for a malformed If-Match header
+func invalidIfMatch() error {
+	return problem.New(http.StatusBadRequest, "invalid_if_match", "If-Match must be a single user ETag, such as \"3\"")
+}
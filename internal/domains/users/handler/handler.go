@@ -2,11 +2,16 @@ package handler
 
 import (
 	"errors"
+	"math"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/domains/users/application"
 	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/domains/users/domain"
 	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/platform/logger"
+	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/platform/problem"
+	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/platform/validation"
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
 )
@@ -35,18 +40,20 @@ func NewUserHandler(userService *application.UserService, log *logger.Logger) *U
 /*
 CreateUser handles POST /users - Create a new user.
 Request body: CreateUserRequest
-Response: 201 Created with UserResponse
-Errors: 400 Bad Request, 409 Conflict (email exists), 500 Internal Server Error
+Response: 201 Created with UserResponse and the user's ETag
+Errors: 400 Bad Request, 409 Conflict (email exists), 422 Unprocessable Entity (validation failed or weak password), 500 Internal Server Error
 */
 func (h *UserHandler) CreateUser(c *fiber.Ctx) error {
 	var req CreateUserRequest
 
 	// Parse request body
 	if err := c.BodyParser(&req); err != nil {
-		return c.Status(http.StatusBadRequest).JSON(ErrorResponse{
-			Error:   "invalid_request",
-			Message: "Invalid request body",
-		})
+		return problem.New(http.StatusBadRequest, "invalid_request", "Invalid request body")
+	}
+
+	// Validate request body
+	if err := validation.Struct(&req); err != nil {
+		return handleError(c, err)
 	}
 
 	// Convert to DTO
@@ -57,39 +64,38 @@ func (h *UserHandler) CreateUser(c *fiber.Ctx) error {
 	}
 
 	// Call service
-	user, err := h.userService.CreateUser(c.Context(), dto)
+	user, err := h.userService.CreateUser(c.UserContext(), dto)
 	if err != nil {
-		return h.handleError(c, err)
+		return handleError(c, err)
 	}
 
 	// Return response
+	setETag(c, user.Version)
 	return c.Status(http.StatusCreated).JSON(toUserResponse(user))
 }
 
 /*
 GetUser handles GET /users/:id - Get a user by ID.
 Path parameter: id (UUID)
-Response: 200 OK with UserResponse
-Errors: 400 Bad Request (invalid ID), 404 Not Found, 500 Internal Server Error
+Response: 200 OK with UserResponse and the user's ETag
+Errors: 400 Bad Request (invalid ID), 403 Forbidden, 404 Not Found, 500 Internal Server Error
 */
 func (h *UserHandler) GetUser(c *fiber.Ctx) error {
 	// Parse ID from path
 	idStr := c.Params("id")
 	id, err := uuid.Parse(idStr)
 	if err != nil {
-		return c.Status(http.StatusBadRequest).JSON(ErrorResponse{
-			Error:   "invalid_id",
-			Message: "Invalid user ID format",
-		})
+		return problem.New(http.StatusBadRequest, "invalid_id", "Invalid user ID format")
 	}
 
 	// Call service
-	user, err := h.userService.GetUser(c.Context(), id)
+	user, err := h.userService.GetUser(c.UserContext(), id)
 	if err != nil {
-		return h.handleError(c, err)
+		return handleError(c, err)
 	}
 
 	// Return response
+	setETag(c, user.Version)
 	return c.Status(http.StatusOK).JSON(toUserResponse(user))
 }
 
@@ -97,65 +103,77 @@ func (h *UserHandler) GetUser(c *fiber.Ctx) error {
 UpdateUser handles PUT /users/:id - Update a user.
 Path parameter: id (UUID)
 Request body: UpdateUserRequest
-Response: 200 OK with UserResponse
-Errors: 400 Bad Request, 404 Not Found, 409 Conflict, 500 Internal Server Error
+Optional header: If-Match with the ETag the update is based on
+Response: 200 OK with UserResponse and the new ETag
+Errors: 400 Bad Request, 403 Forbidden, 404 Not Found, 409 Conflict (email exists or user modified concurrently), 422 Unprocessable Entity (validation failed), 500 Internal Server Error
 */
 func (h *UserHandler) UpdateUser(c *fiber.Ctx) error {
 	// Parse ID from path
 	idStr := c.Params("id")
 	id, err := uuid.Parse(idStr)
 	if err != nil {
-		return c.Status(http.StatusBadRequest).JSON(ErrorResponse{
-			Error:   "invalid_id",
-			Message: "Invalid user ID format",
-		})
+		return problem.New(http.StatusBadRequest, "invalid_id", "Invalid user ID format")
 	}
 
 	// Parse request body
 	var req UpdateUserRequest
 	if err := c.BodyParser(&req); err != nil {
-		return c.Status(http.StatusBadRequest).JSON(ErrorResponse{
-			Error:   "invalid_request",
-			Message: "Invalid request body",
-		})
+		return problem.New(http.StatusBadRequest, "invalid_request", "Invalid request body")
+	}
+
+	// Validate request body
+	if err := validation.Struct(&req); err != nil {
+		return handleError(c, err)
+	}
+
+	// Parse precondition
+	expectedVersion, err := ifMatchVersion(c)
+	if err != nil {
+		return err
 	}
 
 	// Convert to DTO
 	dto := application.UpdateUserDTO{
-		Email: req.Email,
-		Name:  req.Name,
+		Email:           req.Email,
+		Name:            req.Name,
+		ExpectedVersion: expectedVersion,
 	}
 
 	// Call service
-	user, err := h.userService.UpdateUser(c.Context(), id, dto)
+	user, err := h.userService.UpdateUser(c.UserContext(), id, dto)
 	if err != nil {
-		return h.handleError(c, err)
+		return handleError(c, err)
 	}
 
 	// Return response
+	setETag(c, user.Version)
 	return c.Status(http.StatusOK).JSON(toUserResponse(user))
 }
 
 /*
 DeleteUser handles DELETE /users/:id - Delete a user (soft delete).
 Path parameter: id (UUID)
+Optional header: If-Match with the ETag the deletion is based on
 Response: 204 No Content
-Errors: 400 Bad Request, 404 Not Found, 500 Internal Server Error
+Errors: 400 Bad Request, 403 Forbidden, 404 Not Found, 409 Conflict (user modified concurrently), 500 Internal Server Error
 */
 func (h *UserHandler) DeleteUser(c *fiber.Ctx) error {
 	// Parse ID from path
 	idStr := c.Params("id")
 	id, err := uuid.Parse(idStr)
 	if err != nil {
-		return c.Status(http.StatusBadRequest).JSON(ErrorResponse{
-			Error:   "invalid_id",
-			Message: "Invalid user ID format",
-		})
+		return problem.New(http.StatusBadRequest, "invalid_id", "Invalid user ID format")
+	}
+
+	// Parse precondition
+	expectedVersion, err := ifMatchVersion(c)
+	if err != nil {
+		return err
 	}
 
 	// Call service
-	if err := h.userService.DeleteUser(c.Context(), id); err != nil {
-		return h.handleError(c, err)
+	if err := h.userService.DeleteUser(c.UserContext(), id, expectedVersion); err != nil {
+		return handleError(c, err)
 	}
 
 	// Return no content
@@ -166,28 +184,24 @@ func (h *UserHandler) DeleteUser(c *fiber.Ctx) error {
 ListUsers handles GET /users - List users with pagination.
 Query parameters: limit (default 10, max 100), offset (default 0)
 Response: 200 OK with UserListResponse
-Errors: 400 Bad Request, 500 Internal Server Error
+Errors: 400 Bad Request, 403 Forbidden (admin only), 422 Unprocessable Entity (validation failed), 500 Internal Server Error
 */
 func (h *UserHandler) ListUsers(c *fiber.Ctx) error {
 	// Parse query parameters
-	limit := c.QueryInt("limit", 10)
-	offset := c.QueryInt("offset", 0)
-
-	// Validate parameters
-	if limit < 1 {
-		limit = 10
-	}
-	if limit > 100 {
-		limit = 100
+	query := ListUsersQuery{Limit: 10}
+	if err := c.QueryParser(&query); err != nil {
+		return problem.New(http.StatusBadRequest, "invalid_query", "Invalid query parameters")
 	}
-	if offset < 0 {
-		offset = 0
+
+	// Validate query parameters
+	if err := validation.Struct(&query); err != nil {
+		return handleError(c, err)
 	}
 
 	// Call service
-	users, err := h.userService.ListUsers(c.Context(), limit, offset)
+	users, err := h.userService.ListUsers(c.UserContext(), query.Limit, query.Offset)
 	if err != nil {
-		return h.handleError(c, err)
+		return handleError(c, err)
 	}
 
 	// Return response
@@ -199,37 +213,37 @@ ChangePassword handles POST /users/:id/password - Change user password.
 Path parameter: id (UUID)
 Request body: ChangePasswordRequest
 Response: 200 OK with success message
-Errors: 400 Bad Request, 401 Unauthorized (wrong old password), 404 Not Found, 500 Internal Server Error
+Errors: 400 Bad Request, 401 Unauthorized (wrong old password), 403 Forbidden (not own account), 404 Not Found, 422 Unprocessable Entity (validation failed or weak password), 423 Locked (account locked), 429 Too Many Requests, 500 Internal Server Error
 */
 func (h *UserHandler) ChangePassword(c *fiber.Ctx) error {
 	// Parse ID from path
 	idStr := c.Params("id")
 	id, err := uuid.Parse(idStr)
 	if err != nil {
-		return c.Status(http.StatusBadRequest).JSON(ErrorResponse{
-			Error:   "invalid_id",
-			Message: "Invalid user ID format",
-		})
+		return problem.New(http.StatusBadRequest, "invalid_id", "Invalid user ID format")
 	}
 
 	// Parse request body
 	var req ChangePasswordRequest
 	if err := c.BodyParser(&req); err != nil {
-		return c.Status(http.StatusBadRequest).JSON(ErrorResponse{
-			Error:   "invalid_request",
-			Message: "Invalid request body",
-		})
+		return problem.New(http.StatusBadRequest, "invalid_request", "Invalid request body")
+	}
+
+	// Validate request body
+	if err := validation.Struct(&req); err != nil {
+		return handleError(c, err)
 	}
 
 	// Convert to DTO
 	dto := application.ChangePasswordDTO{
 		OldPassword: req.OldPassword,
 		NewPassword: req.NewPassword,
+		ClientIP:    c.IP(),
 	}
 
 	// Call service
-	if err := h.userService.ChangePassword(c.Context(), id, dto); err != nil {
-		return h.handleError(c, err)
+	if err := h.userService.ChangePassword(c.UserContext(), id, dto); err != nil {
+		return handleError(c, err)
 	}
 
 	// Return success
@@ -239,50 +253,191 @@ func (h *UserHandler) ChangePassword(c *fiber.Ctx) error {
 }
 
 /*
-handleError maps domain/application errors to appropriate HTTP status codes.
-This is the error translation layer between business logic and HTTP.
+ChangeRole handles PUT /users/:id/role - Change a user's role (admin only).
+Path parameter: id (UUID)
+Request body: ChangeRoleRequest
+Response: 200 OK with UserResponse
+Errors: 400 Bad Request (invalid role), 403 Forbidden, 404 Not Found, 422 Unprocessable Entity (validation failed), 500 Internal Server Error
 */
-func (h *UserHandler) handleError(c *fiber.Ctx, err error) error {
-	// Log the error
-	h.logger.Error("Handler error", "error", err.Error(), "path", c.Path())
-
-	// Map domain errors to HTTP status codes
-	switch {
-	case errors.Is(err, domain.ErrUserNotFound):
-		return c.Status(http.StatusNotFound).JSON(ErrorResponse{
-			Error:   "not_found",
-			Message: "User not found",
-		})
-
-	case errors.Is(err, domain.ErrEmailAlreadyExists):
-		return c.Status(http.StatusConflict).JSON(ErrorResponse{
-			Error:   "conflict",
-			Message: "Email already exists",
-		})
-
-	case errors.Is(err, domain.ErrInvalidEmail):
-		return c.Status(http.StatusBadRequest).JSON(ErrorResponse{
-			Error:   "invalid_email",
-			Message: "Invalid email format",
-		})
-
-	case errors.Is(err, domain.ErrInvalidPassword):
-		return c.Status(http.StatusUnauthorized).JSON(ErrorResponse{
-			Error:   "invalid_password",
-			Message: "Invalid password",
-		})
-
-	case errors.Is(err, domain.ErrUserInactive):
-		return c.Status(http.StatusForbidden).JSON(ErrorResponse{
-			Error:   "user_inactive",
-			Message: "User account is inactive",
-		})
-
-	default:
-		// Generic internal server error
-		return c.Status(http.StatusInternalServerError).JSON(ErrorResponse{
-			Error:   "internal_error",
-			Message: "An internal error occurred",
-		})
+func (h *UserHandler) ChangeRole(c *fiber.Ctx) error {
+	// Parse ID from path
+	idStr := c.Params("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return problem.New(http.StatusBadRequest, "invalid_id", "Invalid user ID format")
+	}
+
+	// Parse request body
+	var req ChangeRoleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return problem.New(http.StatusBadRequest, "invalid_request", "Invalid request body")
+	}
+
+	// Validate request body
+	if err := validation.Struct(&req); err != nil {
+		return handleError(c, err)
 	}
+
+	// Call service
+	user, err := h.userService.ChangeUserRole(c.UserContext(), id, application.ChangeRoleDTO{
+		Role: req.Role,
+	})
+	if err != nil {
+		return handleError(c, err)
+	}
+
+	// Return response
+	setETag(c, user.Version)
+	return c.Status(http.StatusOK).JSON(toUserResponse(user))
+}
+
+/*
+UnlockUser handles POST /users/:id/unlock - Lift a login lockout (admin only).
+Path parameter: id (UUID)
+Response: 200 OK with UserResponse
+Errors: 400 Bad Request, 403 Forbidden, 404 Not Found, 500 Internal Server Error
+*/
+func (h *UserHandler) UnlockUser(c *fiber.Ctx) error {
+	// Parse ID from path
+	idStr := c.Params("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return problem.New(http.StatusBadRequest, "invalid_id", "Invalid user ID format")
+	}
+
+	// Call service
+	user, err := h.userService.UnlockUser(c.UserContext(), id)
+	if err != nil {
+		return handleError(c, err)
+	}
+
+	// Return response
+	setETag(c, user.Version)
+	return c.Status(http.StatusOK).JSON(toUserResponse(user))
+}
+
+/*
+ActivateUser handles POST /users/:id/activate - Reactivate a deactivated user (admin only).
+Path parameter: id (UUID)
+Response: 200 OK with UserResponse
+Errors: 400 Bad Request, 403 Forbidden, 404 Not Found, 500 Internal Server Error
+*/
+func (h *UserHandler) ActivateUser(c *fiber.Ctx) error {
+	// Parse ID from path
+	idStr := c.Params("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return problem.New(http.StatusBadRequest, "invalid_id", "Invalid user ID format")
+	}
+
+	// Call service
+	user, err := h.userService.ActivateUser(c.UserContext(), id)
+	if err != nil {
+		return handleError(c, err)
+	}
+
+	// Return response
+	setETag(c, user.Version)
+	return c.Status(http.StatusOK).JSON(toUserResponse(user))
+}
+
+/*
+DeactivateUser handles POST /users/:id/deactivate - Deactivate a user.
+Path parameter: id (UUID)
+Response: 200 OK with UserResponse
+Errors: 400 Bad Request, 403 Forbidden, 404 Not Found, 500 Internal Server Error
+*/
+func (h *UserHandler) DeactivateUser(c *fiber.Ctx) error {
+	// Parse ID from path
+	idStr := c.Params("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return problem.New(http.StatusBadRequest, "invalid_id", "Invalid user ID format")
+	}
+
+	// Call service
+	user, err := h.userService.DeactivateUser(c.UserContext(), id)
+	if err != nil {
+		return handleError(c, err)
+	}
+
+	// Return response
+	setETag(c, user.Version)
+	return c.Status(http.StatusOK).JSON(toUserResponse(user))
+}
+
+/*
+ListAuditLog handles GET /users/:id/audit - List the changes made to a user.
+Path parameter: id (UUID)
+Query parameters: action, actor_id, field, from and to (RFC 3339) filters,
+limit (default 10, max 100), offset (default 0)
+Response: 200 OK with AuditLogResponse
+Errors: 400 Bad Request, 403 Forbidden, 404 Not Found, 422 Unprocessable Entity (validation failed), 500 Internal Server Error
+*/
+func (h *UserHandler) ListAuditLog(c *fiber.Ctx) error {
+	// Parse ID from path
+	idStr := c.Params("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return problem.New(http.StatusBadRequest, "invalid_id", "Invalid user ID format")
+	}
+
+	// Parse query parameters
+	query := AuditLogQuery{Limit: 10}
+	if err := c.QueryParser(&query); err != nil {
+		return problem.New(http.StatusBadRequest, "invalid_query", "Invalid query parameters")
+	}
+
+	// Validate query parameters
+	if err := validation.Struct(&query); err != nil {
+		return handleError(c, err)
+	}
+
+	// Convert to DTO; the formats were checked by the validation above
+	dto := application.AuditLogQueryDTO{
+		Action: query.Action,
+		Field:  query.Field,
+		Limit:  query.Limit,
+		Offset: query.Offset,
+	}
+	if query.ActorID != "" {
+		actorID := uuid.MustParse(query.ActorID)
+		dto.ActorID = &actorID
+	}
+	dto.From = parseQueryTime(query.From)
+	dto.To = parseQueryTime(query.To)
+
+	// Call service
+	entries, err := h.userService.ListAuditLog(c.UserContext(), id, dto)
+	if err != nil {
+		return handleError(c, err)
+	}
+
+	// Return response
+	return c.Status(http.StatusOK).JSON(toAuditLogResponse(entries))
+}
+
+// parseQueryTime parses a validated RFC 3339 query parameter, or returns nil if it is empty
+func parseQueryTime(value string) *time.Time {
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil
+	}
+	return &t
+}
+
+/*
+handleError prepares the response for a failed request and returns the error
+for the problem Renderer installed as the Fiber ErrorHandler, which reports it
+using the definitions from RegisterErrors.
+It is shared by all handlers in the users domain.
+*/
+func handleError(c *fiber.Ctx, err error) error {
+	// Tell throttled clients when to retry
+	var lockout *domain.LockoutError
+	if errors.As(err, &lockout) && lockout.RetryAfter > 0 {
+		c.Set(fiber.HeaderRetryAfter, strconv.Itoa(int(math.Ceil(lockout.RetryAfter.Seconds()))))
+	}
+
+	return err
 }
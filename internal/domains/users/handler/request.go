@@ -1,16 +1,20 @@
 package handler
 
+import "time"
+
 /*
 Request models for user endpoints.
 These structs define the expected JSON structure for incoming HTTP requests.
 They are separate from DTOs to allow for different validation rules at the HTTP layer.
+The validate tags are enforced by the validation package once the request is parsed;
+password strength is left to the configured password policy in the application layer.
 */
 
 // CreateUserRequest represents the request body for creating a new user
 type CreateUserRequest struct {
 	Email    string `json:"email" validate:"required,email"`
 	Name     string `json:"name" validate:"required,min=1,max=100"`
-	Password string `json:"password" validate:"required,min=8"`
+	Password string `json:"password" validate:"required"`
 }
 
 // UpdateUserRequest represents the request body for updating a user
@@ -22,7 +26,12 @@ type UpdateUserRequest struct {
 // ChangePasswordRequest represents the request body for changing password
 type ChangePasswordRequest struct {
 	OldPassword string `json:"old_password" validate:"required"`
-	NewPassword string `json:"new_password" validate:"required,min=8"`
+	NewPassword string `json:"new_password" validate:"required"`
+}
+
+// ChangeRoleRequest represents the request body for changing a user's role
+type ChangeRoleRequest struct {
+	Role string `json:"role" validate:"required,oneof=admin support member"`
 }
 
 // ListUsersQuery represents query parameters for listing users
@@ -30,3 +39,64 @@ type ListUsersQuery struct {
 	Limit  int `query:"limit" validate:"min=1,max=100"`
 	Offset int `query:"offset" validate:"min=0"`
 }
+
+// AuditLogQuery represents query parameters for listing a user's audit log
+type AuditLogQuery struct {
+	Action  string `query:"action" validate:"omitempty,oneof=user.created user.updated user.deleted user.lockout"`
+	ActorID string `query:"actor_id" validate:"omitempty,uuid"`
+	Field   string `query:"field" validate:"omitempty,oneof=email name role is_active email_verified_at locked_until password"`
+	From    string `query:"from" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+	To      string `query:"to" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+	Limit   int    `query:"limit" validate:"min=1,max=100"`
+	Offset  int    `query:"offset" validate:"min=0"`
+}
+
+// LoginRequest represents the request body for logging in
+type LoginRequest struct {
+	Email    string `json:"email" validate:"required,email"`
+	Password string `json:"password" validate:"required"`
+}
+
+// RefreshTokenRequest represents the request body for refreshing or revoking tokens
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
+
+// ForgotPasswordRequest represents the request body for requesting a password reset email
+type ForgotPasswordRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+// ResetPasswordRequest represents the request body for resetting a password
+type ResetPasswordRequest struct {
+	Token       string `json:"token" validate:"required"`
+	NewPassword string `json:"new_password" validate:"required"`
+}
+
+// VerifyEmailRequest represents the request body for verifying an email address
+type VerifyEmailRequest struct {
+	Token string `json:"token" validate:"required"`
+}
+
+// ResendVerificationRequest represents the request body for resending a verification email
+type ResendVerificationRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+// MFALoginRequest represents the request body for the second step of a two-factor login
+type MFALoginRequest struct {
+	MFAToken string `json:"mfa_token" validate:"required"`
+	Code     string `json:"code" validate:"required"` // TOTP code or recovery code
+}
+
+// ConfirmTOTPRequest represents the request body for confirming a TOTP enrolment
+type ConfirmTOTPRequest struct {
+	Code string `json:"code" validate:"required,len=6,numeric"`
+}
+
+// CreateAPIKeyRequest represents the request body for creating an API key
+type CreateAPIKeyRequest struct {
+	Name      string     `json:"name" validate:"required,min=1,max=100"`
+	Scopes    []string   `json:"scopes" validate:"required,min=1"`
+	ExpiresAt *time.Time `json:"expires_at"` // Optional; the key never expires if omitted
+}
@@ -0,0 +1,88 @@
+package handler
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	apperrors "github.com/dzikrisyairozi/go-ddd-clean-starter/internal/platform/errors"
+	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/platform/validation"
+)
+
+// fieldErrors validates a request model and returns the messages by field, or nil if it is valid
+func fieldErrors(t *testing.T, request interface{}) map[string]string {
+	t.Helper()
+
+	err := validation.Struct(request)
+	if err == nil {
+		return nil
+	}
+
+	var errs *apperrors.ValidationErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("Struct() error = %v, want *ValidationErrors", err)
+	}
+
+	fields := make(map[string]string, len(errs.Errors))
+	for _, fe := range errs.Errors {
+		fields[fe.Field] = fe.Message
+	}
+	return fields
+}
+
+func TestRequestValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		request interface{}
+		want    map[string]string
+	}{
+		{name: "list users", request: &ListUsersQuery{Limit: 10, Offset: 0}},
+		{
+			name:    "list users out of range",
+			request: &ListUsersQuery{Limit: 0, Offset: -1},
+			want:    map[string]string{"limit": "must be at least 1", "offset": "must be at least 0"},
+		},
+		{
+			name:    "list users over the page size",
+			request: &ListUsersQuery{Limit: 101},
+			want:    map[string]string{"limit": "must be at most 100"},
+		},
+		{
+			name:    "audit log filters",
+			request: &AuditLogQuery{Action: "user.renamed", ActorID: "me", From: "today", Limit: 10},
+			want: map[string]string{
+				"action":   "must be one of: user.created, user.updated, user.deleted, user.lockout",
+				"actor_id": "must be a valid UUID",
+				"from":     "must be an RFC 3339 date-time (e.g. 2006-01-02T15:04:05Z)",
+			},
+		},
+		{name: "create user", request: &CreateUserRequest{Email: "alice@example.com", Name: "Alice", Password: "secret"}},
+		{
+			name:    "create user missing fields",
+			request: &CreateUserRequest{Email: "alice"},
+			want: map[string]string{
+				"email":    "must be a valid email address",
+				"name":     "is required",
+				"password": "is required",
+			},
+		},
+		{
+			name:    "change role",
+			request: &ChangeRoleRequest{Role: "owner"},
+			want:    map[string]string{"role": "must be one of: admin, support, member"},
+		},
+		{
+			name:    "create api key",
+			request: &CreateAPIKeyRequest{Name: "ci"},
+			want:    map[string]string{"scopes": "is required"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := fieldErrors(t, tt.request); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("validation errors = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -15,12 +15,16 @@ They map directly from application DTOs but can be customized for API needs.
 
 // UserResponse represents a single user in API responses
 type UserResponse struct {
-	ID        uuid.UUID `json:"id"`
-	Email     string    `json:"email"`
-	Name      string    `json:"name"`
-	IsActive  bool      `json:"is_active"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID              uuid.UUID  `json:"id"`
+	Email           string     `json:"email"`
+	Name            string     `json:"name"`
+	Role            string     `json:"role"`
+	IsActive        bool       `json:"is_active"`
+	EmailVerifiedAt *time.Time `json:"email_verified_at,omitempty"`
+	LockedUntil     *time.Time `json:"locked_until,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+	Version         int        `json:"version"`
 }
 
 // UserListResponse represents a paginated list of users
@@ -32,11 +36,77 @@ type UserListResponse struct {
 	HasMore bool           `json:"has_more"`
 }
 
-// ErrorResponse represents an error response
-type ErrorResponse struct {
-	Error   string            `json:"error"`
-	Message string            `json:"message,omitempty"`
-	Fields  map[string]string `json:"fields,omitempty"`
+// FieldChangeResponse represents the value of a field before and after a change
+type FieldChangeResponse struct {
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+// AuditEntryResponse represents one audit log entry in API responses
+type AuditEntryResponse struct {
+	ID           uuid.UUID                      `json:"id"`
+	ActorID      *uuid.UUID                     `json:"actor_id"`
+	Action       string                         `json:"action"`
+	TargetUserID uuid.UUID                      `json:"target_user_id"`
+	Changes      map[string]FieldChangeResponse `json:"changes"`
+	RequestID    string                         `json:"request_id,omitempty"`
+	IPAddress    string                         `json:"ip_address,omitempty"`
+	CreatedAt    time.Time                      `json:"created_at"`
+}
+
+// AuditLogResponse represents a paginated list of audit log entries, newest first
+type AuditLogResponse struct {
+	Entries []AuditEntryResponse `json:"entries"`
+	Total   int64                `json:"total"`
+	Limit   int                  `json:"limit"`
+	Offset  int                  `json:"offset"`
+	HasMore bool                 `json:"has_more"`
+}
+
+// TokenResponse represents the tokens returned after a successful login or refresh
+type TokenResponse struct {
+	AccessToken           string    `json:"access_token"`
+	TokenType             string    `json:"token_type"`
+	ExpiresIn             int64     `json:"expires_in"`
+	ExpiresAt             time.Time `json:"expires_at"`
+	RefreshToken          string    `json:"refresh_token"`
+	RefreshTokenExpiresAt time.Time `json:"refresh_token_expires_at"`
+}
+
+// MFAChallengeResponse is returned by login when a second factor is required
+type MFAChallengeResponse struct {
+	MFARequired bool      `json:"mfa_required"`
+	MFAToken    string    `json:"mfa_token"`
+	ExpiresIn   int64     `json:"expires_in"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
+// TOTPEnrollmentResponse represents a started TOTP enrolment
+type TOTPEnrollmentResponse struct {
+	Secret     string `json:"secret"`
+	OTPAuthURI string `json:"otpauth_uri"`
+}
+
+// RecoveryCodesResponse represents recovery codes, which are only shown once
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// APIKeyResponse represents an API key; the secret key is only included on creation
+type APIKeyResponse struct {
+	ID         uuid.UUID  `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Key        string     `json:"key,omitempty"`
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// APIKeyListResponse represents the caller's active API keys
+type APIKeyListResponse struct {
+	APIKeys []APIKeyResponse `json:"api_keys"`
 }
 
 // SuccessResponse represents a generic success response
@@ -49,12 +119,16 @@ type SuccessResponse struct {
 
 func toUserResponse(dto *application.UserResponseDTO) UserResponse {
 	return UserResponse{
-		ID:        dto.ID,
-		Email:     dto.Email,
-		Name:      dto.Name,
-		IsActive:  dto.IsActive,
-		CreatedAt: dto.CreatedAt,
-		UpdatedAt: dto.UpdatedAt,
+		ID:              dto.ID,
+		Email:           dto.Email,
+		Name:            dto.Name,
+		Role:            dto.Role,
+		IsActive:        dto.IsActive,
+		EmailVerifiedAt: dto.EmailVerifiedAt,
+		LockedUntil:     dto.LockedUntil,
+		CreatedAt:       dto.CreatedAt,
+		UpdatedAt:       dto.UpdatedAt,
+		Version:         dto.Version,
 	}
 }
 
@@ -72,3 +146,75 @@ func toUserListResponse(dto *application.UserListResponseDTO) UserListResponse {
 		HasMore: dto.HasMore,
 	}
 }
+
+func toAuditLogResponse(dto *application.AuditLogResponseDTO) AuditLogResponse {
+	entries := make([]AuditEntryResponse, len(dto.Entries))
+	for i, entry := range dto.Entries {
+		changes := make(map[string]FieldChangeResponse, len(entry.Changes))
+		for field, change := range entry.Changes {
+			changes[field] = FieldChangeResponse{Before: change.Before, After: change.After}
+		}
+
+		entries[i] = AuditEntryResponse{
+			ID:           entry.ID,
+			ActorID:      entry.ActorID,
+			Action:       entry.Action,
+			TargetUserID: entry.TargetUserID,
+			Changes:      changes,
+			RequestID:    entry.RequestID,
+			IPAddress:    entry.IPAddress,
+			CreatedAt:    entry.CreatedAt,
+		}
+	}
+
+	return AuditLogResponse{
+		Entries: entries,
+		Total:   dto.Total,
+		Limit:   dto.Limit,
+		Offset:  dto.Offset,
+		HasMore: dto.HasMore,
+	}
+}
+
+func toTokenResponse(dto *application.TokenResponseDTO) TokenResponse {
+	return TokenResponse{
+		AccessToken:           dto.AccessToken,
+		TokenType:             dto.TokenType,
+		ExpiresIn:             int64(time.Until(dto.ExpiresAt).Seconds()),
+		ExpiresAt:             dto.ExpiresAt,
+		RefreshToken:          dto.RefreshToken,
+		RefreshTokenExpiresAt: dto.RefreshTokenExpiresAt,
+	}
+}
+
+func toMFAChallengeResponse(dto *application.MFAChallengeDTO) MFAChallengeResponse {
+	return MFAChallengeResponse{
+		MFARequired: true,
+		MFAToken:    dto.MFAToken,
+		ExpiresIn:   int64(time.Until(dto.ExpiresAt).Seconds()),
+		ExpiresAt:   dto.ExpiresAt,
+	}
+}
+
+func toAPIKeyResponse(dto *application.APIKeyDTO) APIKeyResponse {
+	return APIKeyResponse{
+		ID:         dto.ID,
+		Name:       dto.Name,
+		Prefix:     dto.Prefix,
+		Scopes:     dto.Scopes,
+		ExpiresAt:  dto.ExpiresAt,
+		LastUsedAt: dto.LastUsedAt,
+		CreatedAt:  dto.CreatedAt,
+	}
+}
+
+func toAPIKeyListResponse(dto *application.APIKeyListResponseDTO) APIKeyListResponse {
+	keys := make([]APIKeyResponse, len(dto.APIKeys))
+	for i, key := range dto.APIKeys {
+		keys[i] = toAPIKeyResponse(&key)
+	}
+
+	return APIKeyListResponse{
+		APIKeys: keys,
+	}
+}
@@ -2,7 +2,10 @@ package handler
 
 import (
 	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/domains/users/application"
+	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/domains/users/domain"
+	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/platform/auth"
 	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/platform/logger"
+	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/platform/middleware"
 	"github.com/gofiber/fiber/v2"
 )
 
@@ -11,26 +14,132 @@ RegisterRoutes registers all user-related routes with the Fiber app.
 This follows the principle: "Fiber for routing ONLY".
 All business logic is in the application service.
 
+Creating a user (sign-up) is public; every other route requires the authenticate
+middleware to succeed first. Routes that are never self-service are additionally
+guarded by the caller's role; the rest are authorized per target user by UserService.
+
 Routes:
 
-	POST   /users           - Create a new user
-	GET    /users           - List users (paginated)
+	POST   /users           - Create a new user (public)
+	GET    /users           - List users (paginated, admin only)
 	GET    /users/:id       - Get a user by ID
 	PUT    /users/:id       - Update a user
 	DELETE /users/:id       - Delete a user (soft delete)
-	POST   /users/:id/password - Change user password
+	POST   /users/:id/password - Change own password
+	PUT    /users/:id/role  - Change a user's role (admin only)
+	POST   /users/:id/unlock - Lift a login lockout (admin only)
+	POST   /users/:id/activate - Reactivate a deactivated user (admin only)
+	POST   /users/:id/deactivate - Deactivate a user
+	GET    /users/:id/audit - List the changes made to a user (admin and support only)
 */
-func RegisterRoutes(app *fiber.App, userService *application.UserService, log *logger.Logger) {
+func RegisterRoutes(app *fiber.App, userService *application.UserService, authenticate fiber.Handler, log *logger.Logger) {
 	// Create handler
 	handler := NewUserHandler(userService, log)
 
+	// Role guards for routes that are never self-service
+	canList := requirePermission(domain.PermissionUsersList)
+	canManageRoles := requirePermission(domain.PermissionUsersManageRoles)
+	canUnlock := requirePermission(domain.PermissionUsersUnlock)
+	canReactivate := requirePermission(domain.PermissionUsersDeactivate)
+	canReadAudit := requirePermission(domain.PermissionUsersReadAudit)
+
 	// User routes
 	users := app.Group("/users")
 
-	users.Post("/", handler.CreateUser)                 // Create user
-	users.Get("/", handler.ListUsers)                   // List users
-	users.Get("/:id", handler.GetUser)                  // Get user by ID
-	users.Put("/:id", handler.UpdateUser)               // Update user
-	users.Delete("/:id", handler.DeleteUser)            // Delete user
-	users.Post("/:id/password", handler.ChangePassword) // Change password
+	users.Post("/", handler.CreateUser)                                            // Create user
+	users.Get("/", authenticate, canList, handler.ListUsers)                       // List users
+	users.Get("/:id", authenticate, handler.GetUser)                               // Get user by ID
+	users.Put("/:id", authenticate, handler.UpdateUser)                            // Update user
+	users.Delete("/:id", authenticate, handler.DeleteUser)                         // Delete user
+	users.Post("/:id/password", authenticate, handler.ChangePassword)              // Change password
+	users.Put("/:id/role", authenticate, canManageRoles, handler.ChangeRole)       // Change role
+	users.Post("/:id/unlock", authenticate, canUnlock, handler.UnlockUser)         // Unlock user
+	users.Post("/:id/activate", authenticate, canReactivate, handler.ActivateUser) // Activate user
+	users.Post("/:id/deactivate", authenticate, handler.DeactivateUser)            // Deactivate user
+	users.Get("/:id/audit", authenticate, canReadAudit, handler.ListAuditLog)      // List audit log
+}
+
+/*
+requirePermission returns a route guard that only admits callers whose role grants
+the given permission on any user account. API keys must also have it in scope.
+*/
+func requirePermission(permission domain.Permission) fiber.Handler {
+	return middleware.Authorize(func(principal *auth.Principal) bool {
+		if principal.Scopes != nil && !containsScope(principal.Scopes, permission) {
+			return false
+		}
+		return domain.Role(principal.Role).HasPermission(permission)
+	})
+}
+
+// containsScope reports whether the scopes include the permission
+func containsScope(scopes []string, permission domain.Permission) bool {
+	for _, scope := range scopes {
+		if domain.Permission(scope) == permission {
+			return true
+		}
+	}
+	return false
+}
+
+/*
+RegisterAuthRoutes registers authentication routes with the Fiber app.
+The login and recovery routes are public; managing the caller's own second
+factor requires the authenticate middleware.
+
+Routes:
+
+	POST   /auth/login               - Log in with email and password
+	POST   /auth/login/mfa           - Complete a two-factor login with a code
+	POST   /auth/refresh             - Rotate a refresh token into a new token pair
+	POST   /auth/logout              - Revoke a refresh token family
+	POST   /auth/password/forgot     - Email a password reset link
+	POST   /auth/password/reset      - Reset a password with an emailed token
+	POST   /auth/verify-email        - Verify an email address with an emailed token
+	POST   /auth/verify-email/resend - Email a new verification link
+	POST   /auth/mfa/totp/enroll     - Start TOTP enrolment (authenticated)
+	POST   /auth/mfa/totp/confirm    - Confirm TOTP enrolment and get recovery codes (authenticated)
+*/
+func RegisterAuthRoutes(app *fiber.App, authService *application.AuthService, authenticate fiber.Handler, log *logger.Logger) {
+	// Create handler
+	handler := NewAuthHandler(authService, log)
+
+	// Auth routes
+	auth := app.Group("/auth")
+
+	auth.Post("/login", handler.Login)                            // Log in
+	auth.Post("/login/mfa", handler.LoginMFA)                     // Complete two-factor login
+	auth.Post("/refresh", handler.Refresh)                        // Refresh tokens
+	auth.Post("/logout", handler.Logout)                          // Log out
+	auth.Post("/password/forgot", handler.ForgotPassword)         // Request password reset
+	auth.Post("/password/reset", handler.ResetPassword)           // Reset password
+	auth.Post("/verify-email", handler.VerifyEmail)               // Verify email
+	auth.Post("/verify-email/resend", handler.ResendVerification) // Resend verification email
+
+	// Two-factor management for the authenticated user
+	auth.Post("/mfa/totp/enroll", authenticate, handler.EnrollTOTP)   // Start TOTP enrolment
+	auth.Post("/mfa/totp/confirm", authenticate, handler.ConfirmTOTP) // Confirm TOTP enrolment
+}
+
+/*
+RegisterAPIKeyRoutes registers personal API key routes with the Fiber app.
+Every route requires the authenticate middleware, and keys can only be managed
+from an interactive login, not with another API key.
+
+Routes:
+
+	POST   /api-keys     - Create an API key (the key is shown once)
+	GET    /api-keys     - List the caller's active API keys
+	DELETE /api-keys/:id - Revoke an API key
+*/
+func RegisterAPIKeyRoutes(app *fiber.App, apiKeyService *application.APIKeyService, authenticate fiber.Handler, log *logger.Logger) {
+	// Create handler
+	handler := NewAPIKeyHandler(apiKeyService, log)
+
+	// API key routes
+	apiKeys := app.Group("/api-keys", authenticate)
+
+	apiKeys.Post("/", handler.CreateAPIKey)      // Create API key
+	apiKeys.Get("/", handler.ListAPIKeys)        // List API keys
+	apiKeys.Delete("/:id", handler.RevokeAPIKey) // Revoke API key
 }
@@ -0,0 +1,283 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/domains/users/domain"
+	"github.com/google/uuid"
+)
+
+/*
+UserRepository implements the domain.UserRepository interface in memory.
+It is meant for tests and demos that should run without Postgres, and keeps the
+semantics of the Postgres implementation:
+  - Emails are unique, compared case-insensitively
+  - FindByID, FindByEmail, List and Count only see active users
+  - List orders users by created_at DESC (newest first)
+  - Update and Delete return ErrUserNotFound for unknown IDs, and Update only
+    applies if the stored version still equals user.Version
+  - Timestamps are kept at microsecond precision, like TIMESTAMP columns
+
+It does not write the audit log or the outbox.
+It is safe for concurrent use. Users are copied on the way in and out, so
+changing a user only affects the repository once it is saved or updated.
+*/
+type UserRepository struct {
+	mu    sync.RWMutex
+	users map[uuid.UUID]*domain.User
+}
+
+/*
+NewUserRepository creates a new, empty UserRepository instance.
+*/
+func NewUserRepository() *UserRepository {
+	return &UserRepository{
+		users: make(map[uuid.UUID]*domain.User),
+	}
+}
+
+/*
+Save stores a new user.
+Like the database default, the stored user starts at version 1.
+Returns ErrEmailAlreadyExists if another user has the same email.
+*/
+func (r *UserRepository) Save(ctx context.Context, user *domain.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.users[user.ID]; exists {
+		return fmt.Errorf("failed to create user: user %s already exists", user.ID)
+	}
+	if r.emailTakenLocked(user.Email, user.ID) {
+		return domain.ErrEmailAlreadyExists
+	}
+
+	stored := copyUser(user)
+	stored.Version = 1
+	r.users[user.ID] = stored
+
+	return nil
+}
+
+/*
+FindByID retrieves an active user by their unique identifier.
+Returns ErrUserNotFound if no active user exists with the given ID.
+*/
+func (r *UserRepository) FindByID(ctx context.Context, id uuid.UUID) (*domain.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	user, ok := r.users[id]
+	if !ok || !user.IsActive {
+		return nil, domain.ErrUserNotFound
+	}
+	return copyUser(user), nil
+}
+
+/*
+FindByIDIncludingInactive retrieves a user by their unique identifier, including deactivated users.
+Returns ErrUserNotFound if no user exists with the given ID.
+*/
+func (r *UserRepository) FindByIDIncludingInactive(ctx context.Context, id uuid.UUID) (*domain.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	user, ok := r.users[id]
+	if !ok {
+		return nil, domain.ErrUserNotFound
+	}
+	return copyUser(user), nil
+}
+
+/*
+FindByEmail retrieves an active user by their email address, case-insensitively.
+Returns ErrUserNotFound if no active user exists with the given email.
+*/
+func (r *UserRepository) FindByEmail(ctx context.Context, email domain.Email) (*domain.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	user := r.findByEmailLocked(email)
+	if user == nil || !user.IsActive {
+		return nil, domain.ErrUserNotFound
+	}
+	return copyUser(user), nil
+}
+
+/*
+FindByEmailIncludingInactive retrieves a user by email address, including deactivated users.
+Returns ErrUserNotFound if no user exists with the given email.
+*/
+func (r *UserRepository) FindByEmailIncludingInactive(ctx context.Context, email domain.Email) (*domain.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	user := r.findByEmailLocked(email)
+	if user == nil {
+		return nil, domain.ErrUserNotFound
+	}
+	return copyUser(user), nil
+}
+
+/*
+Update replaces a stored user, active or not, if its version still equals
+user.Version. On success user.Version is set to the new version.
+CreatedAt keeps its stored value.
+Returns ErrUserNotFound if the user doesn't exist.
+Returns ErrConcurrentModification if the user was updated since it was loaded.
+Returns ErrEmailAlreadyExists if the new email conflicts with another user.
+*/
+func (r *UserRepository) Update(ctx context.Context, user *domain.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	current, ok := r.users[user.ID]
+	if !ok {
+		return domain.ErrUserNotFound
+	}
+	if current.Version != user.Version {
+		return domain.ErrConcurrentModification
+	}
+	if r.emailTakenLocked(user.Email, user.ID) {
+		return domain.ErrEmailAlreadyExists
+	}
+
+	stored := copyUser(user)
+	stored.CreatedAt = current.CreatedAt
+	stored.Version = current.Version + 1
+	r.users[user.ID] = stored
+
+	user.Version = stored.Version
+	return nil
+}
+
+/*
+Delete soft deletes a user by setting is_active = false and incrementing its version.
+Returns ErrUserNotFound if the user doesn't exist.
+*/
+func (r *UserRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	current, ok := r.users[id]
+	if !ok {
+		return domain.ErrUserNotFound
+	}
+
+	stored := copyUser(current)
+	stored.IsActive = false
+	stored.UpdatedAt = storedTime(time.Now())
+	stored.Version++
+	r.users[id] = stored
+
+	return nil
+}
+
+/*
+List retrieves a paginated list of active users.
+Results are ordered by created_at DESC (newest first).
+*/
+func (r *UserRepository) List(ctx context.Context, limit, offset int) ([]*domain.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	active := make([]*domain.User, 0, len(r.users))
+	for _, user := range r.users {
+		if user.IsActive {
+			active = append(active, user)
+		}
+	}
+
+	// Newest first; the ID only breaks ties so the order is stable between calls
+	sort.Slice(active, func(i, j int) bool {
+		if !active[i].CreatedAt.Equal(active[j].CreatedAt) {
+			return active[i].CreatedAt.After(active[j].CreatedAt)
+		}
+		return active[i].ID.String() < active[j].ID.String()
+	})
+
+	if offset < 0 {
+		offset = 0
+	}
+	if offset > len(active) {
+		offset = len(active)
+	}
+	end := len(active)
+	if limit >= 0 && offset+limit < end {
+		end = offset + limit
+	}
+
+	users := make([]*domain.User, 0, end-offset)
+	for _, user := range active[offset:end] {
+		users = append(users, copyUser(user))
+	}
+
+	return users, nil
+}
+
+/*
+Count returns the total number of active users.
+*/
+func (r *UserRepository) Count(ctx context.Context) (int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var count int64
+	for _, user := range r.users {
+		if user.IsActive {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// findByEmailLocked returns the stored user with the email, or nil; r.mu must be held
+func (r *UserRepository) findByEmailLocked(email domain.Email) *domain.User {
+	for _, user := range r.users {
+		if strings.EqualFold(user.Email.Value(), email.Value()) {
+			return user
+		}
+	}
+	return nil
+}
+
+// emailTakenLocked reports whether a user other than id has the email; r.mu must be held
+func (r *UserRepository) emailTakenLocked(email domain.Email, id uuid.UUID) bool {
+	user := r.findByEmailLocked(email)
+	return user != nil && user.ID != id
+}
+
+/*
+copyUser returns a copy of the user that shares no memory with it.
+The copy has no recorded events: they belong to the caller's user.
+*/
+func copyUser(user *domain.User) *domain.User {
+	c := *user
+	c.PullEvents()
+
+	c.EmailVerifiedAt = storedTimePtr(user.EmailVerifiedAt)
+	c.LockedUntil = storedTimePtr(user.LockedUntil)
+	c.CreatedAt = storedTime(user.CreatedAt)
+	c.UpdatedAt = storedTime(user.UpdatedAt)
+
+	return &c
+}
+
+// storedTime truncates a time to the microsecond precision of a TIMESTAMP column
+func storedTime(t time.Time) time.Time {
+	return t.Truncate(time.Microsecond)
+}
+
+// storedTimePtr is storedTime for optional times
+func storedTimePtr(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	stored := storedTime(*t)
+	return &stored
+}
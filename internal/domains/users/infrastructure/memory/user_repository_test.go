@@ -0,0 +1,93 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/domains/users/domain"
+	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/domains/users/domain/domaintest"
+)
+
+func TestUserRepository(t *testing.T) {
+	domaintest.TestUserRepository(t, func(t *testing.T) (context.Context, domain.UserRepository) {
+		return context.Background(), NewUserRepository()
+	})
+}
+
+func TestUserRepositoryConcurrentSave(t *testing.T) {
+	ctx := context.Background()
+	repo := NewUserRepository()
+
+	// Every goroutine races to register the same email; exactly one may win
+	const workers = 20
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		saved     int
+		conflicts int
+	)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			email, _ := domain.NewEmail("race@example.com")
+			user, err := domain.NewUser(email, fmt.Sprintf("User %d", i), "hash")
+			if err != nil {
+				t.Errorf("NewUser() error = %v", err)
+				return
+			}
+
+			err = repo.Save(ctx, user)
+
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err == nil:
+				saved++
+			case errors.Is(err, domain.ErrEmailAlreadyExists):
+				conflicts++
+			default:
+				t.Errorf("Save() error = %v", err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if saved != 1 || conflicts != workers-1 {
+		t.Errorf("saved %d users with %d conflicts, want 1 and %d", saved, conflicts, workers-1)
+	}
+}
+
+func TestUserRepositoryReturnsCopies(t *testing.T) {
+	ctx := context.Background()
+	repo := NewUserRepository()
+
+	email, _ := domain.NewEmail("alice@example.com")
+	user, err := domain.NewUser(email, "Alice", "hash")
+	if err != nil {
+		t.Fatalf("NewUser() error = %v", err)
+	}
+	if err := repo.Save(ctx, user); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	// Changes that were never passed to Update must not be visible
+	user.Name = "Changed after Save"
+	loaded, err := repo.FindByID(ctx, user.ID)
+	if err != nil {
+		t.Fatalf("FindByID() error = %v", err)
+	}
+	loaded.Name = "Changed after FindByID"
+
+	stored, err := repo.FindByID(ctx, user.ID)
+	if err != nil {
+		t.Fatalf("FindByID() error = %v", err)
+	}
+	if stored.Name != "Alice" {
+		t.Errorf("Name = %q, want %q", stored.Name, "Alice")
+	}
+}
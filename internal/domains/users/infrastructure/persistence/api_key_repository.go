@@ -0,0 +1,151 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/domains/users/domain"
+	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/domains/users/infrastructure/persistence/sqlc"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+/*
+APIKeyRepository implements the domain.APIKeyRepository interface using SQLC.
+*/
+type APIKeyRepository struct {
+	pool    *pgxpool.Pool
+	queries *sqlc.Queries
+}
+
+/*
+NewAPIKeyRepository creates a new APIKeyRepository instance.
+Requires a pgxpool.Pool for database connectivity.
+*/
+func NewAPIKeyRepository(pool *pgxpool.Pool) *APIKeyRepository {
+	return &APIKeyRepository{
+		pool:    pool,
+		queries: sqlc.New(pool),
+	}
+}
+
+/*
+Save persists a newly created API key.
+*/
+func (r *APIKeyRepository) Save(ctx context.Context, key *domain.APIKey) error {
+	scopes := make([]string, len(key.Scopes))
+	for i, scope := range key.Scopes {
+		scopes[i] = string(scope)
+	}
+
+	params := sqlc.CreateAPIKeyParams{
+		ID:        uuidToPgtype(key.ID),
+		UserID:    uuidToPgtype(key.UserID),
+		Name:      key.Name,
+		Prefix:    key.Prefix,
+		KeyHash:   key.KeyHash,
+		Scopes:    scopes,
+		ExpiresAt: timePtrToPgtype(key.ExpiresAt),
+		CreatedAt: timeToPgtype(key.CreatedAt),
+	}
+
+	if _, err := queriesFor(ctx, r.queries).CreateAPIKey(ctx, params); err != nil {
+		return fmt.Errorf("failed to create api key: %w", err)
+	}
+
+	return nil
+}
+
+/*
+FindByHash retrieves an API key by the hash of its value.
+Returns ErrAPIKeyNotFound if no key matches.
+*/
+func (r *APIKeyRepository) FindByHash(ctx context.Context, keyHash string) (*domain.APIKey, error) {
+	sqlcKey, err := queriesFor(ctx, r.queries).GetAPIKeyByHash(ctx, keyHash)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrAPIKeyNotFound
+		}
+		return nil, fmt.Errorf("failed to get api key: %w", err)
+	}
+
+	return toDomainAPIKey(sqlcKey), nil
+}
+
+/*
+ListByUserID retrieves the user's keys that have not been revoked, newest first.
+*/
+func (r *APIKeyRepository) ListByUserID(ctx context.Context, userID uuid.UUID) ([]*domain.APIKey, error) {
+	sqlcKeys, err := queriesFor(ctx, r.queries).ListAPIKeysByUser(ctx, uuidToPgtype(userID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list api keys: %w", err)
+	}
+
+	keys := make([]*domain.APIKey, len(sqlcKeys))
+	for i, sqlcKey := range sqlcKeys {
+		keys[i] = toDomainAPIKey(sqlcKey)
+	}
+
+	return keys, nil
+}
+
+/*
+Revoke revokes one of the user's keys.
+Returns ErrAPIKeyNotFound if the user has no active key with that ID.
+*/
+func (r *APIKeyRepository) Revoke(ctx context.Context, id, userID uuid.UUID, at time.Time) error {
+	rows, err := queriesFor(ctx, r.queries).RevokeAPIKey(ctx, sqlc.RevokeAPIKeyParams{
+		ID:        uuidToPgtype(id),
+		UserID:    uuidToPgtype(userID),
+		RevokedAt: timeToPgtype(at),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to revoke api key: %w", err)
+	}
+	if rows == 0 {
+		return domain.ErrAPIKeyNotFound
+	}
+
+	return nil
+}
+
+/*
+TouchLastUsed records when the key last authenticated a request.
+*/
+func (r *APIKeyRepository) TouchLastUsed(ctx context.Context, id uuid.UUID, at time.Time) error {
+	err := queriesFor(ctx, r.queries).UpdateAPIKeyLastUsed(ctx, sqlc.UpdateAPIKeyLastUsedParams{
+		ID:         uuidToPgtype(id),
+		LastUsedAt: timeToPgtype(at),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update api key last used time: %w", err)
+	}
+
+	return nil
+}
+
+/*
+toDomainAPIKey maps a SQLC ApiKey model to a domain APIKey entity.
+*/
+func toDomainAPIKey(sqlcKey sqlc.ApiKey) *domain.APIKey {
+	scopes := make([]domain.Permission, len(sqlcKey.Scopes))
+	for i, scope := range sqlcKey.Scopes {
+		scopes[i] = domain.Permission(scope)
+	}
+
+	return &domain.APIKey{
+		ID:         pgtypeToUUID(sqlcKey.ID),
+		UserID:     pgtypeToUUID(sqlcKey.UserID),
+		Name:       sqlcKey.Name,
+		Prefix:     sqlcKey.Prefix,
+		KeyHash:    sqlcKey.KeyHash,
+		Scopes:     scopes,
+		ExpiresAt:  pgtypeToTimePtr(sqlcKey.ExpiresAt),
+		LastUsedAt: pgtypeToTimePtr(sqlcKey.LastUsedAt),
+		RevokedAt:  pgtypeToTimePtr(sqlcKey.RevokedAt),
+		CreatedAt:  pgtypeToTime(sqlcKey.CreatedAt),
+	}
+}
@@ -0,0 +1,122 @@
+package persistence
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/domains/users/domain"
+	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/domains/users/infrastructure/persistence/sqlc"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+/*
+AuditLogRepository implements the domain.AuditLogRepository interface using SQLC.
+Entries are written by UserRepository; this repository only reads them.
+*/
+type AuditLogRepository struct {
+	pool    *pgxpool.Pool
+	queries *sqlc.Queries
+}
+
+/*
+NewAuditLogRepository creates a new AuditLogRepository instance.
+Requires a pgxpool.Pool for database connectivity.
+*/
+func NewAuditLogRepository(pool *pgxpool.Pool) *AuditLogRepository {
+	return &AuditLogRepository{
+		pool:    pool,
+		queries: sqlc.New(pool),
+	}
+}
+
+/*
+ListByTargetUser retrieves the entries about the given user that match the filter.
+Results are ordered by created_at DESC (newest first).
+*/
+func (r *AuditLogRepository) ListByTargetUser(ctx context.Context, userID uuid.UUID, filter domain.AuditFilter) ([]*domain.AuditEntry, error) {
+	params := sqlc.ListAuditLogEntriesParams{
+		TargetUserID: uuidToPgtype(userID),
+		Action:       textToPgtype(filter.Action),
+		ActorID:      uuidPtrToPgtype(filter.ActorID),
+		Field:        textToPgtype(filter.Field),
+		CreatedFrom:  filterTimeToPgtype(filter.From),
+		CreatedTo:    filterTimeToPgtype(filter.To),
+		Limit:        int32(filter.Limit),
+		Offset:       int32(filter.Offset),
+	}
+
+	sqlcEntries, err := queriesFor(ctx, r.queries).ListAuditLogEntries(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list audit log entries: %w", err)
+	}
+
+	entries := make([]*domain.AuditEntry, len(sqlcEntries))
+	for i, sqlcEntry := range sqlcEntries {
+		entry, err := toDomainAuditEntry(sqlcEntry)
+		if err != nil {
+			return nil, fmt.Errorf("failed to map audit log entry at index %d: %w", i, err)
+		}
+		entries[i] = entry
+	}
+
+	return entries, nil
+}
+
+/*
+CountByTargetUser returns the number of entries about the given user that match the filter.
+Useful for pagination calculations.
+*/
+func (r *AuditLogRepository) CountByTargetUser(ctx context.Context, userID uuid.UUID, filter domain.AuditFilter) (int64, error) {
+	params := sqlc.CountAuditLogEntriesParams{
+		TargetUserID: uuidToPgtype(userID),
+		Action:       textToPgtype(filter.Action),
+		ActorID:      uuidPtrToPgtype(filter.ActorID),
+		Field:        textToPgtype(filter.Field),
+		CreatedFrom:  filterTimeToPgtype(filter.From),
+		CreatedTo:    filterTimeToPgtype(filter.To),
+	}
+
+	count, err := queriesFor(ctx, r.queries).CountAuditLogEntries(ctx, params)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count audit log entries: %w", err)
+	}
+
+	return count, nil
+}
+
+/*
+filterTimeToPgtype converts a filter bound to a nullable pgtype.Timestamp.
+Timestamps are stored as local wall-clock time without a zone, so the bound is
+converted to local time first; a nil bound is sent as NULL and does not filter.
+*/
+func filterTimeToPgtype(t *time.Time) pgtype.Timestamp {
+	if t == nil {
+		return pgtype.Timestamp{}
+	}
+	return timeToPgtype(t.Local())
+}
+
+/*
+toDomainAuditEntry maps a SQLC AuditLog model to a domain AuditEntry.
+*/
+func toDomainAuditEntry(sqlcEntry sqlc.AuditLog) (*domain.AuditEntry, error) {
+	changes := make(map[string]domain.FieldChange)
+	if err := json.Unmarshal(sqlcEntry.Changes, &changes); err != nil {
+		return nil, fmt.Errorf("invalid changes in database: %w", err)
+	}
+
+	return &domain.AuditEntry{
+		ID:           pgtypeToUUID(sqlcEntry.ID),
+		ActorID:      pgtypeToUUIDPtr(sqlcEntry.ActorID),
+		Action:       sqlcEntry.Action,
+		TargetUserID: pgtypeToUUID(sqlcEntry.TargetUserID),
+		Changes:      changes,
+		RequestID:    sqlcEntry.RequestID.String,
+		IPAddress:    sqlcEntry.IpAddress.String,
+		CreatedAt:    pgtypeToTime(sqlcEntry.CreatedAt),
+	}, nil
+}
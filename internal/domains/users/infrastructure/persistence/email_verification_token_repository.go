@@ -0,0 +1,109 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/domains/users/domain"
+	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/domains/users/infrastructure/persistence/sqlc"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+/*
+EmailVerificationTokenRepository implements the domain.EmailVerificationTokenRepository interface using SQLC.
+*/
+type EmailVerificationTokenRepository struct {
+	pool    *pgxpool.Pool
+	queries *sqlc.Queries
+}
+
+/*
+NewEmailVerificationTokenRepository creates a new EmailVerificationTokenRepository instance.
+Requires a pgxpool.Pool for database connectivity.
+*/
+func NewEmailVerificationTokenRepository(pool *pgxpool.Pool) *EmailVerificationTokenRepository {
+	return &EmailVerificationTokenRepository{
+		pool:    pool,
+		queries: sqlc.New(pool),
+	}
+}
+
+/*
+Save persists a newly issued email verification token.
+*/
+func (r *EmailVerificationTokenRepository) Save(ctx context.Context, token *domain.EmailVerificationToken) error {
+	params := sqlc.CreateEmailVerificationTokenParams{
+		ID:        uuidToPgtype(token.ID),
+		UserID:    uuidToPgtype(token.UserID),
+		TokenHash: token.TokenHash,
+		ExpiresAt: timeToPgtype(token.ExpiresAt),
+		CreatedAt: timeToPgtype(token.CreatedAt),
+	}
+
+	if _, err := queriesFor(ctx, r.queries).CreateEmailVerificationToken(ctx, params); err != nil {
+		return fmt.Errorf("failed to create email verification token: %w", err)
+	}
+
+	return nil
+}
+
+/*
+FindByHash retrieves an email verification token by the hash of its value.
+Returns ErrEmailVerificationTokenNotFound if no token matches.
+*/
+func (r *EmailVerificationTokenRepository) FindByHash(ctx context.Context, tokenHash string) (*domain.EmailVerificationToken, error) {
+	sqlcToken, err := queriesFor(ctx, r.queries).GetEmailVerificationTokenByHash(ctx, tokenHash)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrEmailVerificationTokenNotFound
+		}
+		return nil, fmt.Errorf("failed to get email verification token: %w", err)
+	}
+
+	return &domain.EmailVerificationToken{
+		ID:        pgtypeToUUID(sqlcToken.ID),
+		UserID:    pgtypeToUUID(sqlcToken.UserID),
+		TokenHash: sqlcToken.TokenHash,
+		ExpiresAt: pgtypeToTime(sqlcToken.ExpiresAt),
+		CreatedAt: pgtypeToTime(sqlcToken.CreatedAt),
+		UsedAt:    pgtypeToTimePtr(sqlcToken.UsedAt),
+	}, nil
+}
+
+/*
+MarkUsed records that the token was used.
+Returns ErrInvalidVerificationToken if the token had already been used.
+*/
+func (r *EmailVerificationTokenRepository) MarkUsed(ctx context.Context, id uuid.UUID, at time.Time) error {
+	rows, err := queriesFor(ctx, r.queries).MarkEmailVerificationTokenUsed(ctx, sqlc.MarkEmailVerificationTokenUsedParams{
+		ID:     uuidToPgtype(id),
+		UsedAt: timeToPgtype(at),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to mark email verification token used: %w", err)
+	}
+	if rows == 0 {
+		return domain.ErrInvalidVerificationToken
+	}
+
+	return nil
+}
+
+/*
+InvalidateAllForUser marks every unused token of the given user as used.
+*/
+func (r *EmailVerificationTokenRepository) InvalidateAllForUser(ctx context.Context, userID uuid.UUID, at time.Time) error {
+	err := queriesFor(ctx, r.queries).InvalidateEmailVerificationTokensForUser(ctx, sqlc.InvalidateEmailVerificationTokensForUserParams{
+		UserID: uuidToPgtype(userID),
+		UsedAt: timeToPgtype(at),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to invalidate email verification tokens: %w", err)
+	}
+
+	return nil
+}
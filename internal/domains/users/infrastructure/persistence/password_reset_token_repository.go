@@ -0,0 +1,109 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/domains/users/domain"
+	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/domains/users/infrastructure/persistence/sqlc"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+/*
+PasswordResetTokenRepository implements the domain.PasswordResetTokenRepository interface using SQLC.
+*/
+type PasswordResetTokenRepository struct {
+	pool    *pgxpool.Pool
+	queries *sqlc.Queries
+}
+
+/*
+NewPasswordResetTokenRepository creates a new PasswordResetTokenRepository instance.
+Requires a pgxpool.Pool for database connectivity.
+*/
+func NewPasswordResetTokenRepository(pool *pgxpool.Pool) *PasswordResetTokenRepository {
+	return &PasswordResetTokenRepository{
+		pool:    pool,
+		queries: sqlc.New(pool),
+	}
+}
+
+/*
+Save persists a newly issued password reset token.
+*/
+func (r *PasswordResetTokenRepository) Save(ctx context.Context, token *domain.PasswordResetToken) error {
+	params := sqlc.CreatePasswordResetTokenParams{
+		ID:        uuidToPgtype(token.ID),
+		UserID:    uuidToPgtype(token.UserID),
+		TokenHash: token.TokenHash,
+		ExpiresAt: timeToPgtype(token.ExpiresAt),
+		CreatedAt: timeToPgtype(token.CreatedAt),
+	}
+
+	if _, err := queriesFor(ctx, r.queries).CreatePasswordResetToken(ctx, params); err != nil {
+		return fmt.Errorf("failed to create password reset token: %w", err)
+	}
+
+	return nil
+}
+
+/*
+FindByHash retrieves a password reset token by the hash of its value.
+Returns ErrPasswordResetTokenNotFound if no token matches.
+*/
+func (r *PasswordResetTokenRepository) FindByHash(ctx context.Context, tokenHash string) (*domain.PasswordResetToken, error) {
+	sqlcToken, err := queriesFor(ctx, r.queries).GetPasswordResetTokenByHash(ctx, tokenHash)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrPasswordResetTokenNotFound
+		}
+		return nil, fmt.Errorf("failed to get password reset token: %w", err)
+	}
+
+	return &domain.PasswordResetToken{
+		ID:        pgtypeToUUID(sqlcToken.ID),
+		UserID:    pgtypeToUUID(sqlcToken.UserID),
+		TokenHash: sqlcToken.TokenHash,
+		ExpiresAt: pgtypeToTime(sqlcToken.ExpiresAt),
+		CreatedAt: pgtypeToTime(sqlcToken.CreatedAt),
+		UsedAt:    pgtypeToTimePtr(sqlcToken.UsedAt),
+	}, nil
+}
+
+/*
+MarkUsed records that the token was used.
+Returns ErrInvalidResetToken if the token had already been used.
+*/
+func (r *PasswordResetTokenRepository) MarkUsed(ctx context.Context, id uuid.UUID, at time.Time) error {
+	rows, err := queriesFor(ctx, r.queries).MarkPasswordResetTokenUsed(ctx, sqlc.MarkPasswordResetTokenUsedParams{
+		ID:     uuidToPgtype(id),
+		UsedAt: timeToPgtype(at),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to mark password reset token used: %w", err)
+	}
+	if rows == 0 {
+		return domain.ErrInvalidResetToken
+	}
+
+	return nil
+}
+
+/*
+InvalidateAllForUser marks every unused token of the given user as used.
+*/
+func (r *PasswordResetTokenRepository) InvalidateAllForUser(ctx context.Context, userID uuid.UUID, at time.Time) error {
+	err := queriesFor(ctx, r.queries).InvalidatePasswordResetTokensForUser(ctx, sqlc.InvalidatePasswordResetTokensForUserParams{
+		UserID: uuidToPgtype(userID),
+		UsedAt: timeToPgtype(at),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to invalidate password reset tokens: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,33 @@
+-- name: CreateAuditLogEntry :exec
+INSERT INTO audit_log (
+    id,
+    actor_id,
+    action,
+    target_user_id,
+    changes,
+    request_id,
+    ip_address,
+    created_at
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8
+);
+
+-- name: ListAuditLogEntries :many
+SELECT * FROM audit_log
+WHERE target_user_id = sqlc.arg('target_user_id')
+    AND (sqlc.narg('action')::text IS NULL OR action = sqlc.narg('action'))
+    AND (sqlc.narg('actor_id')::uuid IS NULL OR actor_id = sqlc.narg('actor_id'))
+    AND (sqlc.narg('field')::text IS NULL OR changes ? sqlc.narg('field'))
+    AND (sqlc.narg('created_from')::timestamp IS NULL OR created_at >= sqlc.narg('created_from'))
+    AND (sqlc.narg('created_to')::timestamp IS NULL OR created_at < sqlc.narg('created_to'))
+ORDER BY created_at DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: CountAuditLogEntries :one
+SELECT COUNT(*) FROM audit_log
+WHERE target_user_id = sqlc.arg('target_user_id')
+    AND (sqlc.narg('action')::text IS NULL OR action = sqlc.narg('action'))
+    AND (sqlc.narg('actor_id')::uuid IS NULL OR actor_id = sqlc.narg('actor_id'))
+    AND (sqlc.narg('field')::text IS NULL OR changes ? sqlc.narg('field'))
+    AND (sqlc.narg('created_from')::timestamp IS NULL OR created_at >= sqlc.narg('created_from'))
+    AND (sqlc.narg('created_to')::timestamp IS NULL OR created_at < sqlc.narg('created_to'));
@@ -0,0 +1,25 @@
+-- name: CreateEmailVerificationToken :one
+INSERT INTO email_verification_tokens (
+    id,
+    user_id,
+    token_hash,
+    expires_at,
+    created_at
+) VALUES (
+    $1, $2, $3, $4, $5
+) RETURNING *;
+
+-- name: GetEmailVerificationTokenByHash :one
+SELECT * FROM email_verification_tokens
+WHERE token_hash = $1
+LIMIT 1;
+
+-- name: MarkEmailVerificationTokenUsed :execrows
+UPDATE email_verification_tokens
+SET used_at = $2
+WHERE id = $1 AND used_at IS NULL;
+
+-- name: InvalidateEmailVerificationTokensForUser :exec
+UPDATE email_verification_tokens
+SET used_at = $2
+WHERE user_id = $1 AND used_at IS NULL;
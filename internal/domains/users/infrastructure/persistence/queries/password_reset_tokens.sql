@@ -0,0 +1,25 @@
+-- name: CreatePasswordResetToken :one
+INSERT INTO password_reset_tokens (
+    id,
+    user_id,
+    token_hash,
+    expires_at,
+    created_at
+) VALUES (
+    $1, $2, $3, $4, $5
+) RETURNING *;
+
+-- name: GetPasswordResetTokenByHash :one
+SELECT * FROM password_reset_tokens
+WHERE token_hash = $1
+LIMIT 1;
+
+-- name: MarkPasswordResetTokenUsed :execrows
+UPDATE password_reset_tokens
+SET used_at = $2
+WHERE id = $1 AND used_at IS NULL;
+
+-- name: InvalidatePasswordResetTokensForUser :exec
+UPDATE password_reset_tokens
+SET used_at = $2
+WHERE user_id = $1 AND used_at IS NULL;
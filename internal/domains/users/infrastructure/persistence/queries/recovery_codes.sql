@@ -0,0 +1,18 @@
+-- name: CreateRecoveryCode :exec
+INSERT INTO mfa_recovery_codes (
+    id,
+    user_id,
+    code_hash,
+    created_at
+) VALUES (
+    $1, $2, $3, $4
+);
+
+-- name: DeleteRecoveryCodesForUser :exec
+DELETE FROM mfa_recovery_codes
+WHERE user_id = $1;
+
+-- name: UseRecoveryCode :execrows
+UPDATE mfa_recovery_codes
+SET used_at = $3
+WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL;
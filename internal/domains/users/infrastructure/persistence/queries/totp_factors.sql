@@ -0,0 +1,30 @@
+-- name: UpsertTOTPFactor :one
+INSERT INTO user_totp_factors (
+    user_id,
+    secret_ciphertext,
+    confirmed_at,
+    last_used_step,
+    created_at,
+    updated_at
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+)
+ON CONFLICT (user_id) DO UPDATE
+SET
+    secret_ciphertext = EXCLUDED.secret_ciphertext,
+    confirmed_at = EXCLUDED.confirmed_at,
+    last_used_step = EXCLUDED.last_used_step,
+    updated_at = EXCLUDED.updated_at
+RETURNING *;
+
+-- name: GetTOTPFactorByUserID :one
+SELECT * FROM user_totp_factors
+WHERE user_id = $1
+LIMIT 1;
+
+-- name: MarkTOTPStepUsed :execrows
+UPDATE user_totp_factors
+SET
+    last_used_step = $2,
+    updated_at = $3
+WHERE user_id = $1 AND last_used_step < $2;
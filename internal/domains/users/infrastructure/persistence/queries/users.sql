@@ -6,9 +6,14 @@ INSERT INTO users (
     password_hash,
     is_active,
     created_at,
-    updated_at
+    updated_at,
+    role,
+    email_verified_at,
+    failed_login_attempts,
+    lockout_count,
+    locked_until
 ) VALUES (
-    $1, $2, $3, $4, $5, $6, $7
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12
 ) RETURNING *;
 
 -- name: GetUserByID :one
@@ -16,11 +21,27 @@ SELECT * FROM users
 WHERE id = $1 AND is_active = true
 LIMIT 1;
 
+-- name: GetUserByIDIncludingInactive :one
+SELECT * FROM users
+WHERE id = $1
+LIMIT 1;
+
+-- name: GetUserForUpdate :one
+SELECT * FROM users
+WHERE id = $1
+LIMIT 1
+FOR UPDATE;
+
 -- name: GetUserByEmail :one
 SELECT * FROM users
 WHERE email = $1 AND is_active = true
 LIMIT 1;
 
+-- name: GetUserByEmailIncludingInactive :one
+SELECT * FROM users
+WHERE email = $1
+LIMIT 1;
+
 -- name: UpdateUser :one
 UPDATE users
 SET
@@ -28,15 +49,22 @@ SET
     name = $3,
     password_hash = $4,
     is_active = $5,
-    updated_at = $6
-WHERE id = $1
+    updated_at = $6,
+    role = $7,
+    email_verified_at = $8,
+    failed_login_attempts = $9,
+    lockout_count = $10,
+    locked_until = $11,
+    version = version + 1
+WHERE id = $1 AND version = $12
 RETURNING *;
 
 -- name: DeleteUser :exec
 UPDATE users
 SET
     is_active = false,
-    updated_at = $2
+    updated_at = $2,
+    version = version + 1
 WHERE id = $1;
 
 -- name: ListUsers :many
@@ -0,0 +1,83 @@
+package persistence
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/domains/users/domain"
+	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/domains/users/infrastructure/persistence/sqlc"
+	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/platform/database"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+/*
+RecoveryCodeRepository implements the domain.RecoveryCodeRepository interface using SQLC.
+*/
+type RecoveryCodeRepository struct {
+	pool      *pgxpool.Pool
+	queries   *sqlc.Queries
+	txManager database.TxManager
+}
+
+/*
+NewRecoveryCodeRepository creates a new RecoveryCodeRepository instance.
+Requires a pgxpool.Pool for database connectivity and the application's TxManager.
+*/
+func NewRecoveryCodeRepository(pool *pgxpool.Pool, txManager database.TxManager) *RecoveryCodeRepository {
+	return &RecoveryCodeRepository{
+		pool:      pool,
+		queries:   sqlc.New(pool),
+		txManager: txManager,
+	}
+}
+
+/*
+ReplaceForUser deletes the user's existing codes and stores the given ones
+in a single transaction.
+*/
+func (r *RecoveryCodeRepository) ReplaceForUser(ctx context.Context, userID uuid.UUID, codes []*domain.RecoveryCode) error {
+	return r.txManager.WithTransaction(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		queries := r.queries.WithTx(tx)
+
+		if err := queries.DeleteRecoveryCodesForUser(ctx, uuidToPgtype(userID)); err != nil {
+			return fmt.Errorf("failed to delete recovery codes: %w", err)
+		}
+
+		for _, code := range codes {
+			err := queries.CreateRecoveryCode(ctx, sqlc.CreateRecoveryCodeParams{
+				ID:        uuidToPgtype(code.ID),
+				UserID:    uuidToPgtype(code.UserID),
+				CodeHash:  code.CodeHash,
+				CreatedAt: timeToPgtype(code.CreatedAt),
+			})
+			if err != nil {
+				return fmt.Errorf("failed to create recovery code: %w", err)
+			}
+		}
+
+		return nil
+	})
+}
+
+/*
+Use marks the unused code with the given hash as used.
+Returns ErrInvalidMFACode if the user has no unused code with that hash.
+*/
+func (r *RecoveryCodeRepository) Use(ctx context.Context, userID uuid.UUID, codeHash string, at time.Time) error {
+	rows, err := queriesFor(ctx, r.queries).UseRecoveryCode(ctx, sqlc.UseRecoveryCodeParams{
+		UserID:   uuidToPgtype(userID),
+		CodeHash: codeHash,
+		UsedAt:   timeToPgtype(at),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to use recovery code: %w", err)
+	}
+	if rows == 0 {
+		return domain.ErrInvalidMFACode
+	}
+
+	return nil
+}
@@ -0,0 +1,134 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/domains/users/domain"
+	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/domains/users/infrastructure/persistence/sqlc"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+/*
+RefreshTokenRepository implements the domain.RefreshTokenRepository interface using SQLC.
+*/
+type RefreshTokenRepository struct {
+	pool    *pgxpool.Pool
+	queries *sqlc.Queries
+}
+
+/*
+NewRefreshTokenRepository creates a new RefreshTokenRepository instance.
+Requires a pgxpool.Pool for database connectivity.
+*/
+func NewRefreshTokenRepository(pool *pgxpool.Pool) *RefreshTokenRepository {
+	return &RefreshTokenRepository{
+		pool:    pool,
+		queries: sqlc.New(pool),
+	}
+}
+
+/*
+Save persists a newly issued refresh token.
+*/
+func (r *RefreshTokenRepository) Save(ctx context.Context, token *domain.RefreshToken) error {
+	params := sqlc.CreateRefreshTokenParams{
+		ID:        uuidToPgtype(token.ID),
+		UserID:    uuidToPgtype(token.UserID),
+		FamilyID:  uuidToPgtype(token.FamilyID),
+		TokenHash: token.TokenHash,
+		ExpiresAt: timeToPgtype(token.ExpiresAt),
+		CreatedAt: timeToPgtype(token.CreatedAt),
+	}
+
+	if _, err := queriesFor(ctx, r.queries).CreateRefreshToken(ctx, params); err != nil {
+		return fmt.Errorf("failed to create refresh token: %w", err)
+	}
+
+	return nil
+}
+
+/*
+FindByHash retrieves a refresh token by the hash of its value.
+Returns ErrRefreshTokenNotFound if no token matches.
+*/
+func (r *RefreshTokenRepository) FindByHash(ctx context.Context, tokenHash string) (*domain.RefreshToken, error) {
+	sqlcToken, err := queriesFor(ctx, r.queries).GetRefreshTokenByHash(ctx, tokenHash)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrRefreshTokenNotFound
+		}
+		return nil, fmt.Errorf("failed to get refresh token: %w", err)
+	}
+
+	return toDomainRefreshToken(sqlcToken), nil
+}
+
+/*
+MarkRotated records that the token was exchanged for a new one.
+Returns ErrRefreshTokenReused if the token had already been rotated or revoked.
+*/
+func (r *RefreshTokenRepository) MarkRotated(ctx context.Context, id uuid.UUID, at time.Time) error {
+	rows, err := queriesFor(ctx, r.queries).MarkRefreshTokenRotated(ctx, sqlc.MarkRefreshTokenRotatedParams{
+		ID:        uuidToPgtype(id),
+		RotatedAt: timeToPgtype(at),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to rotate refresh token: %w", err)
+	}
+	if rows == 0 {
+		return domain.ErrRefreshTokenReused
+	}
+
+	return nil
+}
+
+/*
+RevokeFamily revokes every still-active token in the given family.
+*/
+func (r *RefreshTokenRepository) RevokeFamily(ctx context.Context, familyID uuid.UUID, at time.Time) error {
+	err := queriesFor(ctx, r.queries).RevokeRefreshTokenFamily(ctx, sqlc.RevokeRefreshTokenFamilyParams{
+		FamilyID:  uuidToPgtype(familyID),
+		RevokedAt: timeToPgtype(at),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to revoke refresh token family: %w", err)
+	}
+
+	return nil
+}
+
+/*
+RevokeAllForUser revokes every still-active token of the given user.
+*/
+func (r *RefreshTokenRepository) RevokeAllForUser(ctx context.Context, userID uuid.UUID, at time.Time) error {
+	err := queriesFor(ctx, r.queries).RevokeRefreshTokensForUser(ctx, sqlc.RevokeRefreshTokensForUserParams{
+		UserID:    uuidToPgtype(userID),
+		RevokedAt: timeToPgtype(at),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to revoke refresh tokens for user: %w", err)
+	}
+
+	return nil
+}
+
+/*
+toDomainRefreshToken maps a SQLC RefreshToken model to a domain RefreshToken entity.
+*/
+func toDomainRefreshToken(sqlcToken sqlc.RefreshToken) *domain.RefreshToken {
+	return &domain.RefreshToken{
+		ID:        pgtypeToUUID(sqlcToken.ID),
+		UserID:    pgtypeToUUID(sqlcToken.UserID),
+		FamilyID:  pgtypeToUUID(sqlcToken.FamilyID),
+		TokenHash: sqlcToken.TokenHash,
+		ExpiresAt: pgtypeToTime(sqlcToken.ExpiresAt),
+		CreatedAt: pgtypeToTime(sqlcToken.CreatedAt),
+		RotatedAt: pgtypeToTimePtr(sqlcToken.RotatedAt),
+		RevokedAt: pgtypeToTimePtr(sqlcToken.RevokedAt),
+	}
+}
@@ -2,66 +2,94 @@ package persistence
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"time"
 
 	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/domains/users/domain"
 	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/domains/users/infrastructure/persistence/sqlc"
+	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/platform/auth"
+	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/platform/database"
+	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/platform/outbox"
+	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/platform/requestinfo"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+// usersEmailKey is the unique constraint on users.email
+const usersEmailKey = "users_email_key"
+
 /*
 UserRepository implements the domain.UserRepository interface using SQLC.
 This is the infrastructure layer implementation that handles actual database operations.
 It depends on SQLC-generated code for type-safe database queries.
+Queries run in the transaction carried by the context, if any.
+Save, Update and Delete write an audit log entry, and Save and Update store the
+domain events recorded by the user in the outbox, in the same transaction as the
+user's changes. The audit entry's actor, request ID and IP address are taken
+from the context (see auth.PrincipalFromContext and requestinfo.FromContext).
 */
 type UserRepository struct {
-	pool    *pgxpool.Pool
-	queries *sqlc.Queries
+	pool      *pgxpool.Pool
+	queries   *sqlc.Queries
+	txManager database.TxManager
 }
 
 /*
 NewUserRepository creates a new UserRepository instance.
-Requires a pgxpool.Pool for database connectivity.
-The SQLC Queries instance is created from the pool.
+Requires a pgxpool.Pool for database connectivity and the application's
+TxManager, so the transactions it opens for auditing use the configured
+isolation level and retries. The SQLC Queries instance is created from the pool.
 */
-func NewUserRepository(pool *pgxpool.Pool) *UserRepository {
+func NewUserRepository(pool *pgxpool.Pool, txManager database.TxManager) *UserRepository {
 	return &UserRepository{
-		pool:    pool,
-		queries: sqlc.New(pool),
+		pool:      pool,
+		queries:   sqlc.New(pool),
+		txManager: txManager,
 	}
 }
 
 /*
 Save persists a new user to the database.
 Maps the domain User entity to SQLC parameters and executes the insert query.
+The creation is audited and the user's recorded events are stored in the outbox
+in the same transaction.
 Returns ErrEmailAlreadyExists if a user with the same email already exists.
 */
 func (r *UserRepository) Save(ctx context.Context, user *domain.User) error {
 	params := sqlc.CreateUserParams{
-		ID:           uuidToPgtype(user.ID),
-		Email:        user.Email.Value(),
-		Name:         user.Name,
-		PasswordHash: user.PasswordHash,
-		IsActive:     user.IsActive,
-		CreatedAt:    timeToPgtype(user.CreatedAt),
-		UpdatedAt:    timeToPgtype(user.UpdatedAt),
+		ID:                  uuidToPgtype(user.ID),
+		Email:               user.Email.Value(),
+		Name:                user.Name,
+		PasswordHash:        user.PasswordHash,
+		IsActive:            user.IsActive,
+		CreatedAt:           timeToPgtype(user.CreatedAt),
+		UpdatedAt:           timeToPgtype(user.UpdatedAt),
+		Role:                user.Role.String(),
+		EmailVerifiedAt:     timePtrToPgtype(user.EmailVerifiedAt),
+		FailedLoginAttempts: int32(user.FailedLoginAttempts),
+		LockoutCount:        int32(user.LockoutCount),
+		LockedUntil:         timePtrToPgtype(user.LockedUntil),
 	}
 
-	_, err := r.queries.CreateUser(ctx, params)
-	if err != nil {
-		// Check for unique constraint violation (email already exists)
-		if isUniqueViolation(err) {
-			return domain.ErrEmailAlreadyExists
+	return r.txManager.Do(ctx, func(ctx context.Context) error {
+		_, err := queriesFor(ctx, r.queries).CreateUser(ctx, params)
+		if err != nil {
+			// Check for unique constraint violation (email already exists)
+			if database.IsConstraintViolation(err, database.ErrUniqueViolation, usersEmailKey) {
+				return domain.ErrEmailAlreadyExists
+			}
+			return fmt.Errorf("failed to create user: %w", database.Classify(err))
 		}
-		return fmt.Errorf("failed to create user: %w", err)
-	}
 
-	return nil
+		if err := r.audit(ctx, domain.AuditActionUserCreated, user.ID, domain.DiffUsers(nil, user)); err != nil {
+			return err
+		}
+		return r.appendEvents(ctx, user)
+	})
 }
 
 /*
@@ -70,7 +98,23 @@ Returns ErrUserNotFound if no active user exists with the given ID.
 Maps the SQLC User model to a domain User entity.
 */
 func (r *UserRepository) FindByID(ctx context.Context, id uuid.UUID) (*domain.User, error) {
-	sqlcUser, err := r.queries.GetUserByID(ctx, uuidToPgtype(id))
+	sqlcUser, err := queriesFor(ctx, r.queries).GetUserByID(ctx, uuidToPgtype(id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrUserNotFound
+		}
+		return nil, fmt.Errorf("failed to get user by ID: %w", err)
+	}
+
+	return r.toDomainUser(sqlcUser)
+}
+
+/*
+FindByIDIncludingInactive retrieves a user by their unique identifier, including deactivated users.
+Returns ErrUserNotFound if no user exists with the given ID.
+*/
+func (r *UserRepository) FindByIDIncludingInactive(ctx context.Context, id uuid.UUID) (*domain.User, error) {
+	sqlcUser, err := queriesFor(ctx, r.queries).GetUserByIDIncludingInactive(ctx, uuidToPgtype(id))
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, domain.ErrUserNotFound
@@ -87,7 +131,23 @@ Returns ErrUserNotFound if no active user exists with the given email.
 Email lookup is case-insensitive (handled by database).
 */
 func (r *UserRepository) FindByEmail(ctx context.Context, email domain.Email) (*domain.User, error) {
-	sqlcUser, err := r.queries.GetUserByEmail(ctx, email.Value())
+	sqlcUser, err := queriesFor(ctx, r.queries).GetUserByEmail(ctx, email.Value())
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrUserNotFound
+		}
+		return nil, fmt.Errorf("failed to get user by email: %w", err)
+	}
+
+	return r.toDomainUser(sqlcUser)
+}
+
+/*
+FindByEmailIncludingInactive retrieves a user by email address, including deactivated users.
+Returns ErrUserNotFound if no user exists with the given email.
+*/
+func (r *UserRepository) FindByEmailIncludingInactive(ctx context.Context, email domain.Email) (*domain.User, error) {
+	sqlcUser, err := queriesFor(ctx, r.queries).GetUserByEmailIncludingInactive(ctx, email.Value())
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, domain.ErrUserNotFound
@@ -100,37 +160,71 @@ func (r *UserRepository) FindByEmail(ctx context.Context, email domain.Email) (*
 
 /*
 Update modifies an existing user in the database.
-Maps the domain User entity to SQLC parameters and executes the update query.
+The stored row is locked and its version compared with user.Version before
+the update query runs; on success user.Version is set to the new version.
+The fields that changed are audited and the user's recorded events are stored
+in the outbox in the same transaction.
 Returns ErrUserNotFound if the user doesn't exist.
+Returns ErrConcurrentModification if the user was updated since it was loaded.
 Returns ErrEmailAlreadyExists if the new email conflicts with another user.
 */
 func (r *UserRepository) Update(ctx context.Context, user *domain.User) error {
 	params := sqlc.UpdateUserParams{
-		ID:           uuidToPgtype(user.ID),
-		Email:        user.Email.Value(),
-		Name:         user.Name,
-		PasswordHash: user.PasswordHash,
-		IsActive:     user.IsActive,
-		UpdatedAt:    timeToPgtype(user.UpdatedAt),
+		ID:                  uuidToPgtype(user.ID),
+		Email:               user.Email.Value(),
+		Name:                user.Name,
+		PasswordHash:        user.PasswordHash,
+		IsActive:            user.IsActive,
+		UpdatedAt:           timeToPgtype(user.UpdatedAt),
+		Role:                user.Role.String(),
+		EmailVerifiedAt:     timePtrToPgtype(user.EmailVerifiedAt),
+		FailedLoginAttempts: int32(user.FailedLoginAttempts),
+		LockoutCount:        int32(user.LockoutCount),
+		LockedUntil:         timePtrToPgtype(user.LockedUntil),
+		Version:             int32(user.Version),
 	}
 
-	_, err := r.queries.UpdateUser(ctx, params)
-	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return domain.ErrUserNotFound
+	var version int
+	err := r.txManager.Do(ctx, func(ctx context.Context) error {
+		before, err := r.lockUser(ctx, user.ID)
+		if err != nil {
+			return err
+		}
+		if before.Version != user.Version {
+			return domain.ErrConcurrentModification
+		}
+
+		updated, err := queriesFor(ctx, r.queries).UpdateUser(ctx, params)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return domain.ErrConcurrentModification
+			}
+			if database.IsConstraintViolation(err, database.ErrUniqueViolation, usersEmailKey) {
+				return domain.ErrEmailAlreadyExists
+			}
+			return fmt.Errorf("failed to update user: %w", database.Classify(err))
 		}
-		if isUniqueViolation(err) {
-			return domain.ErrEmailAlreadyExists
+		version = int(updated.Version)
+
+		if changes := domain.DiffUsers(before, user); len(changes) > 0 {
+			if err := r.audit(ctx, domain.UpdateAuditAction(changes), user.ID, changes); err != nil {
+				return err
+			}
 		}
-		return fmt.Errorf("failed to update user: %w", err)
+		return r.appendEvents(ctx, user)
+	})
+	if err != nil {
+		return err
 	}
 
+	user.Version = version
 	return nil
 }
 
 /*
 Delete soft deletes a user by setting is_active = false.
-The user record remains in the database for auditing purposes.
+The user record remains in the database for auditing purposes, and the
+deletion is audited in the same transaction.
 Returns ErrUserNotFound if the user doesn't exist.
 */
 func (r *UserRepository) Delete(ctx context.Context, id uuid.UUID) error {
@@ -139,17 +233,94 @@ func (r *UserRepository) Delete(ctx context.Context, id uuid.UUID) error {
 		UpdatedAt: timeToPgtype(time.Now()),
 	}
 
-	err := r.queries.DeleteUser(ctx, params)
+	return r.txManager.Do(ctx, func(ctx context.Context) error {
+		before, err := r.lockUser(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if err := queriesFor(ctx, r.queries).DeleteUser(ctx, params); err != nil {
+			return fmt.Errorf("failed to delete user: %w", err)
+		}
+
+		changes := make(map[string]domain.FieldChange)
+		if before.IsActive {
+			changes["is_active"] = domain.FieldChange{Before: true, After: false}
+		}
+		return r.audit(ctx, domain.AuditActionUserDeleted, id, changes)
+	})
+}
+
+/*
+lockUser loads a user, active or not, and locks its row until the end of the
+transaction carried by ctx, so the state it returns is the one being changed.
+Returns ErrUserNotFound if no user exists with the given ID.
+*/
+func (r *UserRepository) lockUser(ctx context.Context, id uuid.UUID) (*domain.User, error) {
+	sqlcUser, err := queriesFor(ctx, r.queries).GetUserForUpdate(ctx, uuidToPgtype(id))
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			return domain.ErrUserNotFound
+			return nil, domain.ErrUserNotFound
 		}
-		return fmt.Errorf("failed to delete user: %w", err)
+		return nil, fmt.Errorf("failed to lock user: %w", err)
+	}
+
+	return r.toDomainUser(sqlcUser)
+}
+
+/*
+audit writes an audit log entry for a change to the target user.
+The actor is the authenticated principal in ctx, if any; the request ID and IP
+address come from the request info in ctx, if any.
+*/
+func (r *UserRepository) audit(ctx context.Context, action string, targetID uuid.UUID, changes map[string]domain.FieldChange) error {
+	var actorID *uuid.UUID
+	if principal, ok := auth.PrincipalFromContext(ctx); ok {
+		actorID = &principal.UserID
+	}
+	info, _ := requestinfo.FromContext(ctx)
+
+	entry := domain.NewAuditEntry(actorID, action, targetID, changes, info.RequestID, info.IP)
+
+	payload, err := json.Marshal(entry.Changes)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit log changes: %w", err)
+	}
+
+	err = queriesFor(ctx, r.queries).CreateAuditLogEntry(ctx, sqlc.CreateAuditLogEntryParams{
+		ID:           uuidToPgtype(entry.ID),
+		ActorID:      uuidPtrToPgtype(entry.ActorID),
+		Action:       entry.Action,
+		TargetUserID: uuidToPgtype(entry.TargetUserID),
+		Changes:      payload,
+		RequestID:    textToPgtype(entry.RequestID),
+		IpAddress:    textToPgtype(entry.IPAddress),
+		CreatedAt:    timeToPgtype(entry.CreatedAt),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to write audit log entry: %w", err)
 	}
 
 	return nil
 }
 
+/*
+appendEvents stores the user's recorded events in the outbox.
+It must run in the transaction that persists the user's changes.
+*/
+func (r *UserRepository) appendEvents(ctx context.Context, user *domain.User) error {
+	events := user.Events()
+	if len(events) == 0 {
+		return nil
+	}
+
+	messages, err := toOutboxMessages(events)
+	if err != nil {
+		return err
+	}
+	return outbox.Append(ctx, messages...)
+}
+
 /*
 List retrieves a paginated list of active users.
 Results are ordered by created_at DESC (newest first).
@@ -161,7 +332,7 @@ func (r *UserRepository) List(ctx context.Context, limit, offset int) ([]*domain
 		Offset: int32(offset),
 	}
 
-	sqlcUsers, err := r.queries.ListUsers(ctx, params)
+	sqlcUsers, err := queriesFor(ctx, r.queries).ListUsers(ctx, params)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list users: %w", err)
 	}
@@ -183,7 +354,7 @@ Count returns the total number of active users.
 Useful for pagination calculations.
 */
 func (r *UserRepository) Count(ctx context.Context) (int64, error) {
-	count, err := r.queries.CountUsers(ctx)
+	count, err := queriesFor(ctx, r.queries).CountUsers(ctx)
 	if err != nil {
 		return 0, fmt.Errorf("failed to count users: %w", err)
 	}
@@ -191,6 +362,27 @@ func (r *UserRepository) Count(ctx context.Context) (int64, error) {
 	return count, nil
 }
 
+// toOutboxMessages serializes user events for the outbox
+func toOutboxMessages(events []domain.Event) ([]outbox.Message, error) {
+	messages := make([]outbox.Message, len(events))
+	for i, event := range events {
+		payload, err := json.Marshal(event)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode %s event: %w", event.EventName(), err)
+		}
+
+		messages[i] = outbox.Message{
+			ID:            event.EventID(),
+			AggregateType: "user",
+			AggregateID:   event.AggregateID(),
+			EventType:     event.EventName(),
+			Payload:       payload,
+			OccurredAt:    event.OccurredAt(),
+		}
+	}
+	return messages, nil
+}
+
 /*
 toDomainUser maps a SQLC User model to a domain User entity.
 This is the anti-corruption layer that prevents database models from leaking into the domain.
@@ -201,42 +393,39 @@ func (r *UserRepository) toDomainUser(sqlcUser sqlc.User) (*domain.User, error)
 		return nil, fmt.Errorf("invalid email in database: %w", err)
 	}
 
+	role, err := domain.ParseRole(sqlcUser.Role)
+	if err != nil {
+		return nil, fmt.Errorf("invalid role in database: %w", err)
+	}
+
 	return &domain.User{
-		ID:           pgtypeToUUID(sqlcUser.ID),
-		Email:        email,
-		Name:         sqlcUser.Name,
-		PasswordHash: sqlcUser.PasswordHash,
-		IsActive:     sqlcUser.IsActive,
-		CreatedAt:    pgtypeToTime(sqlcUser.CreatedAt),
-		UpdatedAt:    pgtypeToTime(sqlcUser.UpdatedAt),
+		ID:                  pgtypeToUUID(sqlcUser.ID),
+		Email:               email,
+		Name:                sqlcUser.Name,
+		PasswordHash:        sqlcUser.PasswordHash,
+		Role:                role,
+		IsActive:            sqlcUser.IsActive,
+		EmailVerifiedAt:     pgtypeToTimePtr(sqlcUser.EmailVerifiedAt),
+		FailedLoginAttempts: int(sqlcUser.FailedLoginAttempts),
+		LockoutCount:        int(sqlcUser.LockoutCount),
+		LockedUntil:         pgtypeToTimePtr(sqlcUser.LockedUntil),
+		CreatedAt:           pgtypeToTime(sqlcUser.CreatedAt),
+		UpdatedAt:           pgtypeToTime(sqlcUser.UpdatedAt),
+		Version:             int(sqlcUser.Version),
 	}, nil
 }
 
 /*
-isUniqueViolation checks if the error is a PostgreSQL unique constraint violation.
-This is used to detect email conflicts.
+queriesFor returns the queries to run for ctx.
+When ctx carries a transaction (see database.TxManager), the queries are bound
+to it with WithTx so the call joins the caller's unit of work; otherwise they
+run directly on the pool.
 */
-func isUniqueViolation(err error) bool {
-	// PostgreSQL error code 23505 is unique_violation
-	// This is a simplified check - in production you might want to use pgconn.PgError
-	return err != nil && (err.Error() == "ERROR: duplicate key value violates unique constraint (SQLSTATE 23505)" ||
-		contains(err.Error(), "unique constraint") ||
-		contains(err.Error(), "duplicate key"))
-}
-
-func contains(s, substr string) bool {
-	return len(s) >= len(substr) && (s == substr || len(s) > len(substr) &&
-		(s[:len(substr)] == substr || s[len(s)-len(substr):] == substr ||
-			len(s) > len(substr)+1 && findSubstring(s, substr)))
-}
-
-func findSubstring(s, substr string) bool {
-	for i := 0; i <= len(s)-len(substr); i++ {
-		if s[i:i+len(substr)] == substr {
-			return true
-		}
+func queriesFor(ctx context.Context, queries *sqlc.Queries) *sqlc.Queries {
+	if tx, ok := database.TxFromContext(ctx); ok {
+		return queries.WithTx(tx)
 	}
-	return false
+	return queries
 }
 
 // Type conversion helpers
@@ -252,6 +441,29 @@ func uuidToPgtype(id uuid.UUID) pgtype.UUID {
 	}
 }
 
+/*
+uuidPtrToPgtype converts a *uuid.UUID to a nullable pgtype.UUID.
+A nil ID is stored as NULL.
+*/
+func uuidPtrToPgtype(id *uuid.UUID) pgtype.UUID {
+	if id == nil {
+		return pgtype.UUID{}
+	}
+	return uuidToPgtype(*id)
+}
+
+/*
+pgtypeToUUIDPtr converts a nullable pgtype.UUID to *uuid.UUID.
+Returns nil when the column is NULL.
+*/
+func pgtypeToUUIDPtr(pgUUID pgtype.UUID) *uuid.UUID {
+	if !pgUUID.Valid {
+		return nil
+	}
+	id := uuid.UUID(pgUUID.Bytes)
+	return &id
+}
+
 /*
 pgtypeToUUID converts pgtype.UUID to uuid.UUID.
 This is needed to convert SQLC types back to domain types.
@@ -278,3 +490,37 @@ This is needed to convert SQLC types back to domain types.
 func pgtypeToTime(pgTime pgtype.Timestamp) time.Time {
 	return pgTime.Time
 }
+
+/*
+timePtrToPgtype converts a *time.Time to a nullable pgtype.Timestamp.
+A nil time is stored as NULL.
+*/
+func timePtrToPgtype(t *time.Time) pgtype.Timestamp {
+	if t == nil {
+		return pgtype.Timestamp{}
+	}
+	return timeToPgtype(*t)
+}
+
+/*
+textToPgtype converts a string to a nullable pgtype.Text.
+An empty string is stored as NULL.
+*/
+func textToPgtype(s string) pgtype.Text {
+	return pgtype.Text{
+		String: s,
+		Valid:  s != "",
+	}
+}
+
+/*
+pgtypeToTimePtr converts a nullable pgtype.Timestamp to *time.Time.
+Returns nil when the column is NULL.
+*/
+func pgtypeToTimePtr(pgTime pgtype.Timestamp) *time.Time {
+	if !pgTime.Valid {
+		return nil
+	}
+	t := pgTime.Time
+	return &t
+}
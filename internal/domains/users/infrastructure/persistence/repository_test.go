@@ -0,0 +1,278 @@
+package persistence
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/domains/users/domain"
+	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/domains/users/domain/domaintest"
+	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/platform/auth"
+	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/platform/database"
+	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/platform/database/dbtest"
+	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/platform/requestinfo"
+	"github.com/google/uuid"
+)
+
+/*
+These tests run against Postgres through dbtest and are skipped unless
+TEST_DATABASE_URL is set (see make test-db). Every subtest runs in its own
+rolled-back transaction on a freshly migrated schema.
+*/
+
+func TestUserRepository(t *testing.T) {
+	db := dbtest.Open(t)
+
+	domaintest.TestUserRepository(t, func(t *testing.T) (context.Context, domain.UserRepository) {
+		return db.Tx(t), NewUserRepository(db.Pool, database.NewTxManager(db.Pool))
+	})
+}
+
+func TestUserRepositoryAuditLog(t *testing.T) {
+	db := dbtest.Open(t)
+	repo := NewUserRepository(db.Pool, database.NewTxManager(db.Pool))
+	auditLog := NewAuditLogRepository(db.Pool)
+
+	actorID := uuid.New()
+	ctx := auth.WithPrincipal(db.Tx(t), &auth.Principal{UserID: actorID, Role: "admin"})
+	ctx = requestinfo.WithInfo(ctx, requestinfo.Info{RequestID: "req-1", IP: "203.0.113.7"})
+
+	user := newTestUser(t, "alice@example.com")
+	if err := repo.Save(ctx, user); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	if err := user.UpdateProfile("Alice Cooper", user.Email); err != nil {
+		t.Fatalf("UpdateProfile() error = %v", err)
+	}
+	if err := user.ChangePassword("$2a$10$anotherhashanotherhash"); err != nil {
+		t.Fatalf("ChangePassword() error = %v", err)
+	}
+	if err := repo.Update(ctx, user); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+
+	// An update that changes nothing is not audited
+	if err := repo.Update(ctx, user); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+
+	// Neither are a rehash and a failed login below the lockout threshold
+	policy := domain.LockoutPolicy{Threshold: 2, BaseDuration: time.Minute, MaxDuration: time.Hour}
+	if err := user.RehashPassword("$2a$12$rehashedrehashedrehash"); err != nil {
+		t.Fatalf("RehashPassword() error = %v", err)
+	}
+	user.RecordFailedLogin(policy, time.Now())
+	if err := repo.Update(ctx, user); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+
+	// Reaching the threshold locks the account, which is audited as a lockout
+	user.RecordFailedLogin(policy, time.Now())
+	if err := repo.Update(ctx, user); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+
+	if err := repo.Delete(ctx, user.ID); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+
+	entries, err := auditLog.ListByTargetUser(ctx, user.ID, domain.AuditFilter{Limit: 10})
+	if err != nil {
+		t.Fatalf("ListByTargetUser() error = %v", err)
+	}
+
+	wantActions := []string{domain.AuditActionUserDeleted, domain.AuditActionUserLockout, domain.AuditActionUserUpdated, domain.AuditActionUserCreated}
+	if len(entries) != len(wantActions) {
+		t.Fatalf("ListByTargetUser() returned %d entries, want %d", len(entries), len(wantActions))
+	}
+	for i, entry := range entries {
+		if entry.Action != wantActions[i] {
+			t.Errorf("entries[%d].Action = %q, want %q", i, entry.Action, wantActions[i])
+		}
+		if entry.ActorID == nil || *entry.ActorID != actorID {
+			t.Errorf("entries[%d].ActorID = %v, want %s", i, entry.ActorID, actorID)
+		}
+		if entry.RequestID != "req-1" || entry.IPAddress != "203.0.113.7" {
+			t.Errorf("entries[%d] request = (%q, %q), want (%q, %q)", i, entry.RequestID, entry.IPAddress, "req-1", "203.0.113.7")
+		}
+	}
+
+	if got := entries[1].Changes; len(got) != 1 || got["locked_until"].Before != nil || got["locked_until"].After == nil {
+		t.Errorf("lockout changes = %+v, want only locked_until being set", got)
+	}
+
+	updated := entries[2].Changes
+	if got := updated["name"]; got.Before != "Test User" || got.After != "Alice Cooper" {
+		t.Errorf("name change = %+v, want Test User -> Alice Cooper", got)
+	}
+	if got := updated["password"]; got.Before != domain.AuditRedacted || got.After != domain.AuditRedacted {
+		t.Errorf("password change = %+v, want it redacted", got)
+	}
+	if _, ok := updated["email"]; ok {
+		t.Error("unchanged email was audited")
+	}
+
+	if got := entries[0].Changes["is_active"]; got.Before != true || got.After != false {
+		t.Errorf("is_active change on delete = %+v, want true -> false", got)
+	}
+
+	// The password hash never reaches the audit log
+	tx, _ := database.TxFromContext(ctx)
+	var raw string
+	err = tx.QueryRow(ctx, "SELECT string_agg(changes::text, ' ') FROM audit_log WHERE target_user_id = $1", user.ID).Scan(&raw)
+	if err != nil {
+		t.Fatalf("failed to read audit log: %v", err)
+	}
+	if strings.Contains(raw, "$2a$") {
+		t.Errorf("audit log contains a password hash: %s", raw)
+	}
+}
+
+func TestUserRepositoryOutbox(t *testing.T) {
+	db := dbtest.Open(t)
+	repo := NewUserRepository(db.Pool, database.NewTxManager(db.Pool))
+	ctx := db.Tx(t)
+
+	user := newTestUser(t, "alice@example.com")
+	if err := repo.Save(ctx, user); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	user.PullEvents()
+
+	user.Deactivate()
+	if err := repo.Update(ctx, user); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+
+	tx, _ := database.TxFromContext(ctx)
+	rows, err := tx.Query(ctx, "SELECT event_type, payload FROM outbox_events WHERE aggregate_id = $1 ORDER BY occurred_at", user.ID)
+	if err != nil {
+		t.Fatalf("failed to read outbox: %v", err)
+	}
+	defer rows.Close()
+
+	var eventTypes []string
+	for rows.Next() {
+		var (
+			eventType string
+			payload   []byte
+		)
+		if err := rows.Scan(&eventType, &payload); err != nil {
+			t.Fatalf("failed to scan outbox row: %v", err)
+		}
+		if !json.Valid(payload) {
+			t.Errorf("%s payload is not JSON: %s", eventType, payload)
+		}
+		eventTypes = append(eventTypes, eventType)
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatalf("failed to read outbox: %v", err)
+	}
+
+	want := []string{domain.EventUserRegistered, domain.EventUserDeactivated}
+	if strings.Join(eventTypes, ",") != strings.Join(want, ",") {
+		t.Errorf("outbox events = %v, want %v", eventTypes, want)
+	}
+}
+
+func TestAuditLogRepositoryFilters(t *testing.T) {
+	db := dbtest.Open(t)
+	repo := NewUserRepository(db.Pool, database.NewTxManager(db.Pool))
+	auditLog := NewAuditLogRepository(db.Pool)
+	ctx := db.Tx(t)
+
+	user := newTestUser(t, "alice@example.com")
+	if err := repo.Save(ctx, user); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	for _, name := range []string{"Alice One", "Alice Two", "Alice Three"} {
+		user.Name = name
+		if err := repo.Update(ctx, user); err != nil {
+			t.Fatalf("Update() error = %v", err)
+		}
+	}
+
+	actorID := uuid.New()
+	future := time.Now().Add(time.Hour)
+	tests := []struct {
+		name   string
+		filter domain.AuditFilter
+		want   int64
+	}{
+		{"no filter", domain.AuditFilter{}, 4},
+		{"action", domain.AuditFilter{Action: domain.AuditActionUserUpdated}, 3},
+		{"field", domain.AuditFilter{Field: "role"}, 1},
+		{"actor", domain.AuditFilter{ActorID: &actorID}, 0},
+		{"from", domain.AuditFilter{From: &future}, 0},
+		{"to", domain.AuditFilter{To: &future}, 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			count, err := auditLog.CountByTargetUser(ctx, user.ID, tt.filter)
+			if err != nil {
+				t.Fatalf("CountByTargetUser() error = %v", err)
+			}
+			if count != tt.want {
+				t.Errorf("CountByTargetUser() = %d, want %d", count, tt.want)
+			}
+		})
+	}
+
+	page, err := auditLog.ListByTargetUser(ctx, user.ID, domain.AuditFilter{Action: domain.AuditActionUserUpdated, Limit: 2, Offset: 1})
+	if err != nil {
+		t.Fatalf("ListByTargetUser() error = %v", err)
+	}
+	if len(page) != 2 {
+		t.Fatalf("ListByTargetUser() returned %d entries, want 2", len(page))
+	}
+	if got := page[0].Changes["name"].After; got != "Alice Two" {
+		t.Errorf("first entry of the second page changed name to %v, want Alice Two", got)
+	}
+
+	// The audit log is append-only
+	tx, _ := database.TxFromContext(ctx)
+	if _, err := tx.Exec(ctx, "DELETE FROM audit_log WHERE target_user_id = $1", user.ID); err == nil {
+		t.Error("deleting audit log entries succeeded, want an error")
+	}
+}
+
+func TestUserRepositorySaveDuplicateID(t *testing.T) {
+	db := dbtest.Open(t)
+	repo := NewUserRepository(db.Pool, database.NewTxManager(db.Pool))
+	ctx := db.Tx(t)
+
+	user := newTestUser(t, "alice@example.com")
+	if err := repo.Save(ctx, user); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	// Only the email constraint means the email is taken; the primary key does not
+	user.Email, _ = domain.NewEmail("bob@example.com")
+	err := repo.Save(ctx, user)
+	if !errors.Is(err, database.ErrUniqueViolation) {
+		t.Fatalf("Save() of an existing ID error = %v, want %v", err, database.ErrUniqueViolation)
+	}
+	if errors.Is(err, domain.ErrEmailAlreadyExists) {
+		t.Errorf("Save() of an existing ID error = %v, want it not to be %v", err, domain.ErrEmailAlreadyExists)
+	}
+}
+
+// newTestUser creates an unsaved member with the given email
+func newTestUser(t *testing.T, email string) *domain.User {
+	t.Helper()
+
+	e, err := domain.NewEmail(email)
+	if err != nil {
+		t.Fatalf("NewEmail() error = %v", err)
+	}
+	user, err := domain.NewUser(e, "Test User", "$2a$10$abcdefghijklmnopqrstuv")
+	if err != nil {
+		t.Fatalf("NewUser() error = %v", err)
+	}
+	return user
+}
@@ -8,6 +8,158 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+// Stores hashed personal API keys for machine-to-machine access
+type ApiKey struct {
+	// Unique identifier for the API key (UUID v4)
+	ID pgtype.UUID `json:"id"`
+	// User the API key acts as
+	UserID pgtype.UUID `json:"user_id"`
+	// Name given by the user to tell keys apart
+	Name string `json:"name"`
+	// Non-secret start of the key, shown to identify it
+	Prefix string `json:"prefix"`
+	// SHA-256 hash of the key
+	KeyHash string `json:"key_hash"`
+	// Permissions the key is limited to
+	Scopes []string `json:"scopes"`
+	// Timestamp after which the key is rejected, NULL if it never expires
+	ExpiresAt pgtype.Timestamp `json:"expires_at"`
+	// Timestamp when the key last authenticated a request
+	LastUsedAt pgtype.Timestamp `json:"last_used_at"`
+	// Timestamp when the key was revoked
+	RevokedAt pgtype.Timestamp `json:"revoked_at"`
+	// Timestamp when the key was created
+	CreatedAt pgtype.Timestamp `json:"created_at"`
+}
+
+// Append-only log of changes to user accounts
+type AuditLog struct {
+	// Unique identifier for the entry (UUID v4)
+	ID pgtype.UUID `json:"id"`
+	// Authenticated user who made the change, NULL for unauthenticated requests such as sign-up or password reset
+	ActorID pgtype.UUID `json:"actor_id"`
+	// What was done (e.g. user.updated)
+	Action string `json:"action"`
+	// User account that was changed; no foreign key, so entries outlive the account
+	TargetUserID pgtype.UUID `json:"target_user_id"`
+	// Changed fields as {"field": {"before": ..., "after": ...}}; the password hash is never included
+	Changes []byte `json:"changes"`
+	// ID of the HTTP request that made the change
+	RequestID pgtype.Text `json:"request_id"`
+	// Client IP address of the request that made the change
+	IpAddress pgtype.Text `json:"ip_address"`
+	// Timestamp when the change was made
+	CreatedAt pgtype.Timestamp `json:"created_at"`
+}
+
+// Stores hashed single-use email verification tokens
+type EmailVerificationToken struct {
+	// Unique identifier for the verification token (UUID v4)
+	ID pgtype.UUID `json:"id"`
+	// User whose email address is being verified
+	UserID pgtype.UUID `json:"user_id"`
+	// SHA-256 hash of the emailed verification token
+	TokenHash string `json:"token_hash"`
+	// Timestamp after which the token can no longer be used
+	ExpiresAt pgtype.Timestamp `json:"expires_at"`
+	// Timestamp when the token was issued
+	CreatedAt pgtype.Timestamp `json:"created_at"`
+	// Timestamp when the token was used or invalidated
+	UsedAt pgtype.Timestamp `json:"used_at"`
+}
+
+// Stores hashed single-use MFA recovery codes
+type MfaRecoveryCode struct {
+	// Unique identifier for the recovery code (UUID v4)
+	ID pgtype.UUID `json:"id"`
+	// User the recovery code belongs to
+	UserID pgtype.UUID `json:"user_id"`
+	// SHA-256 hash of the normalized recovery code
+	CodeHash string `json:"code_hash"`
+	// Timestamp when the code was generated
+	CreatedAt pgtype.Timestamp `json:"created_at"`
+	// Timestamp when the code was used
+	UsedAt pgtype.Timestamp `json:"used_at"`
+}
+
+// Domain events waiting to be delivered by the outbox relay
+type OutboxEvent struct {
+	// Unique event ID, also sent to sinks so consumers can drop duplicates
+	ID pgtype.UUID `json:"id"`
+	// Kind of aggregate that raised the event (e.g. user)
+	AggregateType string `json:"aggregate_type"`
+	// ID of the aggregate that raised the event
+	AggregateID pgtype.UUID `json:"aggregate_id"`
+	// Event name (e.g. user.registered)
+	EventType string `json:"event_type"`
+	// Event serialized as JSON
+	Payload []byte `json:"payload"`
+	// Timestamp when the event happened
+	OccurredAt pgtype.Timestamp `json:"occurred_at"`
+	// Timestamp when the event was stored
+	CreatedAt pgtype.Timestamp `json:"created_at"`
+	// Number of failed delivery attempts
+	Attempts int32 `json:"attempts"`
+	// Timestamp before which the event is not retried
+	NextAttemptAt pgtype.Timestamp `json:"next_attempt_at"`
+	// Error of the last failed delivery attempt
+	LastError pgtype.Text `json:"last_error"`
+	// Timestamp when the event was delivered to every sink, NULL while pending
+	PublishedAt pgtype.Timestamp `json:"published_at"`
+}
+
+// Stores hashed single-use password reset tokens
+type PasswordResetToken struct {
+	// Unique identifier for the reset token (UUID v4)
+	ID pgtype.UUID `json:"id"`
+	// User the reset was requested for
+	UserID pgtype.UUID `json:"user_id"`
+	// SHA-256 hash of the emailed reset token
+	TokenHash string `json:"token_hash"`
+	// Timestamp after which the token can no longer be used
+	ExpiresAt pgtype.Timestamp `json:"expires_at"`
+	// Timestamp when the reset was requested
+	CreatedAt pgtype.Timestamp `json:"created_at"`
+	// Timestamp when the token was used or invalidated
+	UsedAt pgtype.Timestamp `json:"used_at"`
+}
+
+// Stores hashed refresh tokens grouped into rotation families
+type RefreshToken struct {
+	// Unique identifier for the refresh token (UUID v4)
+	ID pgtype.UUID `json:"id"`
+	// User the token was issued to
+	UserID pgtype.UUID `json:"user_id"`
+	// Rotation family - all tokens descending from one login
+	FamilyID pgtype.UUID `json:"family_id"`
+	// SHA-256 hash of the opaque refresh token
+	TokenHash string `json:"token_hash"`
+	// Timestamp after which the token can no longer be used
+	ExpiresAt pgtype.Timestamp `json:"expires_at"`
+	// Timestamp when the token was issued
+	CreatedAt pgtype.Timestamp `json:"created_at"`
+	// Timestamp when the token was exchanged for a new one
+	RotatedAt pgtype.Timestamp `json:"rotated_at"`
+	// Timestamp when the token was revoked (logout or reuse detection)
+	RevokedAt pgtype.Timestamp `json:"revoked_at"`
+}
+
+// Stores encrypted TOTP secrets for two-factor authentication
+type UserTotpFactor struct {
+	// User the authenticator belongs to
+	UserID pgtype.UUID `json:"user_id"`
+	// AES-GCM encrypted TOTP secret
+	SecretCiphertext string `json:"secret_ciphertext"`
+	// Timestamp when enrolment was confirmed with a valid code, NULL while pending
+	ConfirmedAt pgtype.Timestamp `json:"confirmed_at"`
+	// Time step of the last accepted code, used to reject replayed codes
+	LastUsedStep int64 `json:"last_used_step"`
+	// Timestamp when enrolment was started
+	CreatedAt pgtype.Timestamp `json:"created_at"`
+	// Timestamp when the factor was last updated
+	UpdatedAt pgtype.Timestamp `json:"updated_at"`
+}
+
 // Stores user account information
 type User struct {
 	// Unique identifier for the user (UUID v4)
@@ -24,4 +176,16 @@ type User struct {
 	CreatedAt pgtype.Timestamp `json:"created_at"`
 	// Timestamp when user was last updated
 	UpdatedAt pgtype.Timestamp `json:"updated_at"`
+	// Access control role: admin, support or member
+	Role string `json:"role"`
+	// Timestamp when the email address was verified, NULL if unverified
+	EmailVerifiedAt pgtype.Timestamp `json:"email_verified_at"`
+	// Consecutive failed password attempts since the last success or lockout
+	FailedLoginAttempts int32 `json:"failed_login_attempts"`
+	// Consecutive lockouts, used to escalate the lockout duration
+	LockoutCount int32 `json:"lockout_count"`
+	// Timestamp until which password login is blocked, NULL if not locked
+	LockedUntil pgtype.Timestamp `json:"locked_until"`
+	// Incremented on every update, used for optimistic concurrency control
+	Version int32 `json:"version"`
 }
@@ -11,13 +11,43 @@ import (
 )
 
 type Querier interface {
+	CountAuditLogEntries(ctx context.Context, arg CountAuditLogEntriesParams) (int64, error)
 	CountUsers(ctx context.Context) (int64, error)
+	CreateAPIKey(ctx context.Context, arg CreateAPIKeyParams) (ApiKey, error)
+	CreateAuditLogEntry(ctx context.Context, arg CreateAuditLogEntryParams) error
+	CreateEmailVerificationToken(ctx context.Context, arg CreateEmailVerificationTokenParams) (EmailVerificationToken, error)
+	CreatePasswordResetToken(ctx context.Context, arg CreatePasswordResetTokenParams) (PasswordResetToken, error)
+	CreateRecoveryCode(ctx context.Context, arg CreateRecoveryCodeParams) error
+	CreateRefreshToken(ctx context.Context, arg CreateRefreshTokenParams) (RefreshToken, error)
 	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
+	DeleteRecoveryCodesForUser(ctx context.Context, userID pgtype.UUID) error
 	DeleteUser(ctx context.Context, arg DeleteUserParams) error
+	GetAPIKeyByHash(ctx context.Context, keyHash string) (ApiKey, error)
+	GetEmailVerificationTokenByHash(ctx context.Context, tokenHash string) (EmailVerificationToken, error)
+	GetPasswordResetTokenByHash(ctx context.Context, tokenHash string) (PasswordResetToken, error)
+	GetRefreshTokenByHash(ctx context.Context, tokenHash string) (RefreshToken, error)
+	GetTOTPFactorByUserID(ctx context.Context, userID pgtype.UUID) (UserTotpFactor, error)
 	GetUserByEmail(ctx context.Context, email string) (User, error)
+	GetUserByEmailIncludingInactive(ctx context.Context, email string) (User, error)
 	GetUserByID(ctx context.Context, id pgtype.UUID) (User, error)
+	GetUserByIDIncludingInactive(ctx context.Context, id pgtype.UUID) (User, error)
+	GetUserForUpdate(ctx context.Context, id pgtype.UUID) (User, error)
+	InvalidateEmailVerificationTokensForUser(ctx context.Context, arg InvalidateEmailVerificationTokensForUserParams) error
+	InvalidatePasswordResetTokensForUser(ctx context.Context, arg InvalidatePasswordResetTokensForUserParams) error
+	ListAPIKeysByUser(ctx context.Context, userID pgtype.UUID) ([]ApiKey, error)
+	ListAuditLogEntries(ctx context.Context, arg ListAuditLogEntriesParams) ([]AuditLog, error)
 	ListUsers(ctx context.Context, arg ListUsersParams) ([]User, error)
+	MarkEmailVerificationTokenUsed(ctx context.Context, arg MarkEmailVerificationTokenUsedParams) (int64, error)
+	MarkPasswordResetTokenUsed(ctx context.Context, arg MarkPasswordResetTokenUsedParams) (int64, error)
+	MarkRefreshTokenRotated(ctx context.Context, arg MarkRefreshTokenRotatedParams) (int64, error)
+	MarkTOTPStepUsed(ctx context.Context, arg MarkTOTPStepUsedParams) (int64, error)
+	RevokeAPIKey(ctx context.Context, arg RevokeAPIKeyParams) (int64, error)
+	RevokeRefreshTokenFamily(ctx context.Context, arg RevokeRefreshTokenFamilyParams) error
+	RevokeRefreshTokensForUser(ctx context.Context, arg RevokeRefreshTokensForUserParams) error
+	UpdateAPIKeyLastUsed(ctx context.Context, arg UpdateAPIKeyLastUsedParams) error
 	UpdateUser(ctx context.Context, arg UpdateUserParams) (User, error)
+	UpsertTOTPFactor(ctx context.Context, arg UpsertTOTPFactorParams) (UserTotpFactor, error)
+	UseRecoveryCode(ctx context.Context, arg UseRecoveryCodeParams) (int64, error)
 }
 
 var _ Querier = (*Queries)(nil)
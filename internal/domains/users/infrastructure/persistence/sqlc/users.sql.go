@@ -31,20 +31,30 @@ INSERT INTO users (
     password_hash,
     is_active,
     created_at,
-    updated_at
+    updated_at,
+    role,
+    email_verified_at,
+    failed_login_attempts,
+    lockout_count,
+    locked_until
 ) VALUES (
-    $1, $2, $3, $4, $5, $6, $7
-) RETURNING id, email, name, password_hash, is_active, created_at, updated_at
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12
+) RETURNING id, email, name, password_hash, is_active, created_at, updated_at, role, email_verified_at, failed_login_attempts, lockout_count, locked_until, version
 `
 
 type CreateUserParams struct {
-	ID           pgtype.UUID      `json:"id"`
-	Email        string           `json:"email"`
-	Name         string           `json:"name"`
-	PasswordHash string           `json:"password_hash"`
-	IsActive     bool             `json:"is_active"`
-	CreatedAt    pgtype.Timestamp `json:"created_at"`
-	UpdatedAt    pgtype.Timestamp `json:"updated_at"`
+	ID                  pgtype.UUID      `json:"id"`
+	Email               string           `json:"email"`
+	Name                string           `json:"name"`
+	PasswordHash        string           `json:"password_hash"`
+	IsActive            bool             `json:"is_active"`
+	CreatedAt           pgtype.Timestamp `json:"created_at"`
+	UpdatedAt           pgtype.Timestamp `json:"updated_at"`
+	Role                string           `json:"role"`
+	EmailVerifiedAt     pgtype.Timestamp `json:"email_verified_at"`
+	FailedLoginAttempts int32            `json:"failed_login_attempts"`
+	LockoutCount        int32            `json:"lockout_count"`
+	LockedUntil         pgtype.Timestamp `json:"locked_until"`
 }
 
 func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, error) {
@@ -56,6 +66,11 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		arg.IsActive,
 		arg.CreatedAt,
 		arg.UpdatedAt,
+		arg.Role,
+		arg.EmailVerifiedAt,
+		arg.FailedLoginAttempts,
+		arg.LockoutCount,
+		arg.LockedUntil,
 	)
 	var i User
 	err := row.Scan(
@@ -66,6 +81,12 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		&i.IsActive,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Role,
+		&i.EmailVerifiedAt,
+		&i.FailedLoginAttempts,
+		&i.LockoutCount,
+		&i.LockedUntil,
+		&i.Version,
 	)
 	return i, err
 }
@@ -74,7 +95,8 @@ const deleteUser = `-- name: DeleteUser :exec
 UPDATE users
 SET
     is_active = false,
-    updated_at = $2
+    updated_at = $2,
+    version = version + 1
 WHERE id = $1
 `
 
@@ -89,7 +111,7 @@ func (q *Queries) DeleteUser(ctx context.Context, arg DeleteUserParams) error {
 }
 
 const getUserByEmail = `-- name: GetUserByEmail :one
-SELECT id, email, name, password_hash, is_active, created_at, updated_at FROM users
+SELECT id, email, name, password_hash, is_active, created_at, updated_at, role, email_verified_at, failed_login_attempts, lockout_count, locked_until, version FROM users
 WHERE email = $1 AND is_active = true
 LIMIT 1
 `
@@ -105,12 +127,45 @@ func (q *Queries) GetUserByEmail(ctx context.Context, email string) (User, error
 		&i.IsActive,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Role,
+		&i.EmailVerifiedAt,
+		&i.FailedLoginAttempts,
+		&i.LockoutCount,
+		&i.LockedUntil,
+		&i.Version,
+	)
+	return i, err
+}
+
+const getUserByEmailIncludingInactive = `-- name: GetUserByEmailIncludingInactive :one
+SELECT id, email, name, password_hash, is_active, created_at, updated_at, role, email_verified_at, failed_login_attempts, lockout_count, locked_until, version FROM users
+WHERE email = $1
+LIMIT 1
+`
+
+func (q *Queries) GetUserByEmailIncludingInactive(ctx context.Context, email string) (User, error) {
+	row := q.db.QueryRow(ctx, getUserByEmailIncludingInactive, email)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.Name,
+		&i.PasswordHash,
+		&i.IsActive,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Role,
+		&i.EmailVerifiedAt,
+		&i.FailedLoginAttempts,
+		&i.LockoutCount,
+		&i.LockedUntil,
+		&i.Version,
 	)
 	return i, err
 }
 
 const getUserByID = `-- name: GetUserByID :one
-SELECT id, email, name, password_hash, is_active, created_at, updated_at FROM users
+SELECT id, email, name, password_hash, is_active, created_at, updated_at, role, email_verified_at, failed_login_attempts, lockout_count, locked_until, version FROM users
 WHERE id = $1 AND is_active = true
 LIMIT 1
 `
@@ -126,12 +181,73 @@ func (q *Queries) GetUserByID(ctx context.Context, id pgtype.UUID) (User, error)
 		&i.IsActive,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Role,
+		&i.EmailVerifiedAt,
+		&i.FailedLoginAttempts,
+		&i.LockoutCount,
+		&i.LockedUntil,
+		&i.Version,
+	)
+	return i, err
+}
+
+const getUserByIDIncludingInactive = `-- name: GetUserByIDIncludingInactive :one
+SELECT id, email, name, password_hash, is_active, created_at, updated_at, role, email_verified_at, failed_login_attempts, lockout_count, locked_until, version FROM users
+WHERE id = $1
+LIMIT 1
+`
+
+func (q *Queries) GetUserByIDIncludingInactive(ctx context.Context, id pgtype.UUID) (User, error) {
+	row := q.db.QueryRow(ctx, getUserByIDIncludingInactive, id)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.Name,
+		&i.PasswordHash,
+		&i.IsActive,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Role,
+		&i.EmailVerifiedAt,
+		&i.FailedLoginAttempts,
+		&i.LockoutCount,
+		&i.LockedUntil,
+		&i.Version,
+	)
+	return i, err
+}
+
+const getUserForUpdate = `-- name: GetUserForUpdate :one
+SELECT id, email, name, password_hash, is_active, created_at, updated_at, role, email_verified_at, failed_login_attempts, lockout_count, locked_until, version FROM users
+WHERE id = $1
+LIMIT 1
+FOR UPDATE
+`
+
+func (q *Queries) GetUserForUpdate(ctx context.Context, id pgtype.UUID) (User, error) {
+	row := q.db.QueryRow(ctx, getUserForUpdate, id)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.Name,
+		&i.PasswordHash,
+		&i.IsActive,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Role,
+		&i.EmailVerifiedAt,
+		&i.FailedLoginAttempts,
+		&i.LockoutCount,
+		&i.LockedUntil,
+		&i.Version,
 	)
 	return i, err
 }
 
 const listUsers = `-- name: ListUsers :many
-SELECT id, email, name, password_hash, is_active, created_at, updated_at FROM users
+SELECT id, email, name, password_hash, is_active, created_at, updated_at, role, email_verified_at, failed_login_attempts, lockout_count, locked_until, version FROM users
 WHERE is_active = true
 ORDER BY created_at DESC
 LIMIT $1 OFFSET $2
@@ -159,6 +275,12 @@ func (q *Queries) ListUsers(ctx context.Context, arg ListUsersParams) ([]User, e
 			&i.IsActive,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.Role,
+			&i.EmailVerifiedAt,
+			&i.FailedLoginAttempts,
+			&i.LockoutCount,
+			&i.LockedUntil,
+			&i.Version,
 		); err != nil {
 			return nil, err
 		}
@@ -177,18 +299,30 @@ SET
     name = $3,
     password_hash = $4,
     is_active = $5,
-    updated_at = $6
-WHERE id = $1
-RETURNING id, email, name, password_hash, is_active, created_at, updated_at
+    updated_at = $6,
+    role = $7,
+    email_verified_at = $8,
+    failed_login_attempts = $9,
+    lockout_count = $10,
+    locked_until = $11,
+    version = version + 1
+WHERE id = $1 AND version = $12
+RETURNING id, email, name, password_hash, is_active, created_at, updated_at, role, email_verified_at, failed_login_attempts, lockout_count, locked_until, version
 `
 
 type UpdateUserParams struct {
-	ID           pgtype.UUID      `json:"id"`
-	Email        string           `json:"email"`
-	Name         string           `json:"name"`
-	PasswordHash string           `json:"password_hash"`
-	IsActive     bool             `json:"is_active"`
-	UpdatedAt    pgtype.Timestamp `json:"updated_at"`
+	ID                  pgtype.UUID      `json:"id"`
+	Email               string           `json:"email"`
+	Name                string           `json:"name"`
+	PasswordHash        string           `json:"password_hash"`
+	IsActive            bool             `json:"is_active"`
+	UpdatedAt           pgtype.Timestamp `json:"updated_at"`
+	Role                string           `json:"role"`
+	EmailVerifiedAt     pgtype.Timestamp `json:"email_verified_at"`
+	FailedLoginAttempts int32            `json:"failed_login_attempts"`
+	LockoutCount        int32            `json:"lockout_count"`
+	LockedUntil         pgtype.Timestamp `json:"locked_until"`
+	Version             int32            `json:"version"`
 }
 
 func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (User, error) {
@@ -199,6 +333,12 @@ func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (User, e
 		arg.PasswordHash,
 		arg.IsActive,
 		arg.UpdatedAt,
+		arg.Role,
+		arg.EmailVerifiedAt,
+		arg.FailedLoginAttempts,
+		arg.LockoutCount,
+		arg.LockedUntil,
+		arg.Version,
 	)
 	var i User
 	err := row.Scan(
@@ -209,6 +349,12 @@ func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (User, e
 		&i.IsActive,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Role,
+		&i.EmailVerifiedAt,
+		&i.FailedLoginAttempts,
+		&i.LockoutCount,
+		&i.LockedUntil,
+		&i.Version,
 	)
 	return i, err
 }
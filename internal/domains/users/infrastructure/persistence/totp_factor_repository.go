@@ -0,0 +1,96 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/domains/users/domain"
+	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/domains/users/infrastructure/persistence/sqlc"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+/*
+TOTPFactorRepository implements the domain.TOTPFactorRepository interface using SQLC.
+*/
+type TOTPFactorRepository struct {
+	pool    *pgxpool.Pool
+	queries *sqlc.Queries
+}
+
+/*
+NewTOTPFactorRepository creates a new TOTPFactorRepository instance.
+Requires a pgxpool.Pool for database connectivity.
+*/
+func NewTOTPFactorRepository(pool *pgxpool.Pool) *TOTPFactorRepository {
+	return &TOTPFactorRepository{
+		pool:    pool,
+		queries: sqlc.New(pool),
+	}
+}
+
+/*
+Save inserts the factor or replaces the user's existing factor.
+*/
+func (r *TOTPFactorRepository) Save(ctx context.Context, factor *domain.TOTPFactor) error {
+	params := sqlc.UpsertTOTPFactorParams{
+		UserID:           uuidToPgtype(factor.UserID),
+		SecretCiphertext: factor.EncryptedSecret,
+		ConfirmedAt:      timePtrToPgtype(factor.ConfirmedAt),
+		LastUsedStep:     factor.LastUsedStep,
+		CreatedAt:        timeToPgtype(factor.CreatedAt),
+		UpdatedAt:        timeToPgtype(factor.UpdatedAt),
+	}
+
+	if _, err := queriesFor(ctx, r.queries).UpsertTOTPFactor(ctx, params); err != nil {
+		return fmt.Errorf("failed to save totp factor: %w", err)
+	}
+
+	return nil
+}
+
+/*
+FindByUserID retrieves the factor of the given user.
+Returns ErrMFANotEnrolled if the user has no factor.
+*/
+func (r *TOTPFactorRepository) FindByUserID(ctx context.Context, userID uuid.UUID) (*domain.TOTPFactor, error) {
+	sqlcFactor, err := queriesFor(ctx, r.queries).GetTOTPFactorByUserID(ctx, uuidToPgtype(userID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrMFANotEnrolled
+		}
+		return nil, fmt.Errorf("failed to get totp factor: %w", err)
+	}
+
+	return &domain.TOTPFactor{
+		UserID:          pgtypeToUUID(sqlcFactor.UserID),
+		EncryptedSecret: sqlcFactor.SecretCiphertext,
+		ConfirmedAt:     pgtypeToTimePtr(sqlcFactor.ConfirmedAt),
+		LastUsedStep:    sqlcFactor.LastUsedStep,
+		CreatedAt:       pgtypeToTime(sqlcFactor.CreatedAt),
+		UpdatedAt:       pgtypeToTime(sqlcFactor.UpdatedAt),
+	}, nil
+}
+
+/*
+MarkStepUsed records the time step of an accepted code.
+Returns ErrInvalidMFACode if the step is not newer than the last accepted one.
+*/
+func (r *TOTPFactorRepository) MarkStepUsed(ctx context.Context, userID uuid.UUID, step int64, at time.Time) error {
+	rows, err := queriesFor(ctx, r.queries).MarkTOTPStepUsed(ctx, sqlc.MarkTOTPStepUsedParams{
+		UserID:       uuidToPgtype(userID),
+		LastUsedStep: step,
+		UpdatedAt:    timeToPgtype(at),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to mark totp step used: %w", err)
+	}
+	if rows == 0 {
+		return domain.ErrInvalidMFACode
+	}
+
+	return nil
+}
@@ -0,0 +1,73 @@
+package auth
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+// ErrAPIKeyRejected indicates that an API key is unknown, revoked or expired
+var ErrAPIKeyRejected = errors.New("api key rejected")
+
+/*
+Principal represents the authenticated caller of a request.
+It is placed into the request context by the authentication middleware and can be
+read by handlers and application services via PrincipalFromContext.
+*/
+type Principal struct {
+	UserID uuid.UUID
+	Email  string
+	Role   string
+	// Scopes limits an API key to these permissions; nil for access tokens
+	Scopes []string
+	// APIKeyID identifies the API key the request was authenticated with, if any
+	APIKeyID uuid.UUID
+}
+
+/*
+ViaAPIKey reports whether the principal was authenticated with an API key rather
+than an access token from an interactive login.
+*/
+func (p *Principal) ViaAPIKey() bool {
+	return p.APIKeyID != uuid.Nil
+}
+
+/*
+TokenVerifier validates a bearer token and returns the principal it identifies.
+Implemented by TokenManager; declared as an interface so middleware can be
+tested or backed by a different token format.
+*/
+type TokenVerifier interface {
+	VerifyAccessToken(token string) (*Principal, error)
+}
+
+/*
+APIKeyVerifier validates an API key and returns the principal it acts as.
+Implemented by the users domain, which owns API keys.
+A key that does not authenticate (unknown, revoked, expired) is reported with an
+error matching ErrAPIKeyRejected; any other error means the key could not be
+checked.
+*/
+type APIKeyVerifier interface {
+	VerifyAPIKey(ctx context.Context, key string) (*Principal, error)
+}
+
+// principalKey is the unexported context key for the authenticated principal
+type principalKey struct{}
+
+/*
+WithPrincipal returns a copy of ctx that carries the given principal.
+*/
+func WithPrincipal(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+/*
+PrincipalFromContext returns the authenticated principal stored in ctx.
+The boolean is false if the request was not authenticated.
+*/
+func PrincipalFromContext(ctx context.Context) (*Principal, bool) {
+	principal, ok := ctx.Value(principalKey{}).(*Principal)
+	return principal, ok && principal != nil
+}
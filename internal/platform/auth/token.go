@@ -0,0 +1,237 @@
+package auth
+
+import (
+	"crypto"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/platform/config"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// ErrInvalidToken indicates that a token is malformed, expired or has a bad signature
+var ErrInvalidToken = errors.New("invalid token")
+
+// purposeMFAChallenge marks tokens that only prove the password step of an MFA login
+const purposeMFAChallenge = "mfa_challenge"
+
+// Claims represents the JWT claims carried by an access token
+type Claims struct {
+	Email string `json:"email,omitempty"`
+	Role  string `json:"role,omitempty"`
+	// Purpose is empty for access tokens and set for special-purpose tokens,
+	// which are never accepted as access tokens
+	Purpose string `json:"purpose,omitempty"`
+	jwt.RegisteredClaims
+}
+
+/*
+TokenManager issues and verifies signed JWT access tokens.
+The signing algorithm (HS256, RS256 or EdDSA), token lifetime, issuer and audience
+are taken from config.AuthConfig so they can be changed without code changes.
+*/
+type TokenManager struct {
+	method       jwt.SigningMethod
+	signKey      interface{}
+	verifyKey    interface{}
+	ttl          time.Duration
+	challengeTTL time.Duration
+	issuer       string
+	audience     string
+}
+
+/*
+NewTokenManager creates a TokenManager from the authentication configuration.
+For HS256 the shared secret is used for both signing and verification.
+For RS256 and EdDSA the private key is loaded from a PEM file; the public key is
+loaded from its own PEM file when configured, or derived from the private key otherwise.
+Returns an error if the algorithm is unsupported or a key cannot be loaded.
+*/
+func NewTokenManager(cfg *config.Config) (*TokenManager, error) {
+	m := &TokenManager{
+		ttl:          cfg.Auth.AccessTokenTTL,
+		challengeTTL: cfg.MFA.ChallengeTTL,
+		issuer:       cfg.Auth.Issuer,
+		audience:     cfg.Auth.Audience,
+	}
+
+	switch cfg.Auth.JWTAlgorithm {
+	case "HS256":
+		m.method = jwt.SigningMethodHS256
+		m.signKey = []byte(cfg.Auth.JWTSecret)
+		m.verifyKey = []byte(cfg.Auth.JWTSecret)
+
+	case "RS256":
+		m.method = jwt.SigningMethodRS256
+		privateKey, err := loadPEM(cfg.Auth.JWTPrivateKeyFile, jwt.ParseRSAPrivateKeyFromPEM)
+		if err != nil {
+			return nil, err
+		}
+		m.signKey = privateKey
+		m.verifyKey = &privateKey.PublicKey
+		if cfg.Auth.JWTPublicKeyFile != "" {
+			publicKey, err := loadPEM(cfg.Auth.JWTPublicKeyFile, jwt.ParseRSAPublicKeyFromPEM)
+			if err != nil {
+				return nil, err
+			}
+			m.verifyKey = publicKey
+		}
+
+	case "EdDSA":
+		m.method = jwt.SigningMethodEdDSA
+		privateKey, err := loadPEM(cfg.Auth.JWTPrivateKeyFile, jwt.ParseEdPrivateKeyFromPEM)
+		if err != nil {
+			return nil, err
+		}
+		m.signKey = privateKey
+		m.verifyKey = privateKey.(crypto.Signer).Public()
+		if cfg.Auth.JWTPublicKeyFile != "" {
+			publicKey, err := loadPEM(cfg.Auth.JWTPublicKeyFile, jwt.ParseEdPublicKeyFromPEM)
+			if err != nil {
+				return nil, err
+			}
+			m.verifyKey = publicKey
+		}
+
+	default:
+		return nil, fmt.Errorf("unsupported jwt algorithm: %s", cfg.Auth.JWTAlgorithm)
+	}
+
+	return m, nil
+}
+
+/*
+IssueAccessToken creates a signed access token for the given user.
+The token carries the user ID as subject, the email and role as custom claims, and
+the configured issuer, audience and expiry. Role changes therefore take effect when
+the user's next access token is issued. A random token ID (jti) is included so
+individual tokens can be told apart in logs.
+Returns the encoded token and its expiry time.
+*/
+func (m *TokenManager) IssueAccessToken(userID uuid.UUID, email, role string) (string, time.Time, error) {
+	token, expiresAt, err := m.sign(Claims{Email: email, Role: role}, userID, m.ttl)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to sign access token: %w", err)
+	}
+
+	return token, expiresAt, nil
+}
+
+/*
+VerifyAccessToken parses and validates an access token.
+It checks the signature with the configured algorithm only (rejecting "none" and
+algorithm substitution), as well as expiry, not-before, issuer and audience.
+Special-purpose tokens such as MFA challenges are rejected.
+Returns the Principal described by the token, or ErrInvalidToken if validation fails.
+*/
+func (m *TokenManager) VerifyAccessToken(token string) (*Principal, error) {
+	claims, userID, err := m.parse(token, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return &Principal{
+		UserID: userID,
+		Email:  claims.Email,
+		Role:   claims.Role,
+	}, nil
+}
+
+/*
+IssueMFAChallenge creates a short-lived token proving that the user passed the
+password step of a two-factor login. It is signed like an access token but
+carries a purpose claim, so it is rejected wherever an access token is expected.
+Returns the encoded token and its expiry time.
+*/
+func (m *TokenManager) IssueMFAChallenge(userID uuid.UUID) (string, time.Time, error) {
+	token, expiresAt, err := m.sign(Claims{Purpose: purposeMFAChallenge}, userID, m.challengeTTL)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to sign mfa challenge: %w", err)
+	}
+
+	return token, expiresAt, nil
+}
+
+/*
+VerifyMFAChallenge validates a token issued by IssueMFAChallenge.
+Returns the ID of the user the challenge was issued to, or ErrInvalidToken.
+*/
+func (m *TokenManager) VerifyMFAChallenge(token string) (uuid.UUID, error) {
+	_, userID, err := m.parse(token, purposeMFAChallenge)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	return userID, nil
+}
+
+// sign fills in the registered claims and signs a token for the given subject
+func (m *TokenManager) sign(claims Claims, userID uuid.UUID, ttl time.Duration) (string, time.Time, error) {
+	now := time.Now()
+	expiresAt := now.Add(ttl)
+
+	claims.RegisteredClaims = jwt.RegisteredClaims{
+		ID:        uuid.NewString(),
+		Subject:   userID.String(),
+		Issuer:    m.issuer,
+		Audience:  jwt.ClaimStrings{m.audience},
+		IssuedAt:  jwt.NewNumericDate(now),
+		NotBefore: jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(expiresAt),
+	}
+
+	token, err := jwt.NewWithClaims(m.method, claims).SignedString(m.signKey)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	return token, expiresAt, nil
+}
+
+// parse validates a token and checks that it was issued for the given purpose
+func (m *TokenManager) parse(token, purpose string) (*Claims, uuid.UUID, error) {
+	claims := &Claims{}
+
+	_, err := jwt.ParseWithClaims(token, claims, func(*jwt.Token) (interface{}, error) {
+		return m.verifyKey, nil
+	},
+		jwt.WithValidMethods([]string{m.method.Alg()}),
+		jwt.WithIssuer(m.issuer),
+		jwt.WithAudience(m.audience),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return nil, uuid.Nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+
+	if claims.Purpose != purpose {
+		return nil, uuid.Nil, fmt.Errorf("%w: unexpected token purpose", ErrInvalidToken)
+	}
+
+	userID, err := uuid.Parse(claims.Subject)
+	if err != nil {
+		return nil, uuid.Nil, fmt.Errorf("%w: invalid subject", ErrInvalidToken)
+	}
+
+	return claims, userID, nil
+}
+
+// loadPEM reads a PEM file and parses it with the given key parser
+func loadPEM[K any](path string, parse func([]byte) (K, error)) (K, error) {
+	var zero K
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return zero, fmt.Errorf("failed to read key file %s: %w", path, err)
+	}
+
+	key, err := parse(data)
+	if err != nil {
+		return zero, fmt.Errorf("failed to parse key file %s: %w", path, err)
+	}
+
+	return key, nil
+}
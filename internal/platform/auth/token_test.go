@@ -0,0 +1,376 @@
+package auth
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/platform/config"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// testKeys holds PEM files for the asymmetric algorithms
+type testKeys struct {
+	rsaPrivate, rsaPublic string
+	edPrivate, edPublic   string
+	rsaPublicPEM          []byte
+}
+
+// writeTestKeys generates RSA and Ed25519 key pairs and writes them as PEM files
+func writeTestKeys(t *testing.T) testKeys {
+	t.Helper()
+	dir := t.TempDir()
+
+	write := func(name, blockType string, der []byte) (string, []byte) {
+		data := pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der})
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, data, 0o600); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+		return path, data
+	}
+	marshal := func(der []byte, err error) []byte {
+		if err != nil {
+			t.Fatalf("failed to marshal key: %v", err)
+		}
+		return der
+	}
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("rsa.GenerateKey() error = %v", err)
+	}
+	edPublic, edPrivate, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("ed25519.GenerateKey() error = %v", err)
+	}
+
+	var keys testKeys
+	keys.rsaPrivate, _ = write("rsa.pem", "PRIVATE KEY", marshal(x509.MarshalPKCS8PrivateKey(rsaKey)))
+	keys.rsaPublic, keys.rsaPublicPEM = write("rsa.pub.pem", "PUBLIC KEY", marshal(x509.MarshalPKIXPublicKey(&rsaKey.PublicKey)))
+	keys.edPrivate, _ = write("ed25519.pem", "PRIVATE KEY", marshal(x509.MarshalPKCS8PrivateKey(edPrivate)))
+	keys.edPublic, _ = write("ed25519.pub.pem", "PUBLIC KEY", marshal(x509.MarshalPKIXPublicKey(edPublic)))
+	return keys
+}
+
+// testConfig returns an HS256 configuration; tests override what they need
+func testConfig() *config.Config {
+	return &config.Config{
+		Auth: config.AuthConfig{
+			JWTAlgorithm:   "HS256",
+			JWTSecret:      "test-secret-that-is-long-enough-for-hs256",
+			AccessTokenTTL: 15 * time.Minute,
+			Issuer:         "test-issuer",
+			Audience:       "test-audience",
+		},
+		MFA: config.MFAConfig{ChallengeTTL: 5 * time.Minute},
+	}
+}
+
+func newTestManager(t *testing.T, configure func(cfg *config.Config)) *TokenManager {
+	t.Helper()
+
+	cfg := testConfig()
+	if configure != nil {
+		configure(cfg)
+	}
+	m, err := NewTokenManager(cfg)
+	if err != nil {
+		t.Fatalf("NewTokenManager() error = %v", err)
+	}
+	return m
+}
+
+func TestTokenManagerRoundTrip(t *testing.T) {
+	keys := writeTestKeys(t)
+
+	tests := []struct {
+		name      string
+		configure func(cfg *config.Config)
+	}{
+		{name: "HS256"},
+		{
+			name: "RS256",
+			configure: func(cfg *config.Config) {
+				cfg.Auth.JWTAlgorithm = "RS256"
+				cfg.Auth.JWTPrivateKeyFile = keys.rsaPrivate
+			},
+		},
+		{
+			name: "RS256 with public key file",
+			configure: func(cfg *config.Config) {
+				cfg.Auth.JWTAlgorithm = "RS256"
+				cfg.Auth.JWTPrivateKeyFile = keys.rsaPrivate
+				cfg.Auth.JWTPublicKeyFile = keys.rsaPublic
+			},
+		},
+		{
+			name: "EdDSA",
+			configure: func(cfg *config.Config) {
+				cfg.Auth.JWTAlgorithm = "EdDSA"
+				cfg.Auth.JWTPrivateKeyFile = keys.edPrivate
+			},
+		},
+		{
+			name: "EdDSA with public key file",
+			configure: func(cfg *config.Config) {
+				cfg.Auth.JWTAlgorithm = "EdDSA"
+				cfg.Auth.JWTPrivateKeyFile = keys.edPrivate
+				cfg.Auth.JWTPublicKeyFile = keys.edPublic
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newTestManager(t, tt.configure)
+			userID := uuid.New()
+
+			token, expiresAt, err := m.IssueAccessToken(userID, "alice@example.com", "admin")
+			if err != nil {
+				t.Fatalf("IssueAccessToken() error = %v", err)
+			}
+			if ttl := time.Until(expiresAt); ttl <= 14*time.Minute || ttl > 15*time.Minute {
+				t.Errorf("IssueAccessToken() expires in %s, want 15m", ttl)
+			}
+
+			principal, err := m.VerifyAccessToken(token)
+			if err != nil {
+				t.Fatalf("VerifyAccessToken() error = %v", err)
+			}
+			if principal.UserID != userID || principal.Email != "alice@example.com" || principal.Role != "admin" {
+				t.Errorf("VerifyAccessToken() = %+v, want user %s, alice@example.com, admin", principal, userID)
+			}
+
+			challenge, _, err := m.IssueMFAChallenge(userID)
+			if err != nil {
+				t.Fatalf("IssueMFAChallenge() error = %v", err)
+			}
+			got, err := m.VerifyMFAChallenge(challenge)
+			if err != nil {
+				t.Fatalf("VerifyMFAChallenge() error = %v", err)
+			}
+			if got != userID {
+				t.Errorf("VerifyMFAChallenge() = %s, want %s", got, userID)
+			}
+		})
+	}
+}
+
+func TestNewTokenManagerErrors(t *testing.T) {
+	keys := writeTestKeys(t)
+
+	tests := []struct {
+		name      string
+		configure func(cfg *config.Config)
+	}{
+		{name: "unsupported algorithm", configure: func(cfg *config.Config) { cfg.Auth.JWTAlgorithm = "HS512" }},
+		{
+			name: "missing key file",
+			configure: func(cfg *config.Config) {
+				cfg.Auth.JWTAlgorithm = "RS256"
+				cfg.Auth.JWTPrivateKeyFile = filepath.Join(t.TempDir(), "missing.pem")
+			},
+		},
+		{
+			name: "key of another algorithm",
+			configure: func(cfg *config.Config) {
+				cfg.Auth.JWTAlgorithm = "RS256"
+				cfg.Auth.JWTPrivateKeyFile = keys.edPrivate
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testConfig()
+			tt.configure(cfg)
+			if _, err := NewTokenManager(cfg); err == nil {
+				t.Error("NewTokenManager() error = nil, want an error")
+			}
+		})
+	}
+}
+
+func TestVerifyAccessTokenRejects(t *testing.T) {
+	keys := writeTestKeys(t)
+	m := newTestManager(t, nil)
+	rsaManager := newTestManager(t, func(cfg *config.Config) {
+		cfg.Auth.JWTAlgorithm = "RS256"
+		cfg.Auth.JWTPrivateKeyFile = keys.rsaPrivate
+	})
+	userID := uuid.New()
+	secret := []byte(testConfig().Auth.JWTSecret)
+
+	// claims returns valid access token claims with the given changes applied
+	claims := func(change func(c *Claims)) *Claims {
+		now := time.Now()
+		c := &Claims{
+			Email: "alice@example.com",
+			Role:  "member",
+			RegisteredClaims: jwt.RegisteredClaims{
+				Subject:   userID.String(),
+				Issuer:    "test-issuer",
+				Audience:  jwt.ClaimStrings{"test-audience"},
+				IssuedAt:  jwt.NewNumericDate(now),
+				ExpiresAt: jwt.NewNumericDate(now.Add(time.Minute)),
+			},
+		}
+		if change != nil {
+			change(c)
+		}
+		return c
+	}
+	sign := func(t *testing.T, method jwt.SigningMethod, key interface{}, c *Claims) string {
+		t.Helper()
+		token, err := jwt.NewWithClaims(method, c).SignedString(key)
+		if err != nil {
+			t.Fatalf("SignedString() error = %v", err)
+		}
+		return token
+	}
+
+	// The unmodified claims are accepted, so each case below fails for its own reason
+	if _, err := m.VerifyAccessToken(sign(t, jwt.SigningMethodHS256, secret, claims(nil))); err != nil {
+		t.Fatalf("VerifyAccessToken() error = %v, want nil", err)
+	}
+
+	tests := []struct {
+		name    string
+		manager *TokenManager
+		token   func(t *testing.T) string
+	}{
+		{
+			name:    "alg none",
+			manager: m,
+			token: func(t *testing.T) string {
+				return sign(t, jwt.SigningMethodNone, jwt.UnsafeAllowNoneSignatureType, claims(nil))
+			},
+		},
+		{
+			name:    "HS256 signed with the RSA public key",
+			manager: rsaManager,
+			token:   func(t *testing.T) string { return sign(t, jwt.SigningMethodHS256, keys.rsaPublicPEM, claims(nil)) },
+		},
+		{
+			name:    "other algorithm with the same secret",
+			manager: m,
+			token:   func(t *testing.T) string { return sign(t, jwt.SigningMethodHS512, secret, claims(nil)) },
+		},
+		{
+			name:    "wrong secret",
+			manager: m,
+			token: func(t *testing.T) string {
+				return sign(t, jwt.SigningMethodHS256, []byte("another-secret"), claims(nil))
+			},
+		},
+		{
+			name:    "wrong issuer",
+			manager: m,
+			token: func(t *testing.T) string {
+				return sign(t, jwt.SigningMethodHS256, secret, claims(func(c *Claims) { c.Issuer = "someone-else" }))
+			},
+		},
+		{
+			name:    "wrong audience",
+			manager: m,
+			token: func(t *testing.T) string {
+				return sign(t, jwt.SigningMethodHS256, secret, claims(func(c *Claims) { c.Audience = jwt.ClaimStrings{"another-api"} }))
+			},
+		},
+		{
+			name:    "expired",
+			manager: m,
+			token: func(t *testing.T) string {
+				return sign(t, jwt.SigningMethodHS256, secret, claims(func(c *Claims) {
+					c.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-time.Minute))
+				}))
+			},
+		},
+		{
+			name:    "missing exp",
+			manager: m,
+			token: func(t *testing.T) string {
+				return sign(t, jwt.SigningMethodHS256, secret, claims(func(c *Claims) { c.ExpiresAt = nil }))
+			},
+		},
+		{
+			name:    "not yet valid",
+			manager: m,
+			token: func(t *testing.T) string {
+				return sign(t, jwt.SigningMethodHS256, secret, claims(func(c *Claims) {
+					c.NotBefore = jwt.NewNumericDate(time.Now().Add(time.Minute))
+				}))
+			},
+		},
+		{
+			name:    "subject is not a user ID",
+			manager: m,
+			token: func(t *testing.T) string {
+				return sign(t, jwt.SigningMethodHS256, secret, claims(func(c *Claims) { c.Subject = "alice" }))
+			},
+		},
+		{
+			name:    "MFA challenge",
+			manager: m,
+			token: func(t *testing.T) string {
+				token, _, err := m.IssueMFAChallenge(userID)
+				if err != nil {
+					t.Fatalf("IssueMFAChallenge() error = %v", err)
+				}
+				return token
+			},
+		},
+		{
+			name:    "malformed",
+			manager: m,
+			token:   func(t *testing.T) string { return "not.a.jwt" },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			principal, err := tt.manager.VerifyAccessToken(tt.token(t))
+			if !errors.Is(err, ErrInvalidToken) {
+				t.Errorf("VerifyAccessToken() error = %v, want %v", err, ErrInvalidToken)
+			}
+			if principal != nil {
+				t.Errorf("VerifyAccessToken() = %+v, want nil", principal)
+			}
+		})
+	}
+}
+
+func TestVerifyMFAChallengeRejectsAccessToken(t *testing.T) {
+	m := newTestManager(t, nil)
+
+	token, _, err := m.IssueAccessToken(uuid.New(), "alice@example.com", "member")
+	if err != nil {
+		t.Fatalf("IssueAccessToken() error = %v", err)
+	}
+
+	if _, err := m.VerifyMFAChallenge(token); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("VerifyMFAChallenge() error = %v, want %v", err, ErrInvalidToken)
+	}
+}
+
+func TestVerifyMFAChallengeRejectsExpiredChallenge(t *testing.T) {
+	m := newTestManager(t, func(cfg *config.Config) { cfg.MFA.ChallengeTTL = -time.Second })
+
+	token, _, err := m.IssueMFAChallenge(uuid.New())
+	if err != nil {
+		t.Fatalf("IssueMFAChallenge() error = %v", err)
+	}
+
+	if _, err := m.VerifyMFAChallenge(token); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("VerifyMFAChallenge() error = %v, want %v", err, ErrInvalidToken)
+	}
+}
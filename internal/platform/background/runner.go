@@ -0,0 +1,75 @@
+package background
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/platform/logger"
+)
+
+/*
+Runner runs work in the background, detached from the request that started it.
+Nobody waits for the result, so errors and panics are logged instead.
+Wait lets a graceful shutdown finish pending work before shared resources such
+as the database pool are closed.
+It is safe for concurrent use.
+*/
+type Runner struct {
+	wg     sync.WaitGroup
+	logger *logger.Logger
+}
+
+/*
+NewRunner creates a Runner.
+Requires a Logger to report failing work.
+*/
+func NewRunner(log *logger.Logger) *Runner {
+	return &Runner{logger: log}
+}
+
+/*
+Go runs fn in a new goroutine and returns immediately.
+fn receives ctx without its cancellation, so the work is not aborted when the
+request that started it completes. The name identifies the work in the log.
+*/
+func (r *Runner) Go(ctx context.Context, name string, fn func(ctx context.Context) error) {
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+
+		if err := run(context.WithoutCancel(ctx), fn); err != nil {
+			r.logger.Error("Background task failed", "task", name, "error", err.Error())
+		}
+	}()
+}
+
+/*
+Wait blocks until all work started with Go has finished, or ctx is done.
+Call it during shutdown once no new work can be started.
+*/
+func (r *Runner) Wait(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		r.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// run calls fn, turning a panic into an error
+func run(ctx context.Context, fn func(ctx context.Context) error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+
+	return fn(ctx)
+}
@@ -0,0 +1,93 @@
+package background
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/platform/logger"
+)
+
+func TestRunnerWaitsForWork(t *testing.T) {
+	runner := NewRunner(logger.New("fatal"))
+
+	var finished atomic.Int32
+	for i := 0; i < 3; i++ {
+		runner.Go(context.Background(), "work", func(ctx context.Context) error {
+			time.Sleep(10 * time.Millisecond)
+			finished.Add(1)
+			return nil
+		})
+	}
+
+	if err := runner.Wait(context.Background()); err != nil {
+		t.Fatalf("Wait() = %v, want nil", err)
+	}
+	if got := finished.Load(); got != 3 {
+		t.Errorf("finished = %d, want 3", got)
+	}
+}
+
+func TestRunnerDetachesFromCancellation(t *testing.T) {
+	runner := NewRunner(logger.New("fatal"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var workErr error
+	runner.Go(ctx, "work", func(ctx context.Context) error {
+		workErr = ctx.Err()
+		return nil
+	})
+
+	if err := runner.Wait(context.Background()); err != nil {
+		t.Fatalf("Wait() = %v, want nil", err)
+	}
+	if workErr != nil {
+		t.Errorf("ctx.Err() = %v, want nil", workErr)
+	}
+}
+
+func TestRunnerSurvivesFailures(t *testing.T) {
+	runner := NewRunner(logger.New("fatal"))
+
+	runner.Go(context.Background(), "failing", func(ctx context.Context) error {
+		return errors.New("boom")
+	})
+	runner.Go(context.Background(), "panicking", func(ctx context.Context) error {
+		panic("boom")
+	})
+
+	if err := runner.Wait(context.Background()); err != nil {
+		t.Fatalf("Wait() = %v, want nil", err)
+	}
+}
+
+func TestRunnerWaitHonoursDeadline(t *testing.T) {
+	runner := NewRunner(logger.New("fatal"))
+
+	release := make(chan struct{})
+	defer close(release)
+	runner.Go(context.Background(), "blocked", func(ctx context.Context) error {
+		<-release
+		return nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if err := runner.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Wait() = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestRunRecoversPanic(t *testing.T) {
+	err := run(context.Background(), func(ctx context.Context) error {
+		panic("boom")
+	})
+	if err == nil || err.Error() != "panic: boom" {
+		t.Errorf("run() = %v, want panic: boom", err)
+	}
+}
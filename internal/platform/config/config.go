@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/joho/godotenv"
@@ -14,12 +15,18 @@ type Config struct {
 	App      AppConfig
 	Database DatabaseConfig
 	Logger   LoggerConfig
+	Auth     AuthConfig
+	Mail     MailConfig
+	MFA      MFAConfig
+	Password PasswordConfig
+	Outbox   OutboxConfig
 }
 
 // AppConfig holds application-specific configuration
 type AppConfig struct {
-	Environment string
-	Port        string
+	Environment        string
+	Port               string
+	ProblemTypeBaseURI string // Prefix of the type URI in problem+json error responses; empty uses about:blank
 }
 
 // DatabaseConfig holds database connection configuration
@@ -34,6 +41,13 @@ type DatabaseConfig struct {
 	MinConns        int32
 	MaxConnLifetime time.Duration
 	MaxConnIdleTime time.Duration
+
+	TxIsolation      string        // Isolation level of use case transactions: read committed, repeatable read or serializable
+	TxMaxRetries     int32         // Times a transaction is retried after a serialization failure or deadlock
+	TxRetryBaseDelay time.Duration // Upper bound of the jittered delay before the first retry, doubled for each further retry
+	TxRetryMaxDelay  time.Duration // Upper bound for the retry delay
+
+	AutoMigrate bool // Apply pending migrations on startup
 }
 
 // LoggerConfig holds logger configuration
@@ -41,6 +55,82 @@ type LoggerConfig struct {
 	Level string
 }
 
+// MailConfig holds outgoing email configuration
+type MailConfig struct {
+	Driver       string // smtp, log or file
+	From         string
+	SMTPHost     string
+	SMTPPort     string
+	SMTPUsername string
+	SMTPPassword string
+	FileDir      string // Output directory for the file driver
+}
+
+// MFAConfig holds two-factor authentication configuration
+type MFAConfig struct {
+	Issuer        string        // Account issuer shown in authenticator apps
+	EncryptionKey string        // Base64 encoded 32 byte key used to encrypt TOTP secrets
+	ChallengeTTL  time.Duration // Lifetime of the token bridging the password and code steps of login
+}
+
+// PasswordConfig holds the rules new passwords must satisfy
+type PasswordConfig struct {
+	MinLength          int32 // Minimum length in characters
+	MaxLength          int32 // Maximum length in bytes; bcrypt only hashes the first 72
+	RequireUppercase   bool
+	RequireLowercase   bool
+	RequireDigit       bool
+	RequireSymbol      bool
+	RejectPersonalInfo bool   // Reject passwords containing the user's email address or name
+	RejectCommon       bool   // Reject passwords from the common-passwords list
+	BlocklistFile      string // Optional file of extra rejected passwords, one per line
+	HashAlgorithm      string // argon2id or bcrypt; stored hashes of the other algorithm are upgraded on login
+	BcryptCost         int32
+	Argon2Memory       int32 // Memory in KiB
+	Argon2Iterations   int32
+	Argon2Parallelism  int32
+}
+
+// OutboxConfig holds the transactional outbox relay configuration
+type OutboxConfig struct {
+	RelayEnabled   bool          // Run the relay in this process; disable to run it elsewhere
+	PollInterval   time.Duration // Pause between polls when the outbox is drained
+	BatchSize      int32         // Maximum number of events delivered per poll
+	LeaseDuration  time.Duration // How long claimed events are reserved for delivery by one relay
+	MaxAttempts    int32         // Failed deliveries after which an event is no longer retried
+	RetryBaseDelay time.Duration // Delay before the first retry, doubled for each further failure
+	RetryMaxDelay  time.Duration // Upper bound for the retry delay
+	Sinks          string        // Comma-separated sinks events are delivered to: log, webhook
+	WebhookURL     string
+	WebhookSecret  string // Optional; signs webhook bodies with HMAC-SHA256
+	WebhookTimeout time.Duration
+}
+
+// AuthConfig holds authentication and token signing configuration
+type AuthConfig struct {
+	JWTAlgorithm                  string // HS256, RS256 or EdDSA
+	JWTSecret                     string // Shared secret for HS256
+	JWTPrivateKeyFile             string // PEM private key for RS256/EdDSA
+	JWTPublicKeyFile              string // PEM public key for RS256/EdDSA
+	AccessTokenTTL                time.Duration
+	RefreshTokenTTL               time.Duration
+	PasswordResetTTL              time.Duration
+	PasswordResetURL              string // Link emailed to users; the token is appended as ?token=
+	EmailVerificationTTL          time.Duration
+	EmailVerificationURL          string        // Link emailed to new users; the token is appended as ?token=
+	RequireEmailVerification      bool          // Reject login until the email address is verified
+	LockoutThreshold              int32         // Failed attempts before an account is locked; 0 disables lockout
+	LockoutDuration               time.Duration // First lock period, doubled for each repeated lockout
+	LockoutMaxDuration            time.Duration // Upper bound for the escalating lock period
+	LoginIPMaxFailures            int32         // Failed attempts per client IP within LoginIPWindow; 0 disables
+	LoginIPWindow                 time.Duration
+	PasswordResetIPMaxRequests    int32 // Password reset requests per client IP within PasswordResetWindow; 0 disables
+	PasswordResetEmailMaxRequests int32 // Password reset requests per email within PasswordResetWindow; 0 disables
+	PasswordResetWindow           time.Duration
+	Issuer                        string
+	Audience                      string
+}
+
 /*
 Load reads configuration from environment variables and .env file.
 It loads application settings, database connection parameters, and logger configuration.
@@ -53,24 +143,80 @@ func Load() (*Config, error) {
 
 	cfg := &Config{
 		App: AppConfig{
-			Environment: getEnv("APP_ENV", "development"),
-			Port:        getEnv("APP_PORT", "6969"),
-		},
-		Database: DatabaseConfig{
-			Host:            getEnv("DB_HOST", "localhost"),
-			Port:            getEnv("DB_PORT", "5432"),
-			User:            getEnv("DB_USER", "postgres"),
-			Password:        getEnv("DB_PASSWORD", "postgres"),
-			DBName:          getEnv("DB_NAME", "go_ddd_starter"),
-			SSLMode:         getEnv("DB_SSLMODE", "disable"),
-			MaxConns:        getEnvAsInt32("DB_MAX_CONNS", 25),
-			MinConns:        getEnvAsInt32("DB_MIN_CONNS", 5),
-			MaxConnLifetime: getEnvAsDuration("DB_MAX_CONN_LIFETIME", "1h"),
-			MaxConnIdleTime: getEnvAsDuration("DB_MAX_CONN_IDLE_TIME", "30m"),
+			Environment:        getEnv("APP_ENV", "development"),
+			Port:               getEnv("APP_PORT", "6969"),
+			ProblemTypeBaseURI: getEnv("PROBLEM_TYPE_BASE_URI", "/problems/"),
 		},
+		Database: loadDatabaseConfig(),
 		Logger: LoggerConfig{
 			Level: getEnv("LOG_LEVEL", "info"),
 		},
+		Auth: AuthConfig{
+			JWTAlgorithm:                  getEnv("JWT_ALGORITHM", "HS256"),
+			JWTSecret:                     getEnv("JWT_SECRET", ""),
+			JWTPrivateKeyFile:             getEnv("JWT_PRIVATE_KEY_FILE", ""),
+			JWTPublicKeyFile:              getEnv("JWT_PUBLIC_KEY_FILE", ""),
+			AccessTokenTTL:                getEnvAsDuration("JWT_ACCESS_TOKEN_TTL", "15m"),
+			RefreshTokenTTL:               getEnvAsDuration("JWT_REFRESH_TOKEN_TTL", "720h"),
+			PasswordResetTTL:              getEnvAsDuration("PASSWORD_RESET_TOKEN_TTL", "1h"),
+			PasswordResetURL:              getEnv("PASSWORD_RESET_URL", "http://localhost:3000/reset-password"),
+			EmailVerificationTTL:          getEnvAsDuration("EMAIL_VERIFICATION_TOKEN_TTL", "24h"),
+			EmailVerificationURL:          getEnv("EMAIL_VERIFICATION_URL", "http://localhost:3000/verify-email"),
+			RequireEmailVerification:      getEnvAsBool("REQUIRE_EMAIL_VERIFICATION", false),
+			LockoutThreshold:              getEnvAsInt32("LOCKOUT_THRESHOLD", 5),
+			LockoutDuration:               getEnvAsDuration("LOCKOUT_DURATION", "1m"),
+			LockoutMaxDuration:            getEnvAsDuration("LOCKOUT_MAX_DURATION", "1h"),
+			LoginIPMaxFailures:            getEnvAsInt32("LOGIN_IP_MAX_FAILURES", 20),
+			LoginIPWindow:                 getEnvAsDuration("LOGIN_IP_WINDOW", "15m"),
+			PasswordResetIPMaxRequests:    getEnvAsInt32("PASSWORD_RESET_IP_MAX_REQUESTS", 10),
+			PasswordResetEmailMaxRequests: getEnvAsInt32("PASSWORD_RESET_EMAIL_MAX_REQUESTS", 3),
+			PasswordResetWindow:           getEnvAsDuration("PASSWORD_RESET_WINDOW", "1h"),
+			Issuer:                        getEnv("JWT_ISSUER", "go-ddd-clean-starter"),
+			Audience:                      getEnv("JWT_AUDIENCE", "go-ddd-clean-starter-api"),
+		},
+		Mail: MailConfig{
+			Driver:       getEnv("MAIL_DRIVER", "log"),
+			From:         getEnv("MAIL_FROM", "no-reply@localhost"),
+			SMTPHost:     getEnv("SMTP_HOST", ""),
+			SMTPPort:     getEnv("SMTP_PORT", "587"),
+			SMTPUsername: getEnv("SMTP_USERNAME", ""),
+			SMTPPassword: getEnv("SMTP_PASSWORD", ""),
+			FileDir:      getEnv("MAIL_FILE_DIR", "./tmp/mail"),
+		},
+		MFA: MFAConfig{
+			Issuer:        getEnv("MFA_ISSUER", "Go DDD Clean Starter"),
+			EncryptionKey: getEnv("MFA_ENCRYPTION_KEY", ""),
+			ChallengeTTL:  getEnvAsDuration("MFA_CHALLENGE_TTL", "5m"),
+		},
+		Password: PasswordConfig{
+			MinLength:          getEnvAsInt32("PASSWORD_MIN_LENGTH", 8),
+			MaxLength:          getEnvAsInt32("PASSWORD_MAX_LENGTH", 72),
+			RequireUppercase:   getEnvAsBool("PASSWORD_REQUIRE_UPPERCASE", false),
+			RequireLowercase:   getEnvAsBool("PASSWORD_REQUIRE_LOWERCASE", false),
+			RequireDigit:       getEnvAsBool("PASSWORD_REQUIRE_DIGIT", false),
+			RequireSymbol:      getEnvAsBool("PASSWORD_REQUIRE_SYMBOL", false),
+			RejectPersonalInfo: getEnvAsBool("PASSWORD_REJECT_PERSONAL_INFO", true),
+			RejectCommon:       getEnvAsBool("PASSWORD_REJECT_COMMON", true),
+			BlocklistFile:      getEnv("PASSWORD_BLOCKLIST_FILE", ""),
+			HashAlgorithm:      getEnv("PASSWORD_HASH_ALGORITHM", "argon2id"),
+			BcryptCost:         getEnvAsInt32("PASSWORD_BCRYPT_COST", 10),
+			Argon2Memory:       getEnvAsInt32("PASSWORD_ARGON2_MEMORY", 19456),
+			Argon2Iterations:   getEnvAsInt32("PASSWORD_ARGON2_ITERATIONS", 2),
+			Argon2Parallelism:  getEnvAsInt32("PASSWORD_ARGON2_PARALLELISM", 1),
+		},
+		Outbox: OutboxConfig{
+			RelayEnabled:   getEnvAsBool("OUTBOX_RELAY_ENABLED", true),
+			PollInterval:   getEnvAsDuration("OUTBOX_POLL_INTERVAL", "1s"),
+			BatchSize:      getEnvAsInt32("OUTBOX_BATCH_SIZE", 100),
+			LeaseDuration:  getEnvAsDuration("OUTBOX_LEASE_DURATION", "5m"),
+			MaxAttempts:    getEnvAsInt32("OUTBOX_MAX_ATTEMPTS", 10),
+			RetryBaseDelay: getEnvAsDuration("OUTBOX_RETRY_BASE_DELAY", "1s"),
+			RetryMaxDelay:  getEnvAsDuration("OUTBOX_RETRY_MAX_DELAY", "10m"),
+			Sinks:          getEnv("OUTBOX_SINKS", "log"),
+			WebhookURL:     getEnv("OUTBOX_WEBHOOK_URL", ""),
+			WebhookSecret:  getEnv("OUTBOX_WEBHOOK_SECRET", ""),
+			WebhookTimeout: getEnvAsDuration("OUTBOX_WEBHOOK_TIMEOUT", "10s"),
+		},
 	}
 
 	// Validate configuration
@@ -81,13 +227,177 @@ func Load() (*Config, error) {
 	return cfg, nil
 }
 
+/*
+LoadDatabase reads only the database settings from environment variables and
+the .env file, for commands such as migrate that need nothing else.
+The other sections of the returned Config are left empty and are not validated,
+so a migration job does not need the application's secrets.
+Returns an error if the database settings are missing or invalid.
+*/
+func LoadDatabase() (*Config, error) {
+	// Load .env file if it exists (ignore error if file doesn't exist)
+	_ = godotenv.Load()
+
+	cfg := &Config{Database: loadDatabaseConfig()}
+
+	if err := cfg.ValidateDatabase(); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	return cfg, nil
+}
+
+// loadDatabaseConfig reads the database settings from environment variables
+func loadDatabaseConfig() DatabaseConfig {
+	return DatabaseConfig{
+		Host:            getEnv("DB_HOST", "localhost"),
+		Port:            getEnv("DB_PORT", "5432"),
+		User:            getEnv("DB_USER", "postgres"),
+		Password:        getEnv("DB_PASSWORD", "postgres"),
+		DBName:          getEnv("DB_NAME", "go_ddd_starter"),
+		SSLMode:         getEnv("DB_SSLMODE", "disable"),
+		MaxConns:        getEnvAsInt32("DB_MAX_CONNS", 25),
+		MinConns:        getEnvAsInt32("DB_MIN_CONNS", 5),
+		MaxConnLifetime: getEnvAsDuration("DB_MAX_CONN_LIFETIME", "1h"),
+		MaxConnIdleTime: getEnvAsDuration("DB_MAX_CONN_IDLE_TIME", "30m"),
+
+		TxIsolation:      getEnv("DB_TX_ISOLATION", "read committed"),
+		TxMaxRetries:     getEnvAsInt32("DB_TX_MAX_RETRIES", 3),
+		TxRetryBaseDelay: getEnvAsDuration("DB_TX_RETRY_BASE_DELAY", "10ms"),
+		TxRetryMaxDelay:  getEnvAsDuration("DB_TX_RETRY_MAX_DELAY", "500ms"),
+
+		AutoMigrate: getEnvAsBool("DB_AUTO_MIGRATE", false),
+	}
+}
+
 /*
 Validate checks if the configuration is valid by ensuring all required fields are present.
-It verifies that critical database connection parameters (host, port, user, database name)
-and application settings (port) are not empty.
+It verifies the database settings (see ValidateDatabase), application settings
+(port) and the token signing settings for the selected JWT algorithm.
 Returns an error describing which required field is missing.
 */
 func (c *Config) Validate() error {
+	if err := c.ValidateDatabase(); err != nil {
+		return err
+	}
+	if c.App.Port == "" {
+		return fmt.Errorf("application port is required")
+	}
+	switch c.Auth.JWTAlgorithm {
+	case "HS256":
+		if len(c.Auth.JWTSecret) < 32 {
+			return fmt.Errorf("jwt secret must be at least 32 bytes for HS256")
+		}
+	case "RS256", "EdDSA":
+		if c.Auth.JWTPrivateKeyFile == "" {
+			return fmt.Errorf("jwt private key file is required for %s", c.Auth.JWTAlgorithm)
+		}
+	default:
+		return fmt.Errorf("unsupported jwt algorithm: %s", c.Auth.JWTAlgorithm)
+	}
+	if c.Auth.AccessTokenTTL <= 0 {
+		return fmt.Errorf("access token ttl must be positive")
+	}
+	if c.Auth.RefreshTokenTTL <= 0 {
+		return fmt.Errorf("refresh token ttl must be positive")
+	}
+	if c.Auth.PasswordResetTTL <= 0 {
+		return fmt.Errorf("password reset token ttl must be positive")
+	}
+	if c.Auth.EmailVerificationTTL <= 0 {
+		return fmt.Errorf("email verification token ttl must be positive")
+	}
+	if c.Auth.LockoutThreshold < 0 {
+		return fmt.Errorf("lockout threshold must not be negative")
+	}
+	if c.Auth.LockoutThreshold > 0 && (c.Auth.LockoutDuration <= 0 || c.Auth.LockoutMaxDuration < c.Auth.LockoutDuration) {
+		return fmt.Errorf("lockout duration must be positive and not exceed the maximum lockout duration")
+	}
+	if c.Auth.LoginIPMaxFailures < 0 {
+		return fmt.Errorf("login ip max failures must not be negative")
+	}
+	if c.Auth.LoginIPMaxFailures > 0 && c.Auth.LoginIPWindow <= 0 {
+		return fmt.Errorf("login ip window must be positive")
+	}
+	if c.Auth.PasswordResetIPMaxRequests < 0 || c.Auth.PasswordResetEmailMaxRequests < 0 {
+		return fmt.Errorf("password reset max requests must not be negative")
+	}
+	if (c.Auth.PasswordResetIPMaxRequests > 0 || c.Auth.PasswordResetEmailMaxRequests > 0) && c.Auth.PasswordResetWindow <= 0 {
+		return fmt.Errorf("password reset window must be positive")
+	}
+	if c.Mail.From == "" {
+		return fmt.Errorf("mail sender address is required")
+	}
+	switch c.Mail.Driver {
+	case "smtp":
+		if c.Mail.SMTPHost == "" {
+			return fmt.Errorf("smtp host is required for the smtp mail driver")
+		}
+	case "log", "file":
+	default:
+		return fmt.Errorf("unsupported mail driver: %s", c.Mail.Driver)
+	}
+	if c.MFA.EncryptionKey == "" {
+		return fmt.Errorf("mfa encryption key is required")
+	}
+	if c.MFA.ChallengeTTL <= 0 {
+		return fmt.Errorf("mfa challenge ttl must be positive")
+	}
+	if c.Password.MinLength < 1 {
+		return fmt.Errorf("password min length must be positive")
+	}
+	if c.Password.MaxLength < c.Password.MinLength || c.Password.MaxLength > 72 {
+		return fmt.Errorf("password max length must be between the min length and 72 bytes")
+	}
+	switch c.Password.HashAlgorithm {
+	case "bcrypt":
+		if c.Password.BcryptCost < 4 || c.Password.BcryptCost > 31 {
+			return fmt.Errorf("bcrypt cost must be between 4 and 31")
+		}
+	case "argon2id":
+		if c.Password.Argon2Iterations < 1 || c.Password.Argon2Parallelism < 1 || c.Password.Argon2Parallelism > 255 {
+			return fmt.Errorf("argon2 iterations must be positive and parallelism between 1 and 255")
+		}
+		if c.Password.Argon2Memory < 8*c.Password.Argon2Parallelism {
+			return fmt.Errorf("argon2 memory must be at least 8 KiB per thread")
+		}
+	default:
+		return fmt.Errorf("unsupported password hash algorithm: %s", c.Password.HashAlgorithm)
+	}
+	if c.Outbox.RelayEnabled {
+		if c.Outbox.PollInterval <= 0 {
+			return fmt.Errorf("outbox poll interval must be positive")
+		}
+		if c.Outbox.BatchSize < 1 || c.Outbox.MaxAttempts < 1 {
+			return fmt.Errorf("outbox batch size and max attempts must be positive")
+		}
+		if c.Outbox.LeaseDuration <= 0 {
+			return fmt.Errorf("outbox lease duration must be positive")
+		}
+		if c.Outbox.RetryBaseDelay <= 0 || c.Outbox.RetryMaxDelay < c.Outbox.RetryBaseDelay {
+			return fmt.Errorf("outbox retry base delay must be positive and not exceed the maximum retry delay")
+		}
+		for _, sink := range strings.Split(c.Outbox.Sinks, ",") {
+			switch strings.TrimSpace(sink) {
+			case "", "log":
+			case "webhook":
+				if c.Outbox.WebhookURL == "" {
+					return fmt.Errorf("outbox webhook url is required for the webhook sink")
+				}
+			default:
+				return fmt.Errorf("unsupported outbox sink: %s", strings.TrimSpace(sink))
+			}
+		}
+	}
+	return nil
+}
+
+/*
+ValidateDatabase checks the database settings: the connection parameters
+(host, port, user, database name) are present and the transaction settings are
+supported.
+*/
+func (c *Config) ValidateDatabase() error {
 	if c.Database.Host == "" {
 		return fmt.Errorf("database host is required")
 	}
@@ -100,8 +410,16 @@ func (c *Config) Validate() error {
 	if c.Database.DBName == "" {
 		return fmt.Errorf("database name is required")
 	}
-	if c.App.Port == "" {
-		return fmt.Errorf("application port is required")
+	switch c.Database.TxIsolation {
+	case "read committed", "repeatable read", "serializable":
+	default:
+		return fmt.Errorf("unsupported transaction isolation level: %s", c.Database.TxIsolation)
+	}
+	if c.Database.TxMaxRetries < 0 {
+		return fmt.Errorf("transaction max retries must not be negative")
+	}
+	if c.Database.TxMaxRetries > 0 && (c.Database.TxRetryBaseDelay <= 0 || c.Database.TxRetryMaxDelay < c.Database.TxRetryBaseDelay) {
+		return fmt.Errorf("transaction retry base delay must be positive and not exceed the maximum retry delay")
 	}
 	return nil
 }
@@ -174,3 +492,15 @@ func getEnvAsDuration(key string, defaultValue string) time.Duration {
 	}
 	return duration
 }
+
+func getEnvAsBool(key string, defaultValue bool) bool {
+	valueStr := os.Getenv(key)
+	if valueStr == "" {
+		return defaultValue
+	}
+	value, err := strconv.ParseBool(valueStr)
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
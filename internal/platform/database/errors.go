@@ -0,0 +1,131 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// Errors that Classify reports for PostgreSQL errors, matched with errors.Is
+var (
+	ErrUniqueViolation      = errors.New("unique constraint violation")
+	ErrForeignKeyViolation  = errors.New("foreign key constraint violation")
+	ErrCheckViolation       = errors.New("check constraint violation")
+	ErrNotNullViolation     = errors.New("not-null constraint violation")
+	ErrSerializationFailure = errors.New("serialization failure")
+	ErrDeadlockDetected     = errors.New("deadlock detected")
+)
+
+// SQLSTATE codes of the errors Classify recognises
+const (
+	codeUniqueViolation      = "23505"
+	codeForeignKeyViolation  = "23503"
+	codeCheckViolation       = "23514"
+	codeNotNullViolation     = "23502"
+	codeSerializationFailure = "40001"
+	codeDeadlockDetected     = "40P01"
+)
+
+// kinds maps SQLSTATE codes to the errors they are classified as
+var kinds = map[string]error{
+	codeUniqueViolation:      ErrUniqueViolation,
+	codeForeignKeyViolation:  ErrForeignKeyViolation,
+	codeCheckViolation:       ErrCheckViolation,
+	codeNotNullViolation:     ErrNotNullViolation,
+	codeSerializationFailure: ErrSerializationFailure,
+	codeDeadlockDetected:     ErrDeadlockDetected,
+}
+
+/*
+Error is a PostgreSQL error that Classify recognised.
+Kind is one of the Err* variables above; Constraint, Table and Column name what
+was violated, when PostgreSQL reports it, so repositories can translate each
+constraint into a domain error.
+errors.Is matches Kind, and errors.As still finds the *pgconn.PgError.
+*/
+type Error struct {
+	Kind       error
+	Code       string // SQLSTATE
+	Constraint string
+	Table      string
+	Column     string
+
+	cause *pgconn.PgError
+}
+
+// Error implements the error interface
+func (e *Error) Error() string {
+	if e.Constraint != "" {
+		return fmt.Sprintf("%v on %s: %s", e.Kind, e.Constraint, e.cause.Message)
+	}
+	return fmt.Sprintf("%v: %s", e.Kind, e.cause.Message)
+}
+
+// Is reports whether target is the kind of the error
+func (e *Error) Is(target error) bool {
+	return target == e.Kind
+}
+
+/*
+Unwrap returns the *pgconn.PgError that was classified.
+This allows errors.As() to reach the driver error for details not copied here.
+*/
+func (e *Error) Unwrap() error {
+	return e.cause
+}
+
+/*
+Classify converts a PostgreSQL error into an *Error by its SQLSTATE code.
+Errors that are not, or do not wrap, a *pgconn.PgError with one of the
+recognised codes are returned unchanged, as is nil.
+Example: fmt.Errorf("failed to create user: %w", database.Classify(err))
+*/
+func Classify(err error) error {
+	var classified *Error
+	if err == nil || errors.As(err, &classified) {
+		return err
+	}
+
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return err
+	}
+
+	kind, ok := kinds[pgErr.Code]
+	if !ok {
+		return err
+	}
+
+	return &Error{
+		Kind:       kind,
+		Code:       pgErr.Code,
+		Constraint: pgErr.ConstraintName,
+		Table:      pgErr.TableName,
+		Column:     pgErr.ColumnName,
+		cause:      pgErr,
+	}
+}
+
+/*
+IsConstraintViolation reports whether err is a violation of the given kind
+(e.g. ErrUniqueViolation) of the named constraint.
+Example: IsConstraintViolation(err, ErrUniqueViolation, "users_email_key")
+*/
+func IsConstraintViolation(err error, kind error, constraint string) bool {
+	var classified *Error
+	if !errors.As(Classify(err), &classified) {
+		return false
+	}
+	return classified.Kind == kind && classified.Constraint == constraint
+}
+
+/*
+IsRetryable reports whether err is a serialization failure or a deadlock:
+transient conflicts with concurrent transactions, after which the whole
+transaction can be retried from the start.
+*/
+func IsRetryable(err error) bool {
+	err = Classify(err)
+	return errors.Is(err, ErrSerializationFailure) || errors.Is(err, ErrDeadlockDetected)
+}
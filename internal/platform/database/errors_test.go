@@ -0,0 +1,88 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		name string
+		code string
+		want error
+	}{
+		{"unique", "23505", ErrUniqueViolation},
+		{"foreign key", "23503", ErrForeignKeyViolation},
+		{"check", "23514", ErrCheckViolation},
+		{"not null", "23502", ErrNotNullViolation},
+		{"serialization", "40001", ErrSerializationFailure},
+		{"deadlock", "40P01", ErrDeadlockDetected},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pgErr := &pgconn.PgError{Code: tt.code, Message: "boom", ConstraintName: "some_constraint"}
+			err := Classify(fmt.Errorf("failed to query: %w", pgErr))
+
+			if !errors.Is(err, tt.want) {
+				t.Errorf("Classify() = %v, want it to match %v", err, tt.want)
+			}
+
+			var classified *Error
+			if !errors.As(err, &classified) || classified.Constraint != "some_constraint" || classified.Code != tt.code {
+				t.Errorf("Classify() = %#v, want an *Error with the constraint and code", err)
+			}
+
+			var cause *pgconn.PgError
+			if !errors.As(err, &cause) || cause != pgErr {
+				t.Error("Classify() result does not unwrap to the *pgconn.PgError")
+			}
+		})
+	}
+}
+
+func TestClassifyUnrecognised(t *testing.T) {
+	syntaxErr := &pgconn.PgError{Code: "42601", Message: "syntax error"}
+	plainErr := errors.New("connection refused")
+
+	for _, err := range []error{nil, syntaxErr, plainErr} {
+		if got := Classify(err); got != err {
+			t.Errorf("Classify(%v) = %v, want it unchanged", err, got)
+		}
+	}
+}
+
+func TestIsConstraintViolation(t *testing.T) {
+	err := &pgconn.PgError{Code: "23505", ConstraintName: "users_email_key"}
+
+	if !IsConstraintViolation(err, ErrUniqueViolation, "users_email_key") {
+		t.Error("IsConstraintViolation() = false for the violated constraint, want true")
+	}
+	if IsConstraintViolation(err, ErrUniqueViolation, "users_pkey") {
+		t.Error("IsConstraintViolation() = true for another constraint, want false")
+	}
+	if IsConstraintViolation(err, ErrCheckViolation, "users_email_key") {
+		t.Error("IsConstraintViolation() = true for another kind, want false")
+	}
+}
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{&pgconn.PgError{Code: "40001"}, true},
+		{&pgconn.PgError{Code: "40P01"}, true},
+		{&pgconn.PgError{Code: "23505"}, false},
+		{errors.New("connection refused"), false},
+	}
+
+	for _, tt := range tests {
+		if got := IsRetryable(tt.err); got != tt.want {
+			t.Errorf("IsRetryable(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
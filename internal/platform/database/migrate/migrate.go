@@ -0,0 +1,338 @@
+package migrate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+
+	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/platform/logger"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var (
+	// ErrDirty is returned when a previous migration failed halfway and the schema must be repaired and forced
+	ErrDirty = errors.New("database is dirty")
+
+	// ErrUnknownVersion is returned for a version that has no migration
+	ErrUnknownVersion = errors.New("unknown migration version")
+)
+
+/*
+createVersionTable creates the table recording the schema version.
+Its layout is the one golang-migrate uses, so databases migrated with the
+migrate CLI can be taken over as they are.
+*/
+const createVersionTable = `
+CREATE TABLE IF NOT EXISTS schema_migrations (
+    version BIGINT NOT NULL PRIMARY KEY,
+    dirty BOOLEAN NOT NULL
+)`
+
+/*
+lockKey is the advisory lock held while migrating.
+It is derived from the schema, so migrations of different schemas in one
+database do not wait for each other.
+*/
+const lockKey = `hashtext(current_schema() || '.schema_migrations')`
+
+/*
+Migrator applies SQL migrations to the database.
+The schema version is stored in the schema_migrations table. Every command
+takes a PostgreSQL advisory lock first, so several replicas migrating on startup
+run one after another and only the first one applies anything.
+
+Each migration runs in its own transaction together with the version update, so
+a failed migration leaves the schema at the previous version.
+*/
+type Migrator struct {
+	pool       *pgxpool.Pool
+	migrations []Migration
+	logger     *logger.Logger
+}
+
+/*
+New creates a Migrator for the migrations in the root of fsys,
+named {version}_{name}.up.sql and {version}_{name}.down.sql.
+Returns an error if the migrations cannot be read or are inconsistent.
+*/
+func New(pool *pgxpool.Pool, fsys fs.FS, log *logger.Logger) (*Migrator, error) {
+	migrations, err := loadMigrations(fsys)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Migrator{
+		pool:       pool,
+		migrations: migrations,
+		logger:     log,
+	}, nil
+}
+
+// Status describes the schema version and which migrations are applied
+type Status struct {
+	Version    int64 // 0 if no migration is applied
+	Dirty      bool
+	Migrations []MigrationStatus
+}
+
+// MigrationStatus reports whether a migration is applied
+type MigrationStatus struct {
+	Version int64
+	Name    string
+	Applied bool
+}
+
+// Up applies every pending migration
+func (m *Migrator) Up(ctx context.Context) error {
+	var latest int64
+	if len(m.migrations) > 0 {
+		latest = m.migrations[len(m.migrations)-1].Version
+	}
+
+	return m.withLock(ctx, func(conn *pgxpool.Conn) error {
+		return m.migrateTo(ctx, conn, latest)
+	})
+}
+
+/*
+Down reverts the last n applied migrations.
+Returns an error if fewer than n migrations are applied.
+*/
+func (m *Migrator) Down(ctx context.Context, n int) error {
+	if n < 1 {
+		return fmt.Errorf("number of migrations to revert must be positive")
+	}
+
+	return m.withLock(ctx, func(conn *pgxpool.Conn) error {
+		current, _, err := m.version(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if current != 0 && m.index(current) < 0 {
+			return fmt.Errorf("%w: database is at version %d", ErrUnknownVersion, current)
+		}
+
+		applied := m.index(current) + 1
+		if n > applied {
+			return fmt.Errorf("cannot revert %d migrations: only %d applied", n, applied)
+		}
+
+		var target int64
+		if applied > n {
+			target = m.migrations[applied-n-1].Version
+		}
+		return m.migrateTo(ctx, conn, target)
+	})
+}
+
+/*
+Goto migrates up or down to the given version.
+Version 0 reverts every migration.
+*/
+func (m *Migrator) Goto(ctx context.Context, version int64) error {
+	if version != 0 && m.index(version) < 0 {
+		return fmt.Errorf("%w: %d", ErrUnknownVersion, version)
+	}
+
+	return m.withLock(ctx, func(conn *pgxpool.Conn) error {
+		return m.migrateTo(ctx, conn, version)
+	})
+}
+
+/*
+Force records the given version as applied and clears the dirty flag without
+running any migration. Use it after repairing a failed migration by hand, or to
+adopt a database whose schema was created some other way.
+Version 0 records that no migration is applied.
+*/
+func (m *Migrator) Force(ctx context.Context, version int64) error {
+	if version != 0 && m.index(version) < 0 {
+		return fmt.Errorf("%w: %d", ErrUnknownVersion, version)
+	}
+
+	return m.withLock(ctx, func(conn *pgxpool.Conn) error {
+		tx, err := conn.Begin(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to begin transaction: %w", err)
+		}
+		defer func() { _ = tx.Rollback(ctx) }()
+
+		if err := setVersion(ctx, tx, version); err != nil {
+			return err
+		}
+		if err := tx.Commit(ctx); err != nil {
+			return fmt.Errorf("failed to commit schema version: %w", err)
+		}
+
+		m.logger.Warn("Forced schema version", "version", version)
+		return nil
+	})
+}
+
+// Status reports the schema version and lists every migration
+func (m *Migrator) Status(ctx context.Context) (*Status, error) {
+	status := &Status{}
+	err := m.withLock(ctx, func(conn *pgxpool.Conn) error {
+		var err error
+		status.Version, status.Dirty, err = m.version(ctx, conn)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	status.Migrations = make([]MigrationStatus, len(m.migrations))
+	for i, migration := range m.migrations {
+		status.Migrations[i] = MigrationStatus{
+			Version: migration.Version,
+			Name:    migration.Name,
+			Applied: migration.Version <= status.Version,
+		}
+	}
+	return status, nil
+}
+
+/*
+withLock runs fn on a connection holding the migration advisory lock, waiting
+for the lock if another process is migrating. The version table is created
+first if it does not exist.
+*/
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *pgxpool.Conn) error) error {
+	conn, err := m.pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to acquire connection: %w", err)
+	}
+	defer conn.Release()
+
+	if _, err := conn.Exec(ctx, "SELECT pg_advisory_lock("+lockKey+")"); err != nil {
+		return fmt.Errorf("failed to acquire migration lock: %w", err)
+	}
+	defer func() {
+		// Unlock even if ctx is done; closing the session releases the lock otherwise
+		if _, err := conn.Exec(context.Background(), "SELECT pg_advisory_unlock("+lockKey+")"); err != nil {
+			m.logger.Error("Failed to release migration lock", "error", err.Error())
+			_ = conn.Conn().Close(context.Background())
+		}
+	}()
+
+	if _, err := conn.Exec(ctx, createVersionTable); err != nil {
+		return fmt.Errorf("failed to create schema_migrations table: %w", err)
+	}
+
+	return fn(conn)
+}
+
+/*
+migrateTo applies or reverts migrations one at a time until the schema is at
+the target version. Returns ErrDirty if the schema is dirty, and
+ErrUnknownVersion if the current version has no migration.
+*/
+func (m *Migrator) migrateTo(ctx context.Context, conn *pgxpool.Conn, target int64) error {
+	current, dirty, err := m.version(ctx, conn)
+	if err != nil {
+		return err
+	}
+	if dirty {
+		return fmt.Errorf("%w: version %d failed halfway; repair it and run force", ErrDirty, current)
+	}
+	if current != 0 && m.index(current) < 0 {
+		return fmt.Errorf("%w: database is at version %d", ErrUnknownVersion, current)
+	}
+
+	if current == target {
+		m.logger.Info("Schema is up to date", "version", current)
+		return nil
+	}
+
+	for _, migration := range m.migrations {
+		if migration.Version <= current || migration.Version > target {
+			continue
+		}
+		if err := m.apply(ctx, conn, migration, migration.up, migration.Version); err != nil {
+			return err
+		}
+		m.logger.Info("Applied migration", "version", migration.Version, "name", migration.Name)
+	}
+
+	for i := len(m.migrations) - 1; i >= 0; i-- {
+		migration := m.migrations[i]
+		if migration.Version > current || migration.Version <= target {
+			continue
+		}
+
+		var previous int64
+		if i > 0 {
+			previous = m.migrations[i-1].Version
+		}
+		if migration.down == "" {
+			return fmt.Errorf("migration %d_%s has no down migration", migration.Version, migration.Name)
+		}
+		if err := m.apply(ctx, conn, migration, migration.down, previous); err != nil {
+			return err
+		}
+		m.logger.Info("Reverted migration", "version", migration.Version, "name", migration.Name)
+	}
+
+	return nil
+}
+
+// apply runs sql and records the resulting version in one transaction
+func (m *Migrator) apply(ctx context.Context, conn *pgxpool.Conn, migration Migration, sql string, version int64) error {
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if _, err := tx.Exec(ctx, sql); err != nil {
+		return fmt.Errorf("failed to run migration %d_%s: %w", migration.Version, migration.Name, err)
+	}
+	if err := setVersion(ctx, tx, version); err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit migration %d_%s: %w", migration.Version, migration.Name, err)
+	}
+
+	return nil
+}
+
+// version returns the recorded schema version, or 0 if none is recorded
+func (m *Migrator) version(ctx context.Context, conn *pgxpool.Conn) (int64, bool, error) {
+	var version int64
+	var dirty bool
+	err := conn.QueryRow(ctx, "SELECT version, dirty FROM schema_migrations LIMIT 1").Scan(&version, &dirty)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to read schema version: %w", err)
+	}
+	return version, dirty, nil
+}
+
+// index returns the position of the migration with the given version, or -1
+func (m *Migrator) index(version int64) int {
+	for i, migration := range m.migrations {
+		if migration.Version == version {
+			return i
+		}
+	}
+	return -1
+}
+
+// setVersion records version as the clean schema version; 0 clears it
+func setVersion(ctx context.Context, tx pgx.Tx, version int64) error {
+	if _, err := tx.Exec(ctx, "DELETE FROM schema_migrations"); err != nil {
+		return fmt.Errorf("failed to clear schema version: %w", err)
+	}
+	if version == 0 {
+		return nil
+	}
+	if _, err := tx.Exec(ctx, "INSERT INTO schema_migrations (version, dirty) VALUES ($1, FALSE)", version); err != nil {
+		return fmt.Errorf("failed to record schema version: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+package migrate_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/dzikrisyairozi/go-ddd-clean-starter/infrastructure/database/migrations"
+	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/platform/database/dbtest"
+	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/platform/database/migrate"
+	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/platform/logger"
+)
+
+func TestMigrator(t *testing.T) {
+	db := dbtest.Open(t)
+	ctx := context.Background()
+
+	migrator, err := migrate.New(db.Pool, migrations.FS, logger.New("warn"))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	// dbtest.Open has already migrated the schema up
+	status := mustStatus(t, migrator)
+	latest := status.Migrations[len(status.Migrations)-1].Version
+	if status.Version != latest || status.Dirty {
+		t.Fatalf("schema is at version %d (dirty %v), want %d", status.Version, status.Dirty, latest)
+	}
+
+	t.Run("up is a no-op when nothing is pending", func(t *testing.T) {
+		if err := migrator.Up(ctx); err != nil {
+			t.Fatalf("Up() error = %v", err)
+		}
+		assertVersion(t, migrator, latest)
+	})
+
+	t.Run("down reverts the last migrations", func(t *testing.T) {
+		if err := migrator.Down(ctx, 2); err != nil {
+			t.Fatalf("Down() error = %v", err)
+		}
+		status := assertVersion(t, migrator, latest-2)
+
+		for _, m := range status.Migrations {
+			if m.Applied != (m.Version <= latest-2) {
+				t.Errorf("migration %d applied = %v", m.Version, m.Applied)
+			}
+		}
+	})
+
+	t.Run("down refuses to revert more than is applied", func(t *testing.T) {
+		if err := migrator.Down(ctx, int(latest)); err == nil {
+			t.Fatal("Down() error = nil, want an error")
+		}
+		assertVersion(t, migrator, latest-2)
+	})
+
+	t.Run("goto zero reverts everything", func(t *testing.T) {
+		if err := migrator.Goto(ctx, 0); err != nil {
+			t.Fatalf("Goto() error = %v", err)
+		}
+		assertVersion(t, migrator, 0)
+
+		var exists bool
+		if err := db.Pool.QueryRow(ctx, "SELECT to_regclass('users') IS NOT NULL").Scan(&exists); err != nil {
+			t.Fatalf("failed to look up users table: %v", err)
+		}
+		if exists {
+			t.Error("users table still exists after reverting every migration")
+		}
+	})
+
+	t.Run("goto an unknown version", func(t *testing.T) {
+		if err := migrator.Goto(ctx, latest+1); !errors.Is(err, migrate.ErrUnknownVersion) {
+			t.Fatalf("Goto() error = %v, want ErrUnknownVersion", err)
+		}
+	})
+
+	t.Run("concurrent ups apply each migration once", func(t *testing.T) {
+		var wg sync.WaitGroup
+		errs := make([]error, 3)
+		for i := range errs {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				errs[i] = migrator.Up(ctx)
+			}(i)
+		}
+		wg.Wait()
+
+		for i, err := range errs {
+			if err != nil {
+				t.Errorf("Up() #%d error = %v", i, err)
+			}
+		}
+		assertVersion(t, migrator, latest)
+	})
+
+	t.Run("dirty schema must be forced", func(t *testing.T) {
+		if _, err := db.Pool.Exec(ctx, "UPDATE schema_migrations SET dirty = TRUE"); err != nil {
+			t.Fatalf("failed to mark schema dirty: %v", err)
+		}
+		if err := migrator.Down(ctx, 1); !errors.Is(err, migrate.ErrDirty) {
+			t.Fatalf("Down() error = %v, want ErrDirty", err)
+		}
+
+		if err := migrator.Force(ctx, latest); err != nil {
+			t.Fatalf("Force() error = %v", err)
+		}
+		if status := assertVersion(t, migrator, latest); status.Dirty {
+			t.Error("schema is still dirty after Force")
+		}
+	})
+}
+
+// mustStatus returns the migrator's status, failing the test on error
+func mustStatus(t *testing.T, migrator *migrate.Migrator) *migrate.Status {
+	t.Helper()
+
+	status, err := migrator.Status(context.Background())
+	if err != nil {
+		t.Fatalf("Status() error = %v", err)
+	}
+	return status
+}
+
+// assertVersion checks the schema version and returns the status
+func assertVersion(t *testing.T, migrator *migrate.Migrator, want int64) *migrate.Status {
+	t.Helper()
+
+	status := mustStatus(t, migrator)
+	if status.Version != want {
+		t.Errorf("schema version = %d, want %d", status.Version, want)
+	}
+	return status
+}
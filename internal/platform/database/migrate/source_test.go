@@ -0,0 +1,100 @@
+package migrate
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/dzikrisyairozi/go-ddd-clean-starter/infrastructure/database/migrations"
+)
+
+func TestLoadMigrations(t *testing.T) {
+	fsys := fstest.MapFS{
+		"000002_add_name.up.sql":      {Data: []byte("ALTER TABLE t ADD name TEXT;")},
+		"000002_add_name.down.sql":    {Data: []byte("ALTER TABLE t DROP name;")},
+		"000001_create_t.up.sql":      {Data: []byte("CREATE TABLE t (id INT);")},
+		"000001_create_t.down.sql":    {Data: []byte("DROP TABLE t;")},
+		"000010_without_down.up.sql":  {Data: []byte("SELECT 1;")},
+		"migrations.go":               {Data: []byte("package migrations")},
+		"README.md":                   {Data: []byte("not a migration")},
+		"000003_in_a_dir.up.sql/file": {Data: []byte("ignored")},
+	}
+
+	got, err := loadMigrations(fsys)
+	if err != nil {
+		t.Fatalf("loadMigrations() error = %v", err)
+	}
+
+	want := []Migration{
+		{Version: 1, Name: "create_t", up: "CREATE TABLE t (id INT);", down: "DROP TABLE t;"},
+		{Version: 2, Name: "add_name", up: "ALTER TABLE t ADD name TEXT;", down: "ALTER TABLE t DROP name;"},
+		{Version: 10, Name: "without_down", up: "SELECT 1;"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("loadMigrations() returned %d migrations, want %d: %+v", len(got), len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("migration %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestLoadMigrationsInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		fsys fstest.MapFS
+		want string
+	}{
+		{
+			name: "version used twice",
+			fsys: fstest.MapFS{
+				"000001_a.up.sql": {Data: []byte("SELECT 1;")},
+				"000001_b.up.sql": {Data: []byte("SELECT 1;")},
+			},
+			want: "used by both",
+		},
+		{
+			name: "no up migration",
+			fsys: fstest.MapFS{
+				"000001_a.down.sql": {Data: []byte("SELECT 1;")},
+			},
+			want: "no up migration",
+		},
+		{
+			name: "version zero",
+			fsys: fstest.MapFS{
+				"000000_a.up.sql": {Data: []byte("SELECT 1;")},
+			},
+			want: "invalid migration version",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := loadMigrations(tt.fsys)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("loadMigrations() error = %v, want it to mention %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestEmbeddedMigrations(t *testing.T) {
+	got, err := loadMigrations(migrations.FS)
+	if err != nil {
+		t.Fatalf("loadMigrations() error = %v", err)
+	}
+	if len(got) == 0 {
+		t.Fatal("no migrations are embedded")
+	}
+
+	for i, m := range got {
+		if m.Version != int64(i+1) {
+			t.Errorf("migration %d_%s breaks the version sequence at position %d", m.Version, m.Name, i+1)
+		}
+		if m.down == "" {
+			t.Errorf("migration %d_%s has no down migration", m.Version, m.Name)
+		}
+	}
+}
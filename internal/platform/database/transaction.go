@@ -3,19 +3,113 @@ package database
 import (
 	"context"
 	"fmt"
+	"math/rand/v2"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
-// TxManager manages database transactions
+/*
+TxManager manages database transactions.
+The transaction is carried in the context passed to fn, so repositories that
+look it up with TxFromContext take part in it without being handed the pgx.Tx.
+*/
 type TxManager interface {
-	WithTransaction(ctx context.Context, fn func(ctx context.Context, tx pgx.Tx) error) error
+	WithTransaction(ctx context.Context, fn func(ctx context.Context, tx pgx.Tx) error, opts ...TxOption) error
+
+	// Do runs fn as a unit of work for callers that must not depend on pgx
+	Do(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+/*
+TxOptions configures how a transaction is started and retried.
+The zero value runs at the server's default isolation level (READ COMMITTED),
+read-write, without retries.
+*/
+type TxOptions struct {
+	IsoLevel       pgx.TxIsoLevel
+	AccessMode     pgx.TxAccessMode
+	DeferrableMode pgx.TxDeferrableMode
+
+	// MaxRetries is how many times a transaction that failed with a
+	// serialization failure or deadlock (see IsRetryable) is run again
+	MaxRetries int
+
+	// RetryBaseDelay and RetryMaxDelay bound the jittered backoff between attempts
+	RetryBaseDelay time.Duration
+	RetryMaxDelay  time.Duration
+}
+
+// TxOption sets a transaction option
+type TxOption func(*TxOptions)
+
+/*
+WithIsolationLevel runs the transaction at the given isolation level.
+Example: WithIsolationLevel(pgx.Serializable)
+*/
+func WithIsolationLevel(level pgx.TxIsoLevel) TxOption {
+	return func(o *TxOptions) {
+		o.IsoLevel = level
+	}
+}
+
+// ReadOnly starts the transaction READ ONLY
+func ReadOnly() TxOption {
+	return func(o *TxOptions) {
+		o.AccessMode = pgx.ReadOnly
+	}
+}
+
+/*
+Deferrable starts the transaction DEFERRABLE.
+PostgreSQL only honours it for SERIALIZABLE READ ONLY transactions, which then
+wait for a safe snapshot instead of risking a serialization failure.
+*/
+func Deferrable() TxOption {
+	return func(o *TxOptions) {
+		o.DeferrableMode = pgx.Deferrable
+	}
+}
+
+/*
+WithRetry runs the transaction again, up to maxRetries times, when it fails
+with a serialization failure or a deadlock. Before each attempt it waits a
+random delay between zero and baseDelay doubled per attempt, capped at maxDelay.
+Use 0 retries to disable retrying.
+*/
+func WithRetry(maxRetries int, baseDelay, maxDelay time.Duration) TxOption {
+	return func(o *TxOptions) {
+		o.MaxRetries = maxRetries
+		o.RetryBaseDelay = baseDelay
+		o.RetryMaxDelay = maxDelay
+	}
+}
+
+// txContextKey is the context key under which the current transaction is stored
+type txContextKey struct{}
+
+/*
+ContextWithTx returns a copy of ctx carrying the transaction.
+Repositories called with the returned context run their queries in tx.
+*/
+func ContextWithTx(ctx context.Context, tx pgx.Tx) context.Context {
+	return context.WithValue(ctx, txContextKey{}, tx)
+}
+
+/*
+TxFromContext returns the transaction carried in ctx.
+Returns false when ctx is not inside a transaction.
+*/
+func TxFromContext(ctx context.Context) (pgx.Tx, bool) {
+	tx, ok := ctx.Value(txContextKey{}).(pgx.Tx)
+	return tx, ok
 }
 
 // txManager implements TxManager
 type txManager struct {
-	pool *pgxpool.Pool
+	pool     *pgxpool.Pool
+	defaults TxOptions
 }
 
 /*
@@ -23,34 +117,79 @@ NewTxManager creates a new transaction manager instance.
 The transaction manager provides a convenient way to execute database operations
 within a transaction with automatic commit/rollback handling.
 Requires a pgxpool.Pool instance for database connectivity.
+opts are the defaults of every transaction it starts; WithTransaction can
+override them per call.
 */
-func NewTxManager(pool *pgxpool.Pool) TxManager {
-	return &txManager{pool: pool}
+func NewTxManager(pool *pgxpool.Pool, opts ...TxOption) TxManager {
+	tm := &txManager{pool: pool}
+	for _, opt := range opts {
+		opt(&tm.defaults)
+	}
+	return tm
 }
 
 /*
 WithTransaction executes a function within a database transaction.
 It automatically handles transaction lifecycle:
-  - Begins a new transaction
+  - Begins a new transaction and stores it in the context passed to fn
   - Executes the provided function with the transaction context
   - Commits if the function succeeds
   - Rolls back if the function returns an error
   - Rolls back and re-panics if a panic occurs
 
+If ctx already carries a transaction, fn joins it instead: the outermost
+WithTransaction decides whether everything is committed or rolled back, and
+opts are ignored.
+
+If retries are enabled and fn or the commit fails with a serialization failure
+or deadlock, the whole transaction is rolled back and fn runs again in a new
+one. fn must therefore be safe to run more than once: it should read what it
+needs inside the transaction and have no side effects outside it.
+
 This ensures atomic operations - either all database changes succeed or none do.
 Example usage:
 
 	txManager.WithTransaction(ctx, func(ctx context.Context, tx pgx.Tx) error {
 	    // Perform multiple database operations
 	    return nil
-	})
+	}, database.WithIsolationLevel(pgx.Serializable))
 */
-func (tm *txManager) WithTransaction(ctx context.Context, fn func(context.Context, pgx.Tx) error) error {
+func (tm *txManager) WithTransaction(ctx context.Context, fn func(context.Context, pgx.Tx) error, opts ...TxOption) error {
+	// Join the transaction already in progress
+	if tx, ok := TxFromContext(ctx); ok {
+		return fn(ctx, tx)
+	}
+
+	options := tm.defaults
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	for attempt := 0; ; attempt++ {
+		err := tm.run(ctx, fn, options)
+		if err == nil || attempt >= options.MaxRetries || !IsRetryable(err) {
+			return err
+		}
+
+		// Give up with the transaction's error if ctx ends while waiting
+		if sleep(ctx, retryDelay(attempt, options.RetryBaseDelay, options.RetryMaxDelay)) != nil {
+			return err
+		}
+	}
+}
+
+// run executes fn in a single transaction started with options
+func (tm *txManager) run(ctx context.Context, fn func(context.Context, pgx.Tx) error, options TxOptions) error {
 	// Begin transaction
-	tx, err := tm.pool.Begin(ctx)
+	tx, err := tm.pool.BeginTx(ctx, pgx.TxOptions{
+		IsoLevel:       options.IsoLevel,
+		AccessMode:     options.AccessMode,
+		DeferrableMode: options.DeferrableMode,
+	})
 	if err != nil {
 		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
+	ctx = ContextWithTx(ctx, tx)
 
 	// Defer rollback in case of panic
 	defer func() {
@@ -77,3 +216,52 @@ func (tm *txManager) WithTransaction(ctx context.Context, fn func(context.Contex
 
 	return nil
 }
+
+/*
+retryDelay returns the wait before retry attempt+1 using full jitter: a random
+duration between zero and baseDelay·2^attempt, capped at maxDelay.
+Spreading retries out keeps transactions that conflicted from colliding again.
+*/
+func retryDelay(attempt int, baseDelay, maxDelay time.Duration) time.Duration {
+	if baseDelay <= 0 {
+		return 0
+	}
+
+	ceiling := baseDelay
+	for i := 0; i < attempt && (maxDelay <= 0 || ceiling < maxDelay); i++ {
+		ceiling *= 2
+	}
+	if maxDelay > 0 && ceiling > maxDelay {
+		ceiling = maxDelay
+	}
+	return rand.N(ceiling + 1)
+}
+
+// sleep waits for d, returning early with the context's error if ctx is done
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+/*
+Do executes fn within a database transaction, like WithTransaction, but without
+exposing the pgx.Tx. Application services use it to make a use case a single
+unit of work: every repository call made with the ctx passed to fn joins the
+transaction. It uses the options the manager was created with.
+*/
+func (tm *txManager) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	return tm.WithTransaction(ctx, func(ctx context.Context, _ pgx.Tx) error {
+		return fn(ctx)
+	})
+}
@@ -0,0 +1,130 @@
+package database_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/platform/database"
+	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/platform/database/dbtest"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// serializationFailure is the error PostgreSQL reports when a SERIALIZABLE transaction conflicts
+var serializationFailure = &pgconn.PgError{Code: "40001", Message: "could not serialize access due to concurrent update"}
+
+func TestWithTransactionOptions(t *testing.T) {
+	db := dbtest.Open(t)
+	tm := database.NewTxManager(db.Pool)
+	ctx := context.Background()
+
+	err := tm.WithTransaction(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		var isolation, readOnly, deferrable string
+		if err := tx.QueryRow(ctx, "SHOW transaction_isolation").Scan(&isolation); err != nil {
+			return err
+		}
+		if err := tx.QueryRow(ctx, "SHOW transaction_read_only").Scan(&readOnly); err != nil {
+			return err
+		}
+		if err := tx.QueryRow(ctx, "SHOW transaction_deferrable").Scan(&deferrable); err != nil {
+			return err
+		}
+
+		if isolation != "serializable" || readOnly != "on" || deferrable != "on" {
+			t.Errorf("transaction is %s, read only %s, deferrable %s; want serializable, on, on", isolation, readOnly, deferrable)
+		}
+		return nil
+	}, database.WithIsolationLevel(pgx.Serializable), database.ReadOnly(), database.Deferrable())
+	if err != nil {
+		t.Fatalf("WithTransaction() error = %v", err)
+	}
+}
+
+func TestWithTransactionJoinedIgnoresOptions(t *testing.T) {
+	db := dbtest.Open(t)
+	tm := database.NewTxManager(db.Pool)
+
+	err := tm.WithTransaction(context.Background(), func(ctx context.Context, _ pgx.Tx) error {
+		return tm.WithTransaction(ctx, func(ctx context.Context, tx pgx.Tx) error {
+			var readOnly string
+			if err := tx.QueryRow(ctx, "SHOW transaction_read_only").Scan(&readOnly); err != nil {
+				return err
+			}
+			if readOnly != "off" {
+				t.Errorf("joined transaction read only = %s, want off", readOnly)
+			}
+			return nil
+		}, database.ReadOnly())
+	})
+	if err != nil {
+		t.Fatalf("WithTransaction() error = %v", err)
+	}
+}
+
+func TestWithTransactionRetry(t *testing.T) {
+	db := dbtest.Open(t)
+	tm := database.NewTxManager(db.Pool, database.WithRetry(3, time.Millisecond, 5*time.Millisecond))
+	ctx := context.Background()
+
+	t.Run("retries serialization failures", func(t *testing.T) {
+		calls := 0
+		err := tm.WithTransaction(ctx, func(ctx context.Context, _ pgx.Tx) error {
+			calls++
+			if calls < 3 {
+				return serializationFailure
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("WithTransaction() error = %v", err)
+		}
+		if calls != 3 {
+			t.Errorf("fn ran %d times, want 3", calls)
+		}
+	})
+
+	t.Run("gives up after max retries", func(t *testing.T) {
+		calls := 0
+		err := tm.WithTransaction(ctx, func(ctx context.Context, _ pgx.Tx) error {
+			calls++
+			return serializationFailure
+		})
+		if !database.IsRetryable(err) {
+			t.Fatalf("WithTransaction() error = %v, want the serialization failure", err)
+		}
+		if calls != 4 {
+			t.Errorf("fn ran %d times, want 4", calls)
+		}
+	})
+
+	t.Run("does not retry other errors", func(t *testing.T) {
+		errBoom := errors.New("boom")
+		calls := 0
+		err := tm.WithTransaction(ctx, func(ctx context.Context, _ pgx.Tx) error {
+			calls++
+			return errBoom
+		})
+		if !errors.Is(err, errBoom) {
+			t.Fatalf("WithTransaction() error = %v, want %v", err, errBoom)
+		}
+		if calls != 1 {
+			t.Errorf("fn ran %d times, want 1", calls)
+		}
+	})
+
+	t.Run("per call options override the defaults", func(t *testing.T) {
+		calls := 0
+		err := tm.WithTransaction(ctx, func(ctx context.Context, _ pgx.Tx) error {
+			calls++
+			return serializationFailure
+		}, database.WithRetry(0, 0, 0))
+		if !database.IsRetryable(err) {
+			t.Fatalf("WithTransaction() error = %v, want the serialization failure", err)
+		}
+		if calls != 1 {
+			t.Errorf("fn ran %d times, want 1", calls)
+		}
+	})
+}
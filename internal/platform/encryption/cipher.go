@@ -0,0 +1,90 @@
+package encryption
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidCiphertext indicates that a value could not be decrypted
+var ErrInvalidCiphertext = errors.New("invalid ciphertext")
+
+// keySize is the required key size in bytes (AES-256)
+const keySize = 32
+
+/*
+Cipher encrypts small secrets (such as TOTP secrets) for storage at rest using
+AES-256-GCM. Each value gets a random nonce, which is stored in front of the
+ciphertext; the result is base64 encoded so it fits in a TEXT column.
+*/
+type Cipher struct {
+	aead cipher.AEAD
+}
+
+/*
+NewCipher creates a Cipher from a base64 encoded 32 byte key.
+Generate a key with: openssl rand -base64 32
+Returns an error if the key is not valid base64 or has the wrong size.
+*/
+func NewCipher(encodedKey string) (*Cipher, error) {
+	key, err := base64.StdEncoding.DecodeString(encodedKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode encryption key: %w", err)
+	}
+
+	if len(key) != keySize {
+		return nil, fmt.Errorf("encryption key must be %d bytes, got %d", keySize, len(key))
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create block cipher: %w", err)
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create gcm cipher: %w", err)
+	}
+
+	return &Cipher{aead: aead}, nil
+}
+
+/*
+Encrypt encrypts the plaintext and returns the base64 encoded nonce and ciphertext.
+*/
+func (c *Cipher) Encrypt(plaintext string) (string, error) {
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	sealed := c.aead.Seal(nonce, nonce, []byte(plaintext), nil)
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+/*
+Decrypt reverses Encrypt.
+Returns ErrInvalidCiphertext if the value is malformed, was encrypted with a
+different key, or has been tampered with.
+*/
+func (c *Cipher) Decrypt(ciphertext string) (string, error) {
+	sealed, err := base64.StdEncoding.DecodeString(ciphertext)
+	if err != nil {
+		return "", ErrInvalidCiphertext
+	}
+
+	nonceSize := c.aead.NonceSize()
+	if len(sealed) < nonceSize {
+		return "", ErrInvalidCiphertext
+	}
+
+	plaintext, err := c.aead.Open(nil, sealed[:nonceSize], sealed[nonceSize:], nil)
+	if err != nil {
+		return "", ErrInvalidCiphertext
+	}
+
+	return string(plaintext), nil
+}
@@ -0,0 +1,105 @@
+package encryption
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// testKey returns a base64 encoded key made of the given byte
+func testKey(b byte, size int) string {
+	return base64.StdEncoding.EncodeToString([]byte(strings.Repeat(string(b), size)))
+}
+
+func TestCipherRoundTrip(t *testing.T) {
+	c, err := NewCipher(testKey('k', keySize))
+	if err != nil {
+		t.Fatalf("NewCipher() error = %v", err)
+	}
+
+	for _, plaintext := range []string{"", "JBSWY3DPEHPK3PXP", strings.Repeat("long secret ", 100)} {
+		ciphertext, err := c.Encrypt(plaintext)
+		if err != nil {
+			t.Fatalf("Encrypt() error = %v", err)
+		}
+		if plaintext != "" && strings.Contains(ciphertext, plaintext) {
+			t.Errorf("ciphertext %q contains the plaintext", ciphertext)
+		}
+
+		got, err := c.Decrypt(ciphertext)
+		if err != nil {
+			t.Fatalf("Decrypt() error = %v", err)
+		}
+		if got != plaintext {
+			t.Errorf("Decrypt() = %q, want %q", got, plaintext)
+		}
+	}
+}
+
+func TestCipherUsesFreshNonces(t *testing.T) {
+	c, err := NewCipher(testKey('k', keySize))
+	if err != nil {
+		t.Fatalf("NewCipher() error = %v", err)
+	}
+
+	first, _ := c.Encrypt("secret")
+	second, _ := c.Encrypt("secret")
+	if first == second {
+		t.Error("encrypting the same plaintext twice gave the same ciphertext")
+	}
+}
+
+func TestCipherRejectsTampering(t *testing.T) {
+	c, err := NewCipher(testKey('k', keySize))
+	if err != nil {
+		t.Fatalf("NewCipher() error = %v", err)
+	}
+
+	ciphertext, err := c.Encrypt("secret")
+	if err != nil {
+		t.Fatalf("Encrypt() error = %v", err)
+	}
+	sealed, _ := base64.StdEncoding.DecodeString(ciphertext)
+
+	flipped := append([]byte(nil), sealed...)
+	flipped[len(flipped)-1] ^= 0x01
+
+	other, err := NewCipher(testKey('o', keySize))
+	if err != nil {
+		t.Fatalf("NewCipher() error = %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		cipher     *Cipher
+		ciphertext string
+	}{
+		{"flipped bit", c, base64.StdEncoding.EncodeToString(flipped)},
+		{"truncated", c, base64.StdEncoding.EncodeToString(sealed[:len(sealed)-1])},
+		{"shorter than the nonce", c, base64.StdEncoding.EncodeToString(sealed[:4])},
+		{"not base64", c, "not base64!"},
+		{"different key", other, ciphertext},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.cipher.Decrypt(tt.ciphertext); !errors.Is(err, ErrInvalidCiphertext) {
+				t.Errorf("Decrypt() error = %v, want ErrInvalidCiphertext", err)
+			}
+		})
+	}
+}
+
+func TestNewCipherInvalidKey(t *testing.T) {
+	for name, key := range map[string]string{
+		"empty":      "",
+		"not base64": "not base64!",
+		"too short":  testKey('k', 16),
+		"too long":   testKey('k', 64),
+	} {
+		if _, err := NewCipher(key); err == nil {
+			t.Errorf("NewCipher(%s) error = nil, want an error", name)
+		}
+	}
+}
@@ -27,7 +27,11 @@ var (
 	ErrForbidden    = errors.New("forbidden")
 )
 
-// AppError represents an application error with additional context
+/*
+AppError represents an application error with additional context.
+When the error is reported through the Registry, its Fields are sent to the
+client as problem extension members, so they must be safe to expose.
+*/
 type AppError struct {
 	Err     error
 	Message string
@@ -77,7 +81,7 @@ func (e *AppError) WithCode(code string) *AppError {
 
 /*
 WithField adds a single key-value pair to the error's contextual information.
-Useful for adding debugging information like IDs, timestamps, or other relevant data.
+Useful for adding context like IDs, timestamps, or other relevant data.
 Returns the AppError for method chaining.
 Example: err.WithField("user_id", 123)
 */
@@ -0,0 +1,103 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+)
+
+/*
+Definition describes how an error is reported to clients.
+Code is a stable, machine-readable identifier, Status the HTTP status code and
+Message a client-facing explanation that is safe to expose.
+*/
+type Definition struct {
+	Code    string
+	Status  int
+	Message string
+}
+
+// registration pairs a sentinel error with its definition
+type registration struct {
+	err error
+	def Definition
+}
+
+/*
+Registry maps errors to the definitions they are reported with.
+Domains register their sentinel errors once at startup, so the shared error
+renderer can report any error that wraps them without a per-domain switch.
+An AppError can also name its definition by Code.
+It is safe for concurrent use.
+*/
+type Registry struct {
+	mu            sync.RWMutex
+	registrations []registration
+	codes         map[string]Definition
+}
+
+/*
+NewRegistry creates a Registry with the platform-level errors already registered:
+ErrNotFound, ErrUnauthorized, ErrForbidden, ErrValidation and ErrInvalidInput.
+*/
+func NewRegistry() *Registry {
+	r := &Registry{
+		codes: make(map[string]Definition),
+	}
+
+	r.Register(ErrNotFound, Definition{Code: "not_found", Status: http.StatusNotFound, Message: "Resource not found"})
+	r.Register(ErrUnauthorized, Definition{Code: "unauthorized", Status: http.StatusUnauthorized, Message: "Authentication required"})
+	r.Register(ErrForbidden, Definition{Code: "forbidden", Status: http.StatusForbidden, Message: "You are not allowed to perform this action"})
+	r.Register(ErrValidation, Definition{Code: "validation_error", Status: http.StatusUnprocessableEntity, Message: "Request validation failed"})
+	r.Register(ErrInvalidInput, Definition{Code: "invalid_input", Status: http.StatusBadRequest, Message: "Invalid input"})
+
+	return r
+}
+
+/*
+Register adds a sentinel error and the definition it is reported with.
+Several errors may share a code only if they share the whole definition.
+It panics on an empty code or a conflicting definition, since both are
+programming errors that should fail at startup.
+Example: registry.Register(domain.ErrUserNotFound, Definition{Code: "user_not_found", Status: http.StatusNotFound, Message: "User not found"})
+*/
+func (r *Registry) Register(err error, def Definition) {
+	if err == nil || def.Code == "" || def.Status == 0 {
+		panic("errors: Register requires an error, a code and a status")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if existing, ok := r.codes[def.Code]; ok && existing != def {
+		panic(fmt.Sprintf("errors: code %q is already registered with a different definition", def.Code))
+	}
+
+	r.codes[def.Code] = def
+	r.registrations = append(r.registrations, registration{err: err, def: def})
+}
+
+/*
+Lookup returns the definition for an error.
+An AppError with a Code is looked up by that code; otherwise the error chain is
+matched against the registered sentinels with errors.Is, in registration order.
+Returns false if nothing matches, including an AppError with an unknown code.
+*/
+func (r *Registry) Lookup(err error) (Definition, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var appErr *AppError
+	if errors.As(err, &appErr) && appErr.Code != "" {
+		def, ok := r.codes[appErr.Code]
+		return def, ok
+	}
+
+	for _, reg := range r.registrations {
+		if errors.Is(err, reg.err) {
+			return reg.def, true
+		}
+	}
+	return Definition{}, false
+}
@@ -0,0 +1,91 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+var (
+	errWidgetNotFound = errors.New("widget not found")
+	errWidgetMissing  = errors.New("widget missing")
+	errWidgetLocked   = errors.New("widget locked")
+)
+
+var (
+	widgetNotFound = Definition{Code: "widget_not_found", Status: http.StatusNotFound, Message: "Widget not found"}
+	widgetLocked   = Definition{Code: "widget_locked", Status: http.StatusConflict, Message: "Widget is locked"}
+)
+
+// newTestRegistry creates a Registry with the widget errors registered
+func newTestRegistry() *Registry {
+	r := NewRegistry()
+	r.Register(errWidgetNotFound, widgetNotFound)
+	r.Register(errWidgetMissing, widgetNotFound)
+	r.Register(errWidgetLocked, widgetLocked)
+	return r
+}
+
+func TestRegistryLookup(t *testing.T) {
+	r := newTestRegistry()
+
+	tests := []struct {
+		name   string
+		err    error
+		want   Definition
+		wantOK bool
+	}{
+		{"sentinel", errWidgetNotFound, widgetNotFound, true},
+		{"wrapped sentinel", fmt.Errorf("load: %w", errWidgetLocked), widgetLocked, true},
+		{"sentinels sharing a definition", errWidgetMissing, widgetNotFound, true},
+		{"platform error", fmt.Errorf("load: %w", ErrForbidden), Definition{Code: "forbidden", Status: http.StatusForbidden, Message: "You are not allowed to perform this action"}, true},
+		{"app error wrapping a sentinel", NewAppError(errWidgetLocked, "update failed"), widgetLocked, true},
+		{"app error code wins over its wrapped error", NewAppError(errWidgetLocked, "update failed").WithCode("widget_not_found"), widgetNotFound, true},
+		{"app error with an unknown code", NewAppError(errWidgetLocked, "update failed").WithCode("widget_exploded"), Definition{}, false},
+		{"unregistered error", errors.New("boom"), Definition{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := r.Lookup(tt.err)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("Lookup() = %+v, %v, want %+v, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestRegistryLookupUsesRegistrationOrder(t *testing.T) {
+	r := newTestRegistry()
+
+	// An error wrapping two sentinels gets the definition registered first
+	err := fmt.Errorf("%w: %w", errWidgetLocked, errWidgetNotFound)
+	if got, _ := r.Lookup(err); got != widgetNotFound {
+		t.Errorf("Lookup() = %+v, want %+v", got, widgetNotFound)
+	}
+}
+
+func TestRegistryRegisterPanics(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		def  Definition
+	}{
+		{"nil error", nil, widgetLocked},
+		{"empty code", errWidgetLocked, Definition{Status: http.StatusConflict}},
+		{"no status", errWidgetLocked, Definition{Code: "widget_locked"}},
+		{"conflicting definition", errors.New("other"), Definition{Code: "widget_not_found", Status: http.StatusGone, Message: "Widget is gone"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Error("Register() did not panic")
+				}
+			}()
+			newTestRegistry().Register(tt.err, tt.def)
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/platform/logger"
+)
+
+/*
+Event is something that happened in a domain.
+Each domain defines its own event types; the name identifies the kind of event
+(e.g. "user.registered") and is what handlers subscribe to.
+*/
+type Event interface {
+	EventName() string
+}
+
+// Handler reacts to a published event
+type Handler func(ctx context.Context, event Event) error
+
+/*
+Dispatcher delivers events to the handlers subscribed to them, in process.
+Events are published after the change that raised them has been committed, so
+a failing handler cannot undo it: handler errors and panics are logged and the
+remaining handlers still run.
+Handlers run synchronously in subscription order, so slow work should be
+handed off by the handler itself.
+It is safe for concurrent use.
+*/
+type Dispatcher struct {
+	mu       sync.RWMutex
+	handlers map[string][]Handler
+	all      []Handler
+	logger   *logger.Logger
+}
+
+/*
+NewDispatcher creates a Dispatcher without subscribers.
+Requires a Logger to report failing handlers.
+*/
+func NewDispatcher(log *logger.Logger) *Dispatcher {
+	return &Dispatcher{
+		handlers: make(map[string][]Handler),
+		logger:   log,
+	}
+}
+
+/*
+Subscribe registers a handler for the events with the given name.
+Example: dispatcher.Subscribe("user.registered", sendWelcomeEmail)
+*/
+func (d *Dispatcher) Subscribe(name string, handler Handler) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.handlers[name] = append(d.handlers[name], handler)
+}
+
+/*
+SubscribeAll registers a handler for every event, whatever its name.
+Useful for cross-cutting concerns such as logging or forwarding events.
+*/
+func (d *Dispatcher) SubscribeAll(handler Handler) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.all = append(d.all, handler)
+}
+
+/*
+Publish delivers the events, in order, to their subscribed handlers and to
+the handlers subscribed to all events.
+*/
+func (d *Dispatcher) Publish(ctx context.Context, events ...Event) {
+	for _, event := range events {
+		d.mu.RLock()
+		handlers := make([]Handler, 0, len(d.handlers[event.EventName()])+len(d.all))
+		handlers = append(handlers, d.handlers[event.EventName()]...)
+		handlers = append(handlers, d.all...)
+		d.mu.RUnlock()
+
+		for _, handler := range handlers {
+			if err := d.call(ctx, handler, event); err != nil {
+				d.logger.Error("Event handler failed", "event", event.EventName(), "error", err.Error())
+			}
+		}
+	}
+}
+
+// call runs a handler, turning a panic into an error
+func (d *Dispatcher) call(ctx context.Context, handler Handler, event Event) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+
+	return handler(ctx, event)
+}
@@ -0,0 +1,191 @@
+package events
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/platform/logger"
+)
+
+// testEvent is an event with a configurable name
+type testEvent struct {
+	name string
+}
+
+func (e testEvent) EventName() string {
+	return e.name
+}
+
+// recorder collects the calls made to its handlers
+type recorder struct {
+	calls []string
+}
+
+// handler returns a Handler that records "<label>:<event name>" and then returns err
+func (r *recorder) handler(label string, err error) Handler {
+	return func(ctx context.Context, event Event) error {
+		r.calls = append(r.calls, label+":"+event.EventName())
+		return err
+	}
+}
+
+// newTestDispatcher returns a Dispatcher logging into the returned buffer
+func newTestDispatcher() (*Dispatcher, *bytes.Buffer) {
+	var out bytes.Buffer
+	return NewDispatcher(logger.NewWithOutput("error", &out)), &out
+}
+
+func TestDispatcherPublish(t *testing.T) {
+	tests := []struct {
+		name      string
+		subscribe func(d *Dispatcher, r *recorder)
+		publish   []Event
+		want      []string
+	}{
+		{
+			name:    "no subscribers",
+			publish: []Event{testEvent{"user.registered"}},
+		},
+		{
+			name: "subscription order",
+			subscribe: func(d *Dispatcher, r *recorder) {
+				d.Subscribe("user.registered", r.handler("first", nil))
+				d.Subscribe("user.registered", r.handler("second", nil))
+				d.Subscribe("user.registered", r.handler("third", nil))
+			},
+			publish: []Event{testEvent{"user.registered"}},
+			want:    []string{"first:user.registered", "second:user.registered", "third:user.registered"},
+		},
+		{
+			name: "only matching names",
+			subscribe: func(d *Dispatcher, r *recorder) {
+				d.Subscribe("user.registered", r.handler("registered", nil))
+				d.Subscribe("user.deleted", r.handler("deleted", nil))
+			},
+			publish: []Event{testEvent{"user.deleted"}},
+			want:    []string{"deleted:user.deleted"},
+		},
+		{
+			name: "events in order",
+			subscribe: func(d *Dispatcher, r *recorder) {
+				d.Subscribe("user.registered", r.handler("registered", nil))
+				d.Subscribe("user.deleted", r.handler("deleted", nil))
+			},
+			publish: []Event{testEvent{"user.deleted"}, testEvent{"user.registered"}, testEvent{"user.deleted"}},
+			want:    []string{"deleted:user.deleted", "registered:user.registered", "deleted:user.deleted"},
+		},
+		{
+			name: "all events after named handlers",
+			subscribe: func(d *Dispatcher, r *recorder) {
+				d.SubscribeAll(r.handler("all", nil))
+				d.Subscribe("user.registered", r.handler("registered", nil))
+			},
+			publish: []Event{testEvent{"user.registered"}, testEvent{"user.deleted"}},
+			want:    []string{"registered:user.registered", "all:user.registered", "all:user.deleted"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, _ := newTestDispatcher()
+			r := &recorder{}
+			if tt.subscribe != nil {
+				tt.subscribe(d, r)
+			}
+
+			d.Publish(context.Background(), tt.publish...)
+
+			if !reflect.DeepEqual(r.calls, tt.want) {
+				t.Errorf("handler calls = %v, want %v", r.calls, tt.want)
+			}
+		})
+	}
+}
+
+func TestDispatcherLogsHandlerErrors(t *testing.T) {
+	d, out := newTestDispatcher()
+	r := &recorder{}
+
+	d.Subscribe("user.registered", r.handler("failing", errors.New("smtp unavailable")))
+	d.Subscribe("user.registered", r.handler("next", nil))
+	d.SubscribeAll(r.handler("all", nil))
+
+	d.Publish(context.Background(), testEvent{"user.registered"})
+
+	want := []string{"failing:user.registered", "next:user.registered", "all:user.registered"}
+	if !reflect.DeepEqual(r.calls, want) {
+		t.Errorf("handler calls = %v, want %v", r.calls, want)
+	}
+
+	logged := out.String()
+	for _, part := range []string{"Event handler failed", "event=user.registered", "smtp unavailable"} {
+		if !strings.Contains(logged, part) {
+			t.Errorf("log = %q, want it to contain %q", logged, part)
+		}
+	}
+}
+
+func TestDispatcherRecoversPanics(t *testing.T) {
+	d, out := newTestDispatcher()
+	r := &recorder{}
+
+	d.Subscribe("user.registered", func(ctx context.Context, event Event) error {
+		panic("nil map")
+	})
+	d.Subscribe("user.registered", r.handler("next", nil))
+	d.SubscribeAll(r.handler("all", nil))
+
+	d.Publish(context.Background(), testEvent{"user.registered"}, testEvent{"user.deleted"})
+
+	want := []string{"next:user.registered", "all:user.registered", "all:user.deleted"}
+	if !reflect.DeepEqual(r.calls, want) {
+		t.Errorf("handler calls = %v, want %v", r.calls, want)
+	}
+	if logged := out.String(); !strings.Contains(logged, "panic: nil map") {
+		t.Errorf("log = %q, want it to contain the panic", logged)
+	}
+}
+
+func TestDispatcherPassesContext(t *testing.T) {
+	d, _ := newTestDispatcher()
+
+	type key struct{}
+	ctx := context.WithValue(context.Background(), key{}, "request-1")
+
+	var got interface{}
+	d.Subscribe("user.registered", func(ctx context.Context, event Event) error {
+		got = ctx.Value(key{})
+		return nil
+	})
+
+	d.Publish(ctx, testEvent{"user.registered"})
+
+	if got != "request-1" {
+		t.Errorf("handler context value = %v, want request-1", got)
+	}
+}
+
+func TestDispatcherSubscribeDuringPublish(t *testing.T) {
+	d, _ := newTestDispatcher()
+	r := &recorder{}
+
+	// A handler may subscribe further handlers; they apply from the next event
+	d.Subscribe("user.registered", func(ctx context.Context, event Event) error {
+		d.Subscribe("user.registered", r.handler("late", nil))
+		return nil
+	})
+
+	d.Publish(context.Background(), testEvent{"user.registered"})
+	if len(r.calls) != 0 {
+		t.Errorf("handler calls = %v, want none for the current event", r.calls)
+	}
+
+	d.Publish(context.Background(), testEvent{"user.registered"})
+	if want := []string{"late:user.registered"}; !reflect.DeepEqual(r.calls, want) {
+		t.Errorf("handler calls = %v, want %v", r.calls, want)
+	}
+}
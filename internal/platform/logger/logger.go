@@ -2,6 +2,7 @@ package logger
 
 import (
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"strings"
@@ -53,9 +54,17 @@ Messages below the specified level will not be logged.
 Example: New("info") will log INFO, WARN, ERROR, and FATAL, but not DEBUG.
 */
 func New(level string) *Logger {
+	return NewWithOutput(level, os.Stdout)
+}
+
+/*
+NewWithOutput creates a logger like New that writes to out instead of standard
+output, e.g. a buffer in tests.
+*/
+func NewWithOutput(level string, out io.Writer) *Logger {
 	return &Logger{
 		level:  parseLogLevel(level),
-		logger: log.New(os.Stdout, "", 0),
+		logger: log.New(out, "", 0),
 	}
 }
 
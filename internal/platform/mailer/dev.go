@@ -0,0 +1,81 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/platform/logger"
+	"github.com/google/uuid"
+)
+
+/*
+LogMailer writes emails to the application log instead of sending them.
+Intended for local development only: message bodies (which may contain
+one-time tokens) are logged in full.
+*/
+type LogMailer struct {
+	from   string
+	logger *logger.Logger
+}
+
+/*
+NewLogMailer creates a new LogMailer that logs with the given logger.
+*/
+func NewLogMailer(from string, log *logger.Logger) *LogMailer {
+	return &LogMailer{
+		from:   from,
+		logger: log,
+	}
+}
+
+/*
+Send logs the message at INFO level.
+*/
+func (m *LogMailer) Send(ctx context.Context, msg Message) error {
+	m.logger.Info("Email (not sent)",
+		"from", m.from,
+		"to", msg.To,
+		"subject", msg.Subject,
+		"body", fmt.Sprintf("%q", msg.Body),
+	)
+	return nil
+}
+
+/*
+FileMailer writes each email as an .eml file into a directory instead of sending it.
+Intended for local development: the files can be opened with any mail client.
+*/
+type FileMailer struct {
+	from string
+	dir  string
+}
+
+/*
+NewFileMailer creates a new FileMailer writing into dir.
+The directory is created on first use if it does not exist.
+*/
+func NewFileMailer(from, dir string) *FileMailer {
+	return &FileMailer{
+		from: from,
+		dir:  dir,
+	}
+}
+
+/*
+Send writes the message to <dir>/<timestamp>-<id>.eml.
+*/
+func (m *FileMailer) Send(ctx context.Context, msg Message) error {
+	if err := os.MkdirAll(m.dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create mail directory: %w", err)
+	}
+
+	name := fmt.Sprintf("%s-%s.eml", time.Now().Format("20060102T150405"), uuid.NewString())
+	if err := os.WriteFile(filepath.Join(m.dir, name), formatMessage(m.from, msg), 0o600); err != nil {
+		return fmt.Errorf("failed to write email file: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,44 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/platform/config"
+	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/platform/logger"
+)
+
+// Message represents a plain-text email
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+/*
+Mailer is the port for sending emails.
+Adapters:
+  - SMTPMailer sends through an SMTP server (production)
+  - LogMailer writes messages to the application log (local development)
+  - FileMailer writes messages as .eml files to a directory (local development)
+*/
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+/*
+New creates the Mailer selected by config.MailConfig.Driver ("smtp", "log" or "file").
+Returns an error for an unknown driver.
+*/
+func New(cfg *config.Config, log *logger.Logger) (Mailer, error) {
+	switch cfg.Mail.Driver {
+	case "smtp":
+		return NewSMTPMailer(cfg.Mail), nil
+	case "log":
+		return NewLogMailer(cfg.Mail.From, log), nil
+	case "file":
+		return NewFileMailer(cfg.Mail.From, cfg.Mail.FileDir), nil
+	default:
+		return nil, fmt.Errorf("unsupported mail driver: %s", cfg.Mail.Driver)
+	}
+}
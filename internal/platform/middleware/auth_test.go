@@ -0,0 +1,162 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/platform/auth"
+	apperrors "github.com/dzikrisyairozi/go-ddd-clean-starter/internal/platform/errors"
+	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/platform/logger"
+	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/platform/problem"
+	"github.com/gofiber/fiber/v2"
+)
+
+var (
+	tokenUser  = uuid.New()
+	apiKeyUser = uuid.New()
+)
+
+// stubTokens accepts the token "good-token"
+type stubTokens struct{}
+
+func (stubTokens) VerifyAccessToken(token string) (*auth.Principal, error) {
+	if token != "good-token" {
+		return nil, auth.ErrInvalidToken
+	}
+	return &auth.Principal{UserID: tokenUser, Role: "member"}, nil
+}
+
+// stubAPIKeys accepts "ak_good", rejects other keys and fails for "ak_unavailable"
+type stubAPIKeys struct{}
+
+func (stubAPIKeys) VerifyAPIKey(ctx context.Context, key string) (*auth.Principal, error) {
+	switch key {
+	case "ak_good":
+		return &auth.Principal{UserID: apiKeyUser, Role: "member", Scopes: []string{"users:read"}, APIKeyID: uuid.New()}, nil
+	case "ak_unavailable":
+		return nil, errors.New("connection refused")
+	default:
+		return nil, auth.ErrAPIKeyRejected
+	}
+}
+
+// newAuthApp serves /me behind the middleware, answering with the principal's user ID
+func newAuthApp(middleware fiber.Handler) *fiber.App {
+	app := fiber.New(fiber.Config{
+		ErrorHandler: problem.NewRenderer("/problems/", apperrors.NewRegistry(), logger.New("fatal")).Render,
+	})
+	app.Get("/me", middleware, func(c *fiber.Ctx) error {
+		principal, ok := auth.PrincipalFromContext(c.UserContext())
+		if !ok {
+			return c.SendString("anonymous")
+		}
+		return c.SendString(principal.UserID.String())
+	})
+	return app
+}
+
+func TestAuthenticate(t *testing.T) {
+	tests := []struct {
+		name       string
+		header     string
+		wantStatus int
+		wantUser   string
+	}{
+		{name: "bearer token", header: "Bearer good-token", wantStatus: http.StatusOK, wantUser: tokenUser.String()},
+		{name: "scheme is case insensitive", header: "bearer good-token", wantStatus: http.StatusOK, wantUser: tokenUser.String()},
+		{name: "api key", header: "ApiKey ak_good", wantStatus: http.StatusOK, wantUser: apiKeyUser.String()},
+		{name: "api key scheme is case insensitive", header: "apikey ak_good", wantStatus: http.StatusOK, wantUser: apiKeyUser.String()},
+		{name: "missing header", header: "", wantStatus: http.StatusUnauthorized},
+		{name: "invalid token", header: "Bearer bad-token", wantStatus: http.StatusUnauthorized},
+		{name: "rejected api key", header: "ApiKey ak_revoked", wantStatus: http.StatusUnauthorized},
+		{name: "api key as bearer token", header: "Bearer ak_good", wantStatus: http.StatusUnauthorized},
+		{name: "unknown scheme", header: "Basic dXNlcjpwYXNz", wantStatus: http.StatusUnauthorized},
+		{name: "missing credential", header: "ApiKey ", wantStatus: http.StatusUnauthorized},
+		{name: "api key check failed", header: "ApiKey ak_unavailable", wantStatus: http.StatusInternalServerError},
+	}
+
+	app := newAuthApp(Authenticate(stubTokens{}, stubAPIKeys{}))
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/me", nil)
+			if tt.header != "" {
+				req.Header.Set(fiber.HeaderAuthorization, tt.header)
+			}
+
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatalf("app.Test() error = %v", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+
+			challenge := resp.Header.Get(fiber.HeaderWWWAuthenticate)
+			if (tt.wantStatus == http.StatusUnauthorized) != (challenge != "") {
+				t.Errorf("WWW-Authenticate = %q with status %d", challenge, resp.StatusCode)
+			}
+
+			if tt.wantUser != "" {
+				body := make([]byte, 64)
+				n, _ := resp.Body.Read(body)
+				if got := string(body[:n]); got != tt.wantUser {
+					t.Errorf("principal = %q, want %q", got, tt.wantUser)
+				}
+			}
+		})
+	}
+}
+
+func TestAuthenticateWithoutAPIKeys(t *testing.T) {
+	app := newAuthApp(Authenticate(stubTokens{}, nil))
+
+	req := httptest.NewRequest(http.MethodGet, "/me", nil)
+	req.Header.Set(fiber.HeaderAuthorization, "ApiKey ak_good")
+
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("app.Test() error = %v", err)
+	}
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("status = %d, want %d", resp.StatusCode, http.StatusUnauthorized)
+	}
+}
+
+func TestOptionalAuthenticate(t *testing.T) {
+	tests := []struct {
+		name       string
+		header     string
+		wantStatus int
+	}{
+		{name: "anonymous", header: "", wantStatus: http.StatusOK},
+		{name: "api key", header: "ApiKey ak_good", wantStatus: http.StatusOK},
+		{name: "rejected api key", header: "ApiKey ak_revoked", wantStatus: http.StatusUnauthorized},
+	}
+
+	app := newAuthApp(OptionalAuthenticate(stubTokens{}, stubAPIKeys{}))
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/me", nil)
+			if tt.header != "" {
+				req.Header.Set(fiber.HeaderAuthorization, tt.header)
+			}
+
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatalf("app.Test() error = %v", err)
+			}
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+		})
+	}
+}
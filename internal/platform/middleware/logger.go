@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/platform/logger"
+	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/platform/requestinfo"
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
 )
@@ -12,8 +13,10 @@ import (
 RequestLogger returns a Fiber middleware that logs all HTTP requests.
 For each request, it:
   - Generates a unique request ID (stored in context as "requestID")
+  - Stores the request ID and client IP in the user context
+    (retrievable with requestinfo.FromContext(c.UserContext()))
   - Records the request start time
-  - Processes the request
+  - Processes the request, writing any returned error with the app's ErrorHandler
   - Logs request details including method, path, status, duration, and client IP
   - Uses different log levels based on response status:
   - ERROR (red) for 5xx server errors
@@ -27,12 +30,19 @@ func RequestLogger(log *logger.Logger) fiber.Handler {
 		// Generate request ID
 		requestID := uuid.New().String()
 		c.Locals("requestID", requestID)
+		c.SetUserContext(requestinfo.WithInfo(c.UserContext(), requestinfo.Info{
+			RequestID: requestID,
+			IP:        c.IP(),
+		}))
 
 		// Record start time
 		start := time.Now()
 
-		// Process request
+		// Process request, rendering any error first so its status is logged
 		err := c.Next()
+		if err != nil {
+			err = c.App().ErrorHandler(c, err)
+		}
 
 		// Calculate duration
 		duration := time.Since(start)
@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/platform/logger"
+	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/platform/problem"
 	"github.com/gofiber/fiber/v2"
 )
 
@@ -11,14 +12,14 @@ import (
 Recovery returns a Fiber middleware that recovers from panics in request handlers.
 When a panic occurs:
   - The panic is caught and logged with request details (method, path, request ID)
-  - A 500 Internal Server Error response is sent to the client
+  - A 500 Internal Server Error problem is returned for the ErrorHandler to render
   - The server continues running (prevents crash)
 
 This middleware should be registered early in the middleware chain to catch
 panics from all subsequent handlers. Critical for production stability.
 */
 func Recovery(log *logger.Logger) fiber.Handler {
-	return func(c *fiber.Ctx) error {
+	return func(c *fiber.Ctx) (err error) {
 		defer func() {
 			if r := recover(); r != nil {
 				// Log the panic
@@ -30,10 +31,8 @@ func Recovery(log *logger.Logger) fiber.Handler {
 					"panic", fmt.Sprintf("%v", r),
 				)
 
-				// Return 500 Internal Server Error
-				_ = c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-					"error": "Internal server error",
-				})
+				// Report 500 Internal Server Error
+				err = problem.Internal(fmt.Errorf("panic: %v", r))
 			}
 		}()
 
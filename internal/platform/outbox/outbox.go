@@ -0,0 +1,67 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/platform/database"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// ErrNoTransaction is returned by Append when the context carries no transaction
+var ErrNoTransaction = errors.New("outbox: append requires a transaction")
+
+/*
+Message is a domain event stored in the outbox, as it is handed to sinks.
+Its JSON form is the envelope sinks send: the event itself is in Payload.
+ID is the event's unique ID; sinks deliver at least once, so consumers should
+use it to drop duplicates.
+*/
+type Message struct {
+	ID            uuid.UUID       `json:"id"`
+	AggregateType string          `json:"aggregate_type"`
+	AggregateID   uuid.UUID       `json:"aggregate_id"`
+	EventType     string          `json:"event_type"`
+	Payload       json.RawMessage `json:"payload"`
+	OccurredAt    time.Time       `json:"occurred_at"`
+
+	// Attempts is the number of failed deliveries so far
+	Attempts int `json:"-"`
+}
+
+const insertMessage = `
+INSERT INTO outbox_events (id, aggregate_type, aggregate_id, event_type, payload, occurred_at)
+VALUES ($1, $2, $3, $4, $5, $6)
+ON CONFLICT (id) DO NOTHING`
+
+/*
+Append stores messages in the outbox using the transaction carried by ctx
+(see database.ContextWithTx), so they are committed or rolled back together
+with the change that raised them. Storing the same message twice is a no-op.
+Returns ErrNoTransaction if ctx carries no transaction.
+*/
+func Append(ctx context.Context, messages ...Message) error {
+	if len(messages) == 0 {
+		return nil
+	}
+
+	tx, ok := database.TxFromContext(ctx)
+	if !ok {
+		return ErrNoTransaction
+	}
+
+	batch := &pgx.Batch{}
+	for _, msg := range messages {
+		batch.Queue(insertMessage, msg.ID, msg.AggregateType, msg.AggregateID, msg.EventType, msg.Payload, msg.OccurredAt)
+	}
+
+	if err := tx.SendBatch(ctx, batch).Close(); err != nil {
+		return fmt.Errorf("failed to store outbox events: %w", err)
+	}
+
+	return nil
+}
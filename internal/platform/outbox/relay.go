@@ -0,0 +1,249 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/platform/logger"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// RelayConfig holds the tunable parameters of the outbox relay
+type RelayConfig struct {
+	PollInterval   time.Duration // Pause between polls when the outbox is drained
+	BatchSize      int           // Maximum number of events claimed per poll
+	LeaseDuration  time.Duration // How long claimed events are reserved for delivery by this relay
+	MaxAttempts    int           // Failed deliveries after which an event is no longer retried
+	RetryBaseDelay time.Duration // Delay before the first retry, doubled for each further failure
+	RetryMaxDelay  time.Duration // Upper bound for the retry delay
+}
+
+// claimPending leases a batch of pending events by pushing back their next attempt
+const claimPending = `
+WITH pending AS (
+    SELECT id
+    FROM outbox_events
+    WHERE published_at IS NULL AND attempts < $1 AND next_attempt_at <= NOW()
+    ORDER BY created_at
+    LIMIT $2
+    FOR UPDATE SKIP LOCKED
+), claimed AS (
+    UPDATE outbox_events e
+    SET next_attempt_at = NOW() + $3 * INTERVAL '1 millisecond'
+    FROM pending
+    WHERE e.id = pending.id
+    RETURNING e.id, e.aggregate_type, e.aggregate_id, e.event_type, e.payload, e.occurred_at, e.attempts, e.created_at
+)
+SELECT id, aggregate_type, aggregate_id, event_type, payload, occurred_at, attempts
+FROM claimed
+ORDER BY created_at`
+
+const markPublished = `
+UPDATE outbox_events
+SET published_at = NOW(), last_error = NULL
+WHERE id = $1`
+
+const markFailed = `
+UPDATE outbox_events
+SET attempts = attempts + 1, last_error = $2, next_attempt_at = NOW() + $3 * INTERVAL '1 millisecond'
+WHERE id = $1`
+
+/*
+Relay is the background worker that delivers outbox events to the sinks.
+It polls the outbox for pending events and claims each batch in one short
+statement, which leases the events by moving their next attempt LeaseDuration
+ahead, so several instances can run side by side without delivering the same
+event concurrently. The events are then delivered with no transaction open, and
+each one is marked published once every sink accepted it; a failed delivery is
+retried with exponential backoff until MaxAttempts is reached, after which the
+event stays in the outbox for inspection.
+
+Delivery is at least once: an event is delivered again if a later sink fails,
+or if the relay stops or its lease expires before the outcome is recorded.
+Events are delivered in the order they were stored, except that a retried event
+falls behind newer ones.
+*/
+type Relay struct {
+	pool   *pgxpool.Pool
+	sinks  []Sink
+	config RelayConfig
+	logger *logger.Logger
+
+	cancel   context.CancelFunc
+	stop     chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+/*
+NewRelay creates a Relay that delivers the outbox events to the sinks, in order.
+Call Start to begin polling and Stop to shut it down.
+*/
+func NewRelay(pool *pgxpool.Pool, cfg RelayConfig, log *logger.Logger, sinks ...Sink) *Relay {
+	return &Relay{
+		pool:   pool,
+		sinks:  sinks,
+		config: cfg,
+		logger: log,
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+}
+
+/*
+Start begins polling the outbox in a background goroutine.
+It must be called at most once.
+*/
+func (r *Relay) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	r.cancel = cancel
+
+	go r.run(ctx)
+}
+
+/*
+Stop asks the relay to stop and waits for the batch in progress to finish.
+If ctx expires first, the batch is cancelled; its undelivered events are
+delivered again once their lease expires. Stop returns immediately if the relay
+was never started.
+*/
+func (r *Relay) Stop(ctx context.Context) error {
+	if r.cancel == nil {
+		return nil
+	}
+
+	r.stopOnce.Do(func() { close(r.stop) })
+
+	select {
+	case <-r.done:
+		return nil
+	case <-ctx.Done():
+		r.cancel()
+		<-r.done
+		return ctx.Err()
+	}
+}
+
+// run polls the outbox until the relay is stopped
+func (r *Relay) run(ctx context.Context) {
+	defer close(r.done)
+	defer r.cancel()
+
+	r.logger.Info("Outbox relay started", "sinks", len(r.sinks))
+
+	for {
+		claimed, err := r.relayBatch(ctx)
+		if err != nil && ctx.Err() == nil {
+			r.logger.Error("Outbox relay failed", "error", err.Error())
+		}
+
+		// Keep going while there is a backlog, otherwise wait for new events
+		wait := r.config.PollInterval
+		if err == nil && claimed == r.config.BatchSize {
+			wait = 0
+		}
+
+		select {
+		case <-r.stop:
+			r.logger.Info("Outbox relay stopped")
+			return
+		case <-time.After(wait):
+		}
+	}
+}
+
+/*
+relayBatch claims a batch of pending events, delivers them and records the
+outcome of each delivery in its own statement. Nothing is locked while the sinks
+are called. Returns the number of events claimed.
+*/
+func (r *Relay) relayBatch(ctx context.Context) (int, error) {
+	messages, err := r.claim(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	// Outcomes of finished deliveries are recorded even while the relay stops
+	recordCtx := context.WithoutCancel(ctx)
+
+	for _, msg := range messages {
+		// Leave the rest of the batch to be claimed again once its lease expires
+		if ctx.Err() != nil {
+			return len(messages), ctx.Err()
+		}
+
+		if err := r.deliver(ctx, msg); err != nil {
+			r.recordFailure(msg, err)
+			if _, err := r.pool.Exec(recordCtx, markFailed, msg.ID, err.Error(), r.backoff(msg.Attempts+1).Milliseconds()); err != nil {
+				return len(messages), fmt.Errorf("failed to record outbox delivery failure: %w", err)
+			}
+			continue
+		}
+
+		if _, err := r.pool.Exec(recordCtx, markPublished, msg.ID); err != nil {
+			return len(messages), fmt.Errorf("failed to mark outbox event as published: %w", err)
+		}
+	}
+
+	return len(messages), nil
+}
+
+// claim leases up to BatchSize pending events, oldest first
+func (r *Relay) claim(ctx context.Context) ([]Message, error) {
+	rows, err := r.pool.Query(ctx, claimPending, r.config.MaxAttempts, r.config.BatchSize, r.config.LeaseDuration.Milliseconds())
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim outbox events: %w", err)
+	}
+	messages, err := pgx.CollectRows(rows, scanMessage)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read outbox events: %w", err)
+	}
+	return messages, nil
+}
+
+// deliver hands the message to every sink, stopping at the first failure
+func (r *Relay) deliver(ctx context.Context, msg Message) error {
+	for _, sink := range r.sinks {
+		if err := sink.Deliver(ctx, msg); err != nil {
+			return fmt.Errorf("%s sink: %w", sink.Name(), err)
+		}
+	}
+	return nil
+}
+
+// recordFailure logs a failed delivery, as an error once the event is given up on
+func (r *Relay) recordFailure(msg Message, err error) {
+	attempts := msg.Attempts + 1
+	if r.givesUp(attempts) {
+		r.logger.Error("Giving up on outbox event", "event_id", msg.ID.String(), "event", msg.EventType, "attempts", attempts, "error", err.Error())
+		return
+	}
+	r.logger.Warn("Outbox event delivery failed", "event_id", msg.ID.String(), "event", msg.EventType, "attempts", attempts, "error", err.Error())
+}
+
+// givesUp reports whether an event that failed the given number of times is no longer retried
+func (r *Relay) givesUp(attempts int) bool {
+	return attempts >= r.config.MaxAttempts
+}
+
+// backoff returns the delay before retrying an event that failed the given number of times
+func (r *Relay) backoff(attempts int) time.Duration {
+	delay := r.config.RetryBaseDelay
+	for i := 1; i < attempts && delay < r.config.RetryMaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > r.config.RetryMaxDelay {
+		return r.config.RetryMaxDelay
+	}
+	return delay
+}
+
+// scanMessage reads one row of claimPending
+func scanMessage(row pgx.CollectableRow) (Message, error) {
+	var msg Message
+	err := row.Scan(&msg.ID, &msg.AggregateType, &msg.AggregateID, &msg.EventType, &msg.Payload, &msg.OccurredAt, &msg.Attempts)
+	return msg, err
+}
@@ -0,0 +1,225 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/platform/database"
+	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/platform/database/dbtest"
+	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/platform/logger"
+	"github.com/google/uuid"
+)
+
+func TestRelayBackoff(t *testing.T) {
+	r := NewRelay(nil, RelayConfig{RetryBaseDelay: time.Second, RetryMaxDelay: 10 * time.Second}, logger.New("error"))
+
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{1, time.Second},
+		{2, 2 * time.Second},
+		{3, 4 * time.Second},
+		{4, 8 * time.Second},
+		{5, 10 * time.Second},
+		{100, 10 * time.Second},
+	}
+
+	for _, tt := range tests {
+		if got := r.backoff(tt.attempts); got != tt.want {
+			t.Errorf("backoff(%d) = %v, want %v", tt.attempts, got, tt.want)
+		}
+	}
+}
+
+func TestRelayGivesUp(t *testing.T) {
+	r := NewRelay(nil, RelayConfig{MaxAttempts: 3}, logger.New("error"))
+
+	for attempts, want := range map[int]bool{1: false, 2: false, 3: true, 4: true} {
+		if got := r.givesUp(attempts); got != want {
+			t.Errorf("givesUp(%d) = %v, want %v", attempts, got, want)
+		}
+	}
+}
+
+/*
+TestRelayBatch runs against Postgres through dbtest and is skipped unless
+TEST_DATABASE_URL is set (see make test-db). The relay works on committed rows,
+so the subtests share the schema and run in order.
+*/
+func TestRelayBatch(t *testing.T) {
+	db := dbtest.Open(t)
+	ctx := context.Background()
+
+	sink := &recordingSink{}
+	relay := NewRelay(db.Pool, RelayConfig{
+		BatchSize:      10,
+		LeaseDuration:  time.Minute,
+		MaxAttempts:    2,
+		RetryBaseDelay: time.Hour,
+		RetryMaxDelay:  time.Hour,
+	}, logger.New("error"), sink)
+
+	first := appendMessage(t, db)
+	second := appendMessage(t, db)
+
+	t.Run("claim leases pending events oldest first", func(t *testing.T) {
+		claimed, err := relay.claim(ctx)
+		if err != nil {
+			t.Fatalf("claim() error = %v", err)
+		}
+		if got := messageIDs(claimed); !reflect.DeepEqual(got, []uuid.UUID{first, second}) {
+			t.Fatalf("claim() = %v, want %v", got, []uuid.UUID{first, second})
+		}
+
+		// Leased events are not claimed again, e.g. by another relay
+		claimed, err = relay.claim(ctx)
+		if err != nil {
+			t.Fatalf("claim() error = %v", err)
+		}
+		if len(claimed) != 0 {
+			t.Errorf("claim() of leased events = %v, want none", messageIDs(claimed))
+		}
+
+		expireLeases(t, db)
+	})
+
+	t.Run("delivered events are published", func(t *testing.T) {
+		claimed, err := relay.relayBatch(ctx)
+		if err != nil || claimed != 2 {
+			t.Fatalf("relayBatch() = %d, %v, want 2, nil", claimed, err)
+		}
+		if !reflect.DeepEqual(sink.delivered, []uuid.UUID{first, second}) {
+			t.Errorf("sink received %v, want %v", sink.delivered, []uuid.UUID{first, second})
+		}
+		for _, id := range []uuid.UUID{first, second} {
+			if state := loadState(t, db, id); !state.published || state.attempts != 0 {
+				t.Errorf("event %s = %+v, want published without failures", id, state)
+			}
+		}
+
+		expireLeases(t, db)
+		if claimed, err := relay.relayBatch(ctx); claimed != 0 || err != nil {
+			t.Errorf("relayBatch() of published events = %d, %v, want 0, nil", claimed, err)
+		}
+	})
+
+	t.Run("failed deliveries are retried until max attempts", func(t *testing.T) {
+		sink.fail = errors.New("connection refused")
+		failing := appendMessage(t, db)
+
+		for attempt := 1; attempt <= 2; attempt++ {
+			if claimed, err := relay.relayBatch(ctx); claimed != 1 || err != nil {
+				t.Fatalf("attempt %d: relayBatch() = %d, %v, want 1, nil", attempt, claimed, err)
+			}
+
+			state := loadState(t, db, failing)
+			if state.published || state.attempts != attempt || !strings.Contains(state.lastError, "connection refused") {
+				t.Errorf("attempt %d: event = %+v", attempt, state)
+			}
+			// The retry waits for the backoff
+			if !state.scheduled {
+				t.Errorf("attempt %d: next attempt is not in the future", attempt)
+			}
+			if claimed, err := relay.relayBatch(ctx); claimed != 0 || err != nil {
+				t.Errorf("attempt %d: relayBatch() during backoff = %d, %v, want 0, nil", attempt, claimed, err)
+			}
+
+			expireLeases(t, db)
+		}
+
+		// Given up after MaxAttempts: the event stays unpublished and is no longer claimed
+		if claimed, err := relay.relayBatch(ctx); claimed != 0 || err != nil {
+			t.Errorf("relayBatch() after max attempts = %d, %v, want 0, nil", claimed, err)
+		}
+		if state := loadState(t, db, failing); state.published || state.attempts != 2 {
+			t.Errorf("event = %+v, want 2 failed attempts", state)
+		}
+	})
+}
+
+// recordingSink records the IDs of delivered messages, or fails every delivery if fail is set
+type recordingSink struct {
+	fail      error
+	delivered []uuid.UUID
+}
+
+func (s *recordingSink) Name() string { return "recording" }
+
+func (s *recordingSink) Deliver(ctx context.Context, msg Message) error {
+	if s.fail != nil {
+		return s.fail
+	}
+	s.delivered = append(s.delivered, msg.ID)
+	return nil
+}
+
+// eventState is the delivery state of a stored event
+type eventState struct {
+	attempts  int
+	lastError string
+	published bool
+	scheduled bool // next attempt is in the future
+}
+
+// appendMessage stores a new event in its own committed transaction and returns its ID
+func appendMessage(t *testing.T, db *dbtest.DB) uuid.UUID {
+	t.Helper()
+
+	msg := Message{
+		ID:            uuid.New(),
+		AggregateType: "user",
+		AggregateID:   uuid.New(),
+		EventType:     "user.registered",
+		Payload:       json.RawMessage(`{"name":"Jane"}`),
+		OccurredAt:    time.Now(),
+	}
+	err := database.NewTxManager(db.Pool).Do(context.Background(), func(ctx context.Context) error {
+		return Append(ctx, msg)
+	})
+	if err != nil {
+		t.Fatalf("Append() error = %v", err)
+	}
+	return msg.ID
+}
+
+// expireLeases makes every unpublished event due, as if its lease or backoff had passed
+func expireLeases(t *testing.T, db *dbtest.DB) {
+	t.Helper()
+
+	if _, err := db.Pool.Exec(context.Background(), "UPDATE outbox_events SET next_attempt_at = NOW() WHERE published_at IS NULL"); err != nil {
+		t.Fatalf("failed to expire leases: %v", err)
+	}
+}
+
+// loadState reads the delivery state of an event
+func loadState(t *testing.T, db *dbtest.DB, id uuid.UUID) eventState {
+	t.Helper()
+
+	var state eventState
+	var lastError *string
+	err := db.Pool.QueryRow(context.Background(),
+		"SELECT attempts, last_error, published_at IS NOT NULL, next_attempt_at > NOW() FROM outbox_events WHERE id = $1", id,
+	).Scan(&state.attempts, &lastError, &state.published, &state.scheduled)
+	if err != nil {
+		t.Fatalf("failed to load outbox event %s: %v", id, err)
+	}
+	if lastError != nil {
+		state.lastError = *lastError
+	}
+	return state
+}
+
+// messageIDs returns the IDs of the messages
+func messageIDs(messages []Message) []uuid.UUID {
+	ids := make([]uuid.UUID, len(messages))
+	for i, msg := range messages {
+		ids[i] = msg.ID
+	}
+	return ids
+}
@@ -0,0 +1,246 @@
+package outbox
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/platform/config"
+	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/platform/logger"
+)
+
+/*
+Sink is the port the relay delivers outbox events to.
+Adapters:
+  - LogSink writes events to the application log (local development)
+  - WebhookSink POSTs events to an HTTP endpoint
+  - NATSSink publishes events through a NATS connection
+  - KafkaSink produces events through a Kafka client
+
+Deliver must return an error unless the sink accepted the message, so the
+relay can retry it. It may be called again for a message it already accepted.
+*/
+type Sink interface {
+	Name() string
+	Deliver(ctx context.Context, msg Message) error
+}
+
+/*
+NewSinks creates the sinks listed in config.OutboxConfig.Sinks, a comma-separated
+list of "log" and "webhook". NATS and Kafka sinks need a client connection and
+are created with NewNATSSink and NewKafkaSink instead.
+Returns an error for an unknown sink.
+*/
+func NewSinks(cfg *config.Config, log *logger.Logger) ([]Sink, error) {
+	var sinks []Sink
+	for _, name := range strings.Split(cfg.Outbox.Sinks, ",") {
+		switch strings.TrimSpace(name) {
+		case "":
+		case "log":
+			sinks = append(sinks, NewLogSink(log))
+		case "webhook":
+			sinks = append(sinks, NewWebhookSink(cfg.Outbox.WebhookURL, cfg.Outbox.WebhookSecret, cfg.Outbox.WebhookTimeout))
+		default:
+			return nil, fmt.Errorf("unsupported outbox sink: %s", name)
+		}
+	}
+	return sinks, nil
+}
+
+/*
+LogSink writes outbox events to the application log instead of delivering them.
+Intended for local development.
+*/
+type LogSink struct {
+	logger *logger.Logger
+}
+
+/*
+NewLogSink creates a new LogSink that logs with the given logger.
+*/
+func NewLogSink(log *logger.Logger) *LogSink {
+	return &LogSink{logger: log}
+}
+
+// Name returns the name of the sink
+func (s *LogSink) Name() string { return "log" }
+
+/*
+Deliver logs the message at INFO level.
+*/
+func (s *LogSink) Deliver(ctx context.Context, msg Message) error {
+	s.logger.Info("Outbox event",
+		"event_id", msg.ID.String(),
+		"event", msg.EventType,
+		"aggregate_type", msg.AggregateType,
+		"aggregate_id", msg.AggregateID.String(),
+		"payload", string(msg.Payload),
+	)
+	return nil
+}
+
+/*
+WebhookSink POSTs each outbox event as JSON to an HTTP endpoint.
+The body is the Message envelope. Requests carry the X-Event-ID and X-Event-Type
+headers and, if a secret is configured, X-Signature-256: sha256=<hex HMAC-SHA256
+of the body>, so the receiver can check the request came from this service.
+Any status other than 2xx is a failed delivery.
+*/
+type WebhookSink struct {
+	url    string
+	secret []byte
+	client *http.Client
+}
+
+/*
+NewWebhookSink creates a WebhookSink posting to url.
+The secret is optional; requests time out after timeout.
+*/
+func NewWebhookSink(url, secret string, timeout time.Duration) *WebhookSink {
+	return &WebhookSink{
+		url:    url,
+		secret: []byte(secret),
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+// Name returns the name of the sink
+func (s *WebhookSink) Name() string { return "webhook" }
+
+/*
+Deliver posts the message to the webhook URL.
+*/
+func (s *WebhookSink) Deliver(ctx context.Context, msg Message) error {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("failed to encode event: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Event-ID", msg.ID.String())
+	req.Header.Set("X-Event-Type", msg.EventType)
+	if len(s.secret) > 0 {
+		mac := hmac.New(sha256.New, s.secret)
+		mac.Write(body)
+		req.Header.Set("X-Signature-256", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+/*
+NATSPublisher is the part of a NATS client the NATSSink needs.
+*nats.Conn from github.com/nats-io/nats.go satisfies it.
+*/
+type NATSPublisher interface {
+	Publish(subject string, data []byte) error
+}
+
+/*
+NATSSink publishes each outbox event as JSON to a NATS subject made of a prefix
+and the event type (e.g. "events." gives "events.user.registered").
+*/
+type NATSSink struct {
+	conn          NATSPublisher
+	subjectPrefix string
+}
+
+/*
+NewNATSSink creates a NATSSink publishing through conn.
+*/
+func NewNATSSink(conn NATSPublisher, subjectPrefix string) *NATSSink {
+	return &NATSSink{
+		conn:          conn,
+		subjectPrefix: subjectPrefix,
+	}
+}
+
+// Name returns the name of the sink
+func (s *NATSSink) Name() string { return "nats" }
+
+/*
+Deliver publishes the message envelope to the event's subject.
+*/
+func (s *NATSSink) Deliver(ctx context.Context, msg Message) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("failed to encode event: %w", err)
+	}
+
+	if err := s.conn.Publish(s.subjectPrefix+msg.EventType, data); err != nil {
+		return fmt.Errorf("failed to publish to nats: %w", err)
+	}
+
+	return nil
+}
+
+/*
+KafkaProducer is the port the KafkaSink produces records through.
+Adapt the Kafka client of your choice to it (e.g. a kafka-go Writer or a franz-go client);
+Produce must only return once the broker acknowledged the record.
+*/
+type KafkaProducer interface {
+	Produce(ctx context.Context, topic string, key, value []byte) error
+}
+
+/*
+KafkaSink produces each outbox event as JSON to a Kafka topic.
+Records are keyed by aggregate ID, so the events of one aggregate land in the
+same partition.
+*/
+type KafkaSink struct {
+	producer KafkaProducer
+	topic    string
+}
+
+/*
+NewKafkaSink creates a KafkaSink producing to topic.
+*/
+func NewKafkaSink(producer KafkaProducer, topic string) *KafkaSink {
+	return &KafkaSink{
+		producer: producer,
+		topic:    topic,
+	}
+}
+
+// Name returns the name of the sink
+func (s *KafkaSink) Name() string { return "kafka" }
+
+/*
+Deliver produces the message envelope keyed by aggregate ID.
+*/
+func (s *KafkaSink) Deliver(ctx context.Context, msg Message) error {
+	value, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("failed to encode event: %w", err)
+	}
+
+	if err := s.producer.Produce(ctx, s.topic, []byte(msg.AggregateID.String()), value); err != nil {
+		return fmt.Errorf("failed to produce to kafka: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,201 @@
+package password
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/platform/config"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Supported hashing algorithms
+const (
+	AlgorithmBcrypt   = "bcrypt"
+	AlgorithmArgon2id = "argon2id"
+)
+
+var (
+	// ErrMismatch indicates that a password does not match the stored hash
+	ErrMismatch = errors.New("password does not match")
+
+	// ErrUnknownHashFormat indicates that a stored hash was not produced by a supported algorithm
+	ErrUnknownHashFormat = errors.New("unknown password hash format")
+)
+
+// argon2id output sizes in bytes
+const (
+	argon2SaltLength = 16
+	argon2KeyLength  = 32
+)
+
+// Argon2Params holds the argon2id cost parameters
+type Argon2Params struct {
+	Memory      uint32 // Memory in KiB
+	Iterations  uint32
+	Parallelism uint8
+}
+
+/*
+Hasher hashes passwords with the configured algorithm and verifies hashes from
+any supported algorithm. The algorithm and its parameters are encoded in every
+hash, so they can be changed without invalidating stored passwords:
+  - bcrypt: the standard modular crypt format, e.g. $2a$10$...
+  - argon2id: the PHC string format, e.g. $argon2id$v=19$m=65536,t=3,p=2$salt$hash
+
+NeedsRehash reports hashes that were made with another algorithm or outdated
+parameters, so callers can upgrade them when the plain password is at hand.
+*/
+type Hasher struct {
+	algorithm  string
+	bcryptCost int
+	argon2     Argon2Params
+}
+
+/*
+NewHasher creates a Hasher from the password configuration.
+Returns an error if the algorithm is unsupported or its parameters are out of range.
+*/
+func NewHasher(cfg *config.Config) (*Hasher, error) {
+	h := &Hasher{
+		algorithm:  cfg.Password.HashAlgorithm,
+		bcryptCost: int(cfg.Password.BcryptCost),
+		argon2: Argon2Params{
+			Memory:      uint32(cfg.Password.Argon2Memory),
+			Iterations:  uint32(cfg.Password.Argon2Iterations),
+			Parallelism: uint8(cfg.Password.Argon2Parallelism),
+		},
+	}
+
+	switch h.algorithm {
+	case AlgorithmBcrypt:
+		if h.bcryptCost < bcrypt.MinCost || h.bcryptCost > bcrypt.MaxCost {
+			return nil, fmt.Errorf("bcrypt cost must be between %d and %d", bcrypt.MinCost, bcrypt.MaxCost)
+		}
+	case AlgorithmArgon2id:
+		if h.argon2.Memory < 8*uint32(h.argon2.Parallelism) || h.argon2.Iterations < 1 || h.argon2.Parallelism < 1 {
+			return nil, fmt.Errorf("invalid argon2id parameters")
+		}
+	default:
+		return nil, fmt.Errorf("unsupported password hash algorithm: %s", h.algorithm)
+	}
+
+	return h, nil
+}
+
+// Hash hashes the password with the configured algorithm and parameters
+func (h *Hasher) Hash(password string) (string, error) {
+	if h.algorithm == AlgorithmBcrypt {
+		hash, err := bcrypt.GenerateFromPassword([]byte(password), h.bcryptCost)
+		if err != nil {
+			return "", err
+		}
+		return string(hash), nil
+	}
+
+	salt := make([]byte, argon2SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", fmt.Errorf("failed to generate salt: %w", err)
+	}
+
+	return encodeArgon2id(h.argon2, salt, argon2id(password, salt, h.argon2, argon2KeyLength)), nil
+}
+
+/*
+Verify checks a password against a hash made by any supported algorithm.
+Returns ErrMismatch if the password is wrong, or ErrUnknownHashFormat if the
+hash cannot be parsed.
+*/
+func (h *Hasher) Verify(encodedHash, password string) error {
+	if isBcrypt(encodedHash) {
+		err := bcrypt.CompareHashAndPassword([]byte(encodedHash), []byte(password))
+		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+			return ErrMismatch
+		}
+		return err
+	}
+
+	params, salt, key, err := decodeArgon2id(encodedHash)
+	if err != nil {
+		return err
+	}
+
+	if subtle.ConstantTimeCompare(key, argon2id(password, salt, params, uint32(len(key)))) != 1 {
+		return ErrMismatch
+	}
+	return nil
+}
+
+// NeedsRehash reports whether the hash was made with another algorithm or other parameters than configured
+func (h *Hasher) NeedsRehash(encodedHash string) bool {
+	if isBcrypt(encodedHash) {
+		if h.algorithm != AlgorithmBcrypt {
+			return true
+		}
+		cost, err := bcrypt.Cost([]byte(encodedHash))
+		return err != nil || cost != h.bcryptCost
+	}
+
+	if h.algorithm != AlgorithmArgon2id {
+		return true
+	}
+	params, _, key, err := decodeArgon2id(encodedHash)
+	return err != nil || params != h.argon2 || len(key) != argon2KeyLength
+}
+
+// isBcrypt reports whether the hash uses the bcrypt modular crypt format
+func isBcrypt(encodedHash string) bool {
+	return strings.HasPrefix(encodedHash, "$2a$") ||
+		strings.HasPrefix(encodedHash, "$2b$") ||
+		strings.HasPrefix(encodedHash, "$2y$")
+}
+
+// argon2id derives a key of the given length from the password
+func argon2id(password string, salt []byte, params Argon2Params, keyLength uint32) []byte {
+	return argon2.IDKey([]byte(password), salt, params.Iterations, params.Memory, params.Parallelism, keyLength)
+}
+
+// encodeArgon2id formats an argon2id hash as a PHC string
+func encodeArgon2id(params Argon2Params, salt, key []byte) string {
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version,
+		params.Memory, params.Iterations, params.Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	)
+}
+
+// decodeArgon2id parses a PHC string produced by encodeArgon2id
+func decodeArgon2id(encodedHash string) (Argon2Params, []byte, []byte, error) {
+	var params Argon2Params
+
+	parts := strings.Split(encodedHash, "$")
+	if len(parts) != 6 || parts[0] != "" || parts[1] != AlgorithmArgon2id {
+		return params, nil, nil, ErrUnknownHashFormat
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return params, nil, nil, ErrUnknownHashFormat
+	}
+
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.Memory, &params.Iterations, &params.Parallelism); err != nil {
+		return params, nil, nil, ErrUnknownHashFormat
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return params, nil, nil, ErrUnknownHashFormat
+	}
+
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(key) == 0 {
+		return params, nil, nil, ErrUnknownHashFormat
+	}
+
+	return params, salt, key, nil
+}
@@ -0,0 +1,173 @@
+package password
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/platform/config"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Cheap parameters keep the tests fast
+var testArgon2 = Argon2Params{Memory: 64, Iterations: 1, Parallelism: 1}
+
+// newTestHasher creates a Hasher for the algorithm with the given bcrypt cost and argon2id parameters
+func newTestHasher(t *testing.T, algorithm string, bcryptCost int, params Argon2Params) *Hasher {
+	t.Helper()
+
+	hasher, err := NewHasher(&config.Config{Password: config.PasswordConfig{
+		HashAlgorithm:     algorithm,
+		BcryptCost:        int32(bcryptCost),
+		Argon2Memory:      int32(params.Memory),
+		Argon2Iterations:  int32(params.Iterations),
+		Argon2Parallelism: int32(params.Parallelism),
+	}})
+	if err != nil {
+		t.Fatalf("NewHasher() error = %v", err)
+	}
+	return hasher
+}
+
+func TestArgon2idEncodingRoundTrip(t *testing.T) {
+	salt := []byte("0123456789abcdef")
+	key := bytes.Repeat([]byte{0xab}, argon2KeyLength)
+
+	encoded := encodeArgon2id(testArgon2, salt, key)
+	if want := "$argon2id$v=19$m=64,t=1,p=1$"; !strings.HasPrefix(encoded, want) {
+		t.Errorf("encodeArgon2id() = %s, want prefix %s", encoded, want)
+	}
+
+	params, gotSalt, gotKey, err := decodeArgon2id(encoded)
+	if err != nil {
+		t.Fatalf("decodeArgon2id() error = %v", err)
+	}
+	if params != testArgon2 || !bytes.Equal(gotSalt, salt) || !bytes.Equal(gotKey, key) {
+		t.Errorf("decodeArgon2id() = %+v, %x, %x, want %+v, %x, %x", params, gotSalt, gotKey, testArgon2, salt, key)
+	}
+}
+
+func TestHasherVerify(t *testing.T) {
+	hashers := []struct {
+		algorithm string
+		prefix    string
+		hasher    *Hasher
+	}{
+		{AlgorithmBcrypt, "$2a$", newTestHasher(t, AlgorithmBcrypt, bcrypt.MinCost, testArgon2)},
+		{AlgorithmArgon2id, "$argon2id$", newTestHasher(t, AlgorithmArgon2id, bcrypt.MinCost, testArgon2)},
+	}
+
+	for _, h := range hashers {
+		hash, err := h.hasher.Hash("correct horse")
+		if err != nil {
+			t.Fatalf("%s: Hash() error = %v", h.algorithm, err)
+		}
+		if !strings.HasPrefix(hash, h.prefix) {
+			t.Errorf("%s: Hash() = %s, want prefix %s", h.algorithm, hash, h.prefix)
+		}
+
+		// Every hasher verifies hashes of either algorithm
+		for _, verifier := range hashers {
+			if err := verifier.hasher.Verify(hash, "correct horse"); err != nil {
+				t.Errorf("%s hash: Verify() of the right password error = %v", h.algorithm, err)
+			}
+			if err := verifier.hasher.Verify(hash, "wrong horse"); !errors.Is(err, ErrMismatch) {
+				t.Errorf("%s hash: Verify() of a wrong password error = %v, want ErrMismatch", h.algorithm, err)
+			}
+		}
+	}
+}
+
+func TestHasherSaltsEveryHash(t *testing.T) {
+	hasher := newTestHasher(t, AlgorithmArgon2id, bcrypt.MinCost, testArgon2)
+
+	first, _ := hasher.Hash("correct horse")
+	second, _ := hasher.Hash("correct horse")
+	if first == second {
+		t.Error("hashing the same password twice gave the same hash")
+	}
+}
+
+func TestHasherVerifyUnknownFormat(t *testing.T) {
+	hasher := newTestHasher(t, AlgorithmArgon2id, bcrypt.MinCost, testArgon2)
+
+	for _, hash := range []string{
+		"",
+		"plaintext",
+		"$argon2i$v=19$m=64,t=1,p=1$c2FsdA$a2V5",
+		"$argon2id$v=16$m=64,t=1,p=1$c2FsdA$a2V5",
+		"$argon2id$v=19$m=x,t=1,p=1$c2FsdA$a2V5",
+		"$argon2id$v=19$m=64,t=1,p=1$not base64$a2V5",
+		"$argon2id$v=19$m=64,t=1,p=1$c2FsdA$",
+		"$argon2id$v=19$m=64,t=1,p=1$c2FsdA",
+	} {
+		if err := hasher.Verify(hash, "password"); !errors.Is(err, ErrUnknownHashFormat) {
+			t.Errorf("Verify(%q) error = %v, want ErrUnknownHashFormat", hash, err)
+		}
+	}
+}
+
+func TestHasherNeedsRehash(t *testing.T) {
+	bcryptHasher := newTestHasher(t, AlgorithmBcrypt, bcrypt.MinCost, testArgon2)
+	argon2Hasher := newTestHasher(t, AlgorithmArgon2id, bcrypt.MinCost, testArgon2)
+
+	bcryptHash, _ := bcryptHasher.Hash("correct horse")
+	argon2Hash, _ := argon2Hasher.Hash("correct horse")
+
+	tests := []struct {
+		name   string
+		hasher *Hasher
+		hash   string
+		want   bool
+	}{
+		{"bcrypt with current cost", bcryptHasher, bcryptHash, false},
+		{"bcrypt with another cost", newTestHasher(t, AlgorithmBcrypt, bcrypt.MinCost+1, testArgon2), bcryptHash, true},
+		{"bcrypt when argon2id is configured", argon2Hasher, bcryptHash, true},
+		{"argon2id with current parameters", argon2Hasher, argon2Hash, false},
+		{"argon2id with more memory", newTestHasher(t, AlgorithmArgon2id, bcrypt.MinCost, Argon2Params{Memory: 128, Iterations: 1, Parallelism: 1}), argon2Hash, true},
+		{"argon2id with more iterations", newTestHasher(t, AlgorithmArgon2id, bcrypt.MinCost, Argon2Params{Memory: 64, Iterations: 2, Parallelism: 1}), argon2Hash, true},
+		{"argon2id with more parallelism", newTestHasher(t, AlgorithmArgon2id, bcrypt.MinCost, Argon2Params{Memory: 64, Iterations: 1, Parallelism: 2}), argon2Hash, true},
+		{"argon2id with another key length", argon2Hasher, encodeArgon2id(testArgon2, []byte("salt"), []byte("short key")), true},
+		{"argon2id when bcrypt is configured", bcryptHasher, argon2Hash, true},
+		{"unknown format", argon2Hasher, "plaintext", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.hasher.NeedsRehash(tt.hash); got != tt.want {
+				t.Errorf("NeedsRehash() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewHasherInvalidConfig(t *testing.T) {
+	tests := []struct {
+		name       string
+		algorithm  string
+		bcryptCost int
+		params     Argon2Params
+	}{
+		{"unsupported algorithm", "md5", bcrypt.MinCost, testArgon2},
+		{"bcrypt cost too low", AlgorithmBcrypt, bcrypt.MinCost - 1, testArgon2},
+		{"bcrypt cost too high", AlgorithmBcrypt, bcrypt.MaxCost + 1, testArgon2},
+		{"argon2id without iterations", AlgorithmArgon2id, bcrypt.MinCost, Argon2Params{Memory: 64, Parallelism: 1}},
+		{"argon2id memory below 8 KiB per lane", AlgorithmArgon2id, bcrypt.MinCost, Argon2Params{Memory: 15, Iterations: 1, Parallelism: 2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewHasher(&config.Config{Password: config.PasswordConfig{
+				HashAlgorithm:     tt.algorithm,
+				BcryptCost:        int32(tt.bcryptCost),
+				Argon2Memory:      int32(tt.params.Memory),
+				Argon2Iterations:  int32(tt.params.Iterations),
+				Argon2Parallelism: int32(tt.params.Parallelism),
+			}})
+			if err == nil {
+				t.Error("NewHasher() error = nil, want an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,177 @@
+package password
+
+import (
+	"bufio"
+	_ "embed"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/platform/config"
+	apperrors "github.com/dzikrisyairozi/go-ddd-clean-starter/internal/platform/errors"
+)
+
+// MaxBytes is the longest password bcrypt can hash; it ignores any bytes past it
+const MaxBytes = 72
+
+// minPersonalTokenLength skips short name parts that would match too many passwords
+const minPersonalTokenLength = 4
+
+//go:embed common_passwords.txt
+var commonPasswords string
+
+/*
+Policy checks new passwords against configurable rules:
+  - Minimum length in characters and maximum length in bytes (at most MaxBytes)
+  - Required character classes (uppercase, lowercase, digit, symbol)
+  - Not containing the user's email address or name
+  - Not appearing in the common-passwords list
+*/
+type Policy struct {
+	minLength          int
+	maxLength          int
+	requireUppercase   bool
+	requireLowercase   bool
+	requireDigit       bool
+	requireSymbol      bool
+	rejectPersonalInfo bool
+	common             map[string]struct{}
+}
+
+/*
+NewPolicy creates a Policy from the password configuration.
+The built-in common-passwords list is used when common passwords are rejected;
+passwords from the configured blocklist file, if any, are added to it.
+Returns an error if the blocklist file cannot be read.
+*/
+func NewPolicy(cfg *config.Config) (*Policy, error) {
+	p := &Policy{
+		minLength:          int(cfg.Password.MinLength),
+		maxLength:          int(cfg.Password.MaxLength),
+		requireUppercase:   cfg.Password.RequireUppercase,
+		requireLowercase:   cfg.Password.RequireLowercase,
+		requireDigit:       cfg.Password.RequireDigit,
+		requireSymbol:      cfg.Password.RequireSymbol,
+		rejectPersonalInfo: cfg.Password.RejectPersonalInfo,
+	}
+
+	if p.maxLength <= 0 || p.maxLength > MaxBytes {
+		p.maxLength = MaxBytes
+	}
+
+	if !cfg.Password.RejectCommon {
+		return p, nil
+	}
+
+	p.common = make(map[string]struct{})
+	addPasswords(p.common, strings.NewReader(commonPasswords))
+
+	if cfg.Password.BlocklistFile != "" {
+		file, err := os.Open(cfg.Password.BlocklistFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open password blocklist %s: %w", cfg.Password.BlocklistFile, err)
+		}
+		defer file.Close()
+
+		if err := addPasswords(p.common, file); err != nil {
+			return nil, fmt.Errorf("failed to read password blocklist %s: %w", cfg.Password.BlocklistFile, err)
+		}
+	}
+
+	return p, nil
+}
+
+/*
+Validate checks a password against the policy.
+field names the request field the password came from, and personalInfo holds
+values the password must not contain, such as the user's email address and name.
+Returns nil if the password is acceptable, or *errors.ValidationErrors listing
+every rule it breaks.
+*/
+func (p *Policy) Validate(field, password string, personalInfo ...string) error {
+	errs := apperrors.NewValidationErrors()
+
+	if utf8.RuneCountInString(password) < p.minLength {
+		errs.Add(field, fmt.Sprintf("must be at least %d characters", p.minLength))
+	}
+	if len(password) > p.maxLength {
+		errs.Add(field, fmt.Sprintf("must be at most %d bytes", p.maxLength))
+	}
+
+	var hasUpper, hasLower, hasDigit, hasSymbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsLower(r):
+			hasLower = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r) || unicode.IsSpace(r):
+			hasSymbol = true
+		}
+	}
+	if p.requireUppercase && !hasUpper {
+		errs.Add(field, "must contain an uppercase letter")
+	}
+	if p.requireLowercase && !hasLower {
+		errs.Add(field, "must contain a lowercase letter")
+	}
+	if p.requireDigit && !hasDigit {
+		errs.Add(field, "must contain a digit")
+	}
+	if p.requireSymbol && !hasSymbol {
+		errs.Add(field, "must contain a symbol")
+	}
+
+	lowered := strings.ToLower(password)
+
+	if p.rejectPersonalInfo && containsPersonalInfo(lowered, personalInfo) {
+		errs.Add(field, "must not contain your email address or name")
+	}
+
+	if _, ok := p.common[lowered]; ok {
+		errs.Add(field, "is too common")
+	}
+
+	if errs.HasErrors() {
+		return errs
+	}
+	return nil
+}
+
+// containsPersonalInfo reports whether the lowercased password contains any of the values or their parts
+func containsPersonalInfo(lowered string, values []string) bool {
+	for _, value := range values {
+		value = strings.ToLower(value)
+
+		// Check the email local part and each word of the name on their own
+		tokens := strings.FieldsFunc(value, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		})
+		tokens = append(tokens, value)
+
+		for _, token := range tokens {
+			if utf8.RuneCountInString(token) >= minPersonalTokenLength && strings.Contains(lowered, token) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// addPasswords adds one lowercased password per line to the set, skipping blanks and # comments
+func addPasswords(set map[string]struct{}, r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		set[strings.ToLower(line)] = struct{}{}
+	}
+	return scanner.Err()
+}
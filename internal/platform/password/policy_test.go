@@ -0,0 +1,212 @@
+package password
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/platform/config"
+	apperrors "github.com/dzikrisyairozi/go-ddd-clean-starter/internal/platform/errors"
+)
+
+// newTestPolicy creates a Policy from the given password configuration
+func newTestPolicy(t *testing.T, cfg config.PasswordConfig) *Policy {
+	t.Helper()
+
+	policy, err := NewPolicy(&config.Config{Password: cfg})
+	if err != nil {
+		t.Fatalf("NewPolicy() error = %v", err)
+	}
+	return policy
+}
+
+// violations returns the messages of the validation errors returned by Validate
+func violations(t *testing.T, err error) []string {
+	t.Helper()
+
+	if err == nil {
+		return nil
+	}
+	var errs *apperrors.ValidationErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("Validate() error = %v, want *ValidationErrors", err)
+	}
+
+	messages := make([]string, len(errs.Errors))
+	for i, e := range errs.Errors {
+		if e.Field != "password" {
+			t.Errorf("violation %q is for field %q, want password", e.Message, e.Field)
+		}
+		messages[i] = e.Message
+	}
+	return messages
+}
+
+func TestPolicyValidate(t *testing.T) {
+	policy := newTestPolicy(t, config.PasswordConfig{
+		MinLength:          8,
+		MaxLength:          72,
+		RequireUppercase:   true,
+		RequireLowercase:   true,
+		RequireDigit:       true,
+		RequireSymbol:      true,
+		RejectPersonalInfo: true,
+		RejectCommon:       true,
+	})
+	personalInfo := []string{"jdoe@example.com", "Jane Doe"}
+
+	tests := []struct {
+		name     string
+		password string
+		want     []string
+	}{
+		{"acceptable", "Str0ng!Secret", nil},
+		{"space counts as a symbol", "Str0ng Secret", nil},
+		{"non-ascii letters count as letters", "Émile7!über", nil},
+		{"missing uppercase", "str0ng!secret", []string{"must contain an uppercase letter"}},
+		{"missing lowercase", "STR0NG!SECRET", []string{"must contain a lowercase letter"}},
+		{"missing digit", "Strong!Secret", []string{"must contain a digit"}},
+		{"missing symbol", "Str0ngSecret", []string{"must contain a symbol"}},
+		{"too short", "S0!s", []string{"must be at least 8 characters"}},
+		{
+			"every class missing",
+			"        ",
+			[]string{
+				"must contain an uppercase letter",
+				"must contain a lowercase letter",
+				"must contain a digit",
+			},
+		},
+		{"contains email", "X1!jdoe@example.com", []string{"must not contain your email address or name"}},
+		{"contains email local part", "Secret!JDOE42", []string{"must not contain your email address or name"}},
+		{"contains name part", "Secret!jane42", []string{"must not contain your email address or name"}},
+		{"contains full name", "1!JANE DOEx", []string{"must not contain your email address or name"}},
+		{
+			"common password, compared case-insensitively",
+			"1Q2W3E4R",
+			[]string{"must contain a lowercase letter", "must contain a symbol", "is too common"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := violations(t, policy.Validate("password", tt.password, personalInfo...))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Validate(%q) = %q, want %q", tt.password, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPolicyValidateLength(t *testing.T) {
+	policy := newTestPolicy(t, config.PasswordConfig{MinLength: 4, MaxLength: 10})
+
+	tests := []struct {
+		name     string
+		password string
+		want     []string
+	}{
+		// The minimum counts characters, so multibyte runes count once
+		{"four two-byte runes", "éééé", nil},
+		{"three two-byte runes", "ééé", []string{"must be at least 4 characters"}},
+		// The maximum counts bytes, since bcrypt ignores bytes past 72
+		{"ten bytes", "ééééé", nil},
+		{"eleven bytes", "éééééa", []string{"must be at most 10 bytes"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := violations(t, policy.Validate("password", tt.password))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Validate(%q) = %q, want %q", tt.password, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPolicyMaxLengthIsCappedAtBcryptLimit(t *testing.T) {
+	for _, maxLength := range []int32{0, MaxBytes + 1} {
+		policy := newTestPolicy(t, config.PasswordConfig{MaxLength: maxLength})
+
+		if err := policy.Validate("password", strings.Repeat("a", MaxBytes)); err != nil {
+			t.Errorf("max %d: Validate() of %d bytes error = %v", maxLength, MaxBytes, err)
+		}
+		if err := policy.Validate("password", strings.Repeat("a", MaxBytes+1)); err == nil {
+			t.Errorf("max %d: Validate() of %d bytes error = nil, want an error", maxLength, MaxBytes+1)
+		}
+	}
+}
+
+func TestContainsPersonalInfo(t *testing.T) {
+	tests := []struct {
+		name     string
+		password string
+		values   []string
+		want     bool
+	}{
+		{"name part of minimum length", "xx-jane-xx", []string{"Jane Doe"}, true},
+		{"name parts below minimum length are ignored", "al-li-1234", []string{"Al Li"}, false},
+		{"short parts still match as a whole", "xxal lixx", []string{"Al Li"}, true},
+		{"email domain part", "myexample!", []string{"bo@example.com"}, true},
+		{"unrelated", "correct horse", []string{"bo@example.com", "Bo Li"}, false},
+		{"no values", "anything", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := containsPersonalInfo(tt.password, tt.values); got != tt.want {
+				t.Errorf("containsPersonalInfo(%q, %q) = %v, want %v", tt.password, tt.values, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPolicyPersonalInfoCanBeAllowed(t *testing.T) {
+	policy := newTestPolicy(t, config.PasswordConfig{MinLength: 1})
+
+	if err := policy.Validate("password", "jane-secret", "Jane Doe"); err != nil {
+		t.Errorf("Validate() error = %v, want nil", err)
+	}
+}
+
+func TestPolicyBlocklistFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "blocklist.txt")
+	blocklist := "# Company specific passwords\n\n  Acme-Winter-2024  \nhunter22\n"
+	if err := os.WriteFile(path, []byte(blocklist), 0o600); err != nil {
+		t.Fatalf("failed to write blocklist: %v", err)
+	}
+
+	policy := newTestPolicy(t, config.PasswordConfig{MinLength: 1, RejectCommon: true, BlocklistFile: path})
+
+	for _, password := range []string{"acme-winter-2024", "HUNTER22", "password"} {
+		if got := violations(t, policy.Validate("password", password)); !reflect.DeepEqual(got, []string{"is too common"}) {
+			t.Errorf("Validate(%q) = %q, want it rejected as too common", password, got)
+		}
+	}
+	for _, password := range []string{"# Company specific passwords", "hunter23"} {
+		if err := policy.Validate("password", password); err != nil {
+			t.Errorf("Validate(%q) error = %v, want nil", password, err)
+		}
+	}
+
+	t.Run("ignored when common passwords are allowed", func(t *testing.T) {
+		policy := newTestPolicy(t, config.PasswordConfig{MinLength: 1, BlocklistFile: path})
+
+		if err := policy.Validate("password", "hunter22"); err != nil {
+			t.Errorf("Validate() error = %v, want nil", err)
+		}
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		cfg := &config.Config{Password: config.PasswordConfig{
+			RejectCommon:  true,
+			BlocklistFile: filepath.Join(t.TempDir(), "missing.txt"),
+		}}
+		if _, err := NewPolicy(cfg); err == nil {
+			t.Error("NewPolicy() error = nil, want an error")
+		}
+	})
+}
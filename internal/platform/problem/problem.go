@@ -0,0 +1,130 @@
+package problem
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// ContentType is the media type of RFC 7807 problem details
+const ContentType = "application/problem+json"
+
+/*
+Problem is an RFC 7807 problem details object describing an error response.
+Type, Title and Status are always set when the problem is rendered; Code is a
+stable, machine-readable identifier of the problem, and Fields lists the
+invalid request fields for validation problems. Extensions are written as
+additional top-level members (RFC 7807 section 3.2); they never replace the
+standard members above.
+
+Problem implements error so handlers and middleware can return it and let the
+Renderer write the response. The cause, if any, is only logged, never sent.
+*/
+type Problem struct {
+	Type      string            `json:"type"`
+	Title     string            `json:"title"`
+	Status    int               `json:"status"`
+	Detail    string            `json:"detail,omitempty"`
+	Instance  string            `json:"instance,omitempty"`
+	Code      string            `json:"code,omitempty"`
+	RequestID string            `json:"request_id,omitempty"`
+	Fields    map[string]string `json:"fields,omitempty"`
+
+	Extensions map[string]interface{} `json:"-"`
+
+	cause error
+}
+
+/*
+New creates a problem with the given HTTP status, code and client-facing detail.
+The title is the standard text for the status.
+Example: New(http.StatusNotFound, "not_found", "User not found")
+*/
+func New(status int, code, detail string) *Problem {
+	return &Problem{
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: detail,
+		Code:   code,
+	}
+}
+
+// Error implements the error interface
+func (p *Problem) Error() string {
+	if p.cause != nil {
+		return fmt.Sprintf("%s: %v", p.Detail, p.cause)
+	}
+	return p.Detail
+}
+
+/*
+Unwrap returns the error that caused the problem.
+This allows errors.Is() and errors.As() to see through the problem to its cause.
+*/
+func (p *Problem) Unwrap() error {
+	return p.cause
+}
+
+/*
+WithCause records the error that caused the problem for logging.
+Returns the Problem for method chaining.
+*/
+func (p *Problem) WithCause(err error) *Problem {
+	p.cause = err
+	return p
+}
+
+/*
+WithExtensions adds extension members to the problem.
+Returns the Problem for method chaining.
+*/
+func (p *Problem) WithExtensions(extensions map[string]interface{}) *Problem {
+	if len(extensions) == 0 {
+		return p
+	}
+	if p.Extensions == nil {
+		p.Extensions = make(map[string]interface{}, len(extensions))
+	}
+	for k, v := range extensions {
+		p.Extensions[k] = v
+	}
+	return p
+}
+
+// MarshalJSON writes the standard members and the extension members as one object
+func (p Problem) MarshalJSON() ([]byte, error) {
+	type members Problem
+	standard, err := json.Marshal(members(p))
+	if err != nil || len(p.Extensions) == 0 {
+		return standard, err
+	}
+
+	var merged map[string]interface{}
+	if err := json.Unmarshal(standard, &merged); err != nil {
+		return nil, err
+	}
+	for k, v := range p.Extensions {
+		if !isStandardMember(k) {
+			merged[k] = v
+		}
+	}
+	return json.Marshal(merged)
+}
+
+// isStandardMember reports whether name is a member of Problem, which extensions may not use
+func isStandardMember(name string) bool {
+	switch name {
+	case "type", "title", "status", "detail", "instance", "code", "request_id", "fields":
+		return true
+	}
+	return false
+}
+
+/*
+WithFields sets the per-field messages of a validation problem.
+Returns the Problem for method chaining.
+*/
+func (p *Problem) WithFields(fields map[string]string) *Problem {
+	p.Fields = fields
+	return p
+}
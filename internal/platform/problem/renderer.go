@@ -0,0 +1,145 @@
+package problem
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	apperrors "github.com/dzikrisyairozi/go-ddd-clean-starter/internal/platform/errors"
+	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/platform/logger"
+	"github.com/gofiber/fiber/v2"
+)
+
+/*
+Renderer writes every error response of the API as application/problem+json.
+It is installed as the Fiber ErrorHandler, so handlers and middleware only need
+to return an error:
+  - *Problem is written as is
+  - *errors.ValidationErrors becomes 422 Unprocessable Entity with the invalid fields
+  - Errors found in the error Registry, by AppError code or by a wrapped
+    sentinel error, use the registered status, code and message; the Fields of
+    an AppError in the chain become extension members
+  - *fiber.Error (unknown route, body too large, ...) keeps its status
+  - Anything else, including an AppError with an unregistered code, becomes
+    500 Internal Server Error without exposing the error text
+
+Server errors are logged with their cause; client errors only at debug level,
+since the request logger already reports their status.
+*/
+type Renderer struct {
+	typeBaseURI string
+	registry    *apperrors.Registry
+	logger      *logger.Logger
+}
+
+/*
+NewRenderer creates a Renderer that reports errors registered in the registry.
+typeBaseURI is prefixed to a problem's code to form its type URI
+(e.g. "/problems/" gives "/problems/not_found"); if empty, every type is about:blank.
+*/
+func NewRenderer(typeBaseURI string, registry *apperrors.Registry, log *logger.Logger) *Renderer {
+	return &Renderer{
+		typeBaseURI: typeBaseURI,
+		registry:    registry,
+		logger:      log,
+	}
+}
+
+/*
+Render writes err as a problem details response.
+Its signature matches fiber.ErrorHandler.
+*/
+func (r *Renderer) Render(c *fiber.Ctx, err error) error {
+	p := *r.From(err)
+
+	if p.Type == "" {
+		p.Type = r.typeURI(p.Code)
+	}
+	if p.Title == "" {
+		p.Title = http.StatusText(p.Status)
+	}
+	p.Instance = c.Path()
+	p.RequestID, _ = c.Locals("requestID").(string)
+
+	if p.Status >= http.StatusInternalServerError {
+		r.logger.Error("Request failed", "request_id", p.RequestID, "status", p.Status, "path", p.Instance, "error", err.Error())
+	} else {
+		r.logger.Debug("Request failed", "request_id", p.RequestID, "status", p.Status, "path", p.Instance, "error", err.Error())
+	}
+
+	return c.Status(p.Status).JSON(p, ContentType)
+}
+
+// typeURI returns the type URI for a problem code
+func (r *Renderer) typeURI(code string) string {
+	if r.typeBaseURI == "" || code == "" {
+		return "about:blank"
+	}
+	return r.typeBaseURI + code
+}
+
+/*
+From converts an error into the problem it should be reported as.
+It never returns nil; errors it does not recognise become an internal error.
+*/
+func (r *Renderer) From(err error) *Problem {
+	var p *Problem
+	if errors.As(err, &p) {
+		return p
+	}
+
+	var validationErrs *apperrors.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		return New(http.StatusUnprocessableEntity, "validation_error", "Request validation failed").
+			WithFields(validationFields(validationErrs)).
+			WithCause(err)
+	}
+
+	if def, ok := r.registry.Lookup(err); ok {
+		return New(def.Status, def.Code, def.Message).
+			WithExtensions(appErrorFields(err)).
+			WithCause(err)
+	}
+
+	var fiberErr *fiber.Error
+	if errors.As(err, &fiberErr) && fiberErr.Code < http.StatusInternalServerError {
+		return New(fiberErr.Code, statusCode(fiberErr.Code), fiberErr.Message).WithCause(err)
+	}
+
+	return Internal(err)
+}
+
+/*
+Internal creates the 500 problem reported for unexpected errors.
+The cause is only logged; clients get a generic message.
+*/
+func Internal(cause error) *Problem {
+	return New(http.StatusInternalServerError, "internal_error", "An internal error occurred").WithCause(cause)
+}
+
+// statusCode derives a problem code from an HTTP status (e.g. 404 gives "not_found")
+func statusCode(status int) string {
+	return strings.ToLower(strings.ReplaceAll(http.StatusText(status), " ", "_"))
+}
+
+// appErrorFields returns the Fields of the first AppError in the chain, if any
+func appErrorFields(err error) map[string]interface{} {
+	var appErr *apperrors.AppError
+	if errors.As(err, &appErr) {
+		return appErr.Fields
+	}
+	return nil
+}
+
+// validationFields flattens validation errors into one message per field
+func validationFields(errs *apperrors.ValidationErrors) map[string]string {
+	fields := make(map[string]string, len(errs.Errors))
+	for _, e := range errs.Errors {
+		if existing, ok := fields[e.Field]; ok {
+			fields[e.Field] = existing + "; " + e.Message
+			continue
+		}
+		fields[e.Field] = e.Message
+	}
+	return fields
+}
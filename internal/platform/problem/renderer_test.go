@@ -0,0 +1,187 @@
+package problem
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	apperrors "github.com/dzikrisyairozi/go-ddd-clean-starter/internal/platform/errors"
+	"github.com/dzikrisyairozi/go-ddd-clean-starter/internal/platform/logger"
+	"github.com/gofiber/fiber/v2"
+)
+
+var errWidgetNotFound = errors.New("widget not found")
+
+// newTestRenderer creates a Renderer whose registry knows errWidgetNotFound
+func newTestRenderer() *Renderer {
+	registry := apperrors.NewRegistry()
+	registry.Register(errWidgetNotFound, apperrors.Definition{Code: "widget_not_found", Status: http.StatusNotFound, Message: "Widget not found"})
+
+	return NewRenderer("/problems/", registry, logger.New("error"))
+}
+
+// render serves a request whose handler fails with err and returns the decoded response
+func render(t *testing.T, err error) (*http.Response, map[string]interface{}) {
+	t.Helper()
+
+	app := fiber.New(fiber.Config{ErrorHandler: newTestRenderer().Render})
+	app.Get("/widgets/1", func(c *fiber.Ctx) error {
+		c.Locals("requestID", "req-1")
+		return err
+	})
+
+	resp, testErr := app.Test(httptest.NewRequest(http.MethodGet, "/widgets/1", nil))
+	if testErr != nil {
+		t.Fatalf("app.Test() error = %v", testErr)
+	}
+	defer resp.Body.Close()
+
+	var body map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	return resp, body
+}
+
+// assertProblem checks the status, content type and the given members of a problem response
+func assertProblem(t *testing.T, resp *http.Response, body map[string]interface{}, status int, want map[string]interface{}) {
+	t.Helper()
+
+	if resp.StatusCode != status {
+		t.Errorf("status = %d, want %d", resp.StatusCode, status)
+	}
+	if got := resp.Header.Get("Content-Type"); got != ContentType {
+		t.Errorf("Content-Type = %q, want %q", got, ContentType)
+	}
+	if got := body["status"]; got != float64(status) {
+		t.Errorf("status member = %v, want %d", got, status)
+	}
+	for key, value := range want {
+		if !reflect.DeepEqual(body[key], value) {
+			t.Errorf("%s = %#v, want %#v", key, body[key], value)
+		}
+	}
+}
+
+func TestRenderRegisteredError(t *testing.T) {
+	resp, body := render(t, fmt.Errorf("failed to load widget: %w", errWidgetNotFound))
+
+	assertProblem(t, resp, body, http.StatusNotFound, map[string]interface{}{
+		"type":       "/problems/widget_not_found",
+		"title":      "Not Found",
+		"code":       "widget_not_found",
+		"detail":     "Widget not found",
+		"instance":   "/widgets/1",
+		"request_id": "req-1",
+	})
+}
+
+func TestRenderAppErrorCode(t *testing.T) {
+	err := apperrors.NewAppError(errors.New("no such widget"), "lookup failed").
+		WithCode("widget_not_found").
+		WithField("widget_id", "w-1")
+
+	resp, body := render(t, err)
+
+	assertProblem(t, resp, body, http.StatusNotFound, map[string]interface{}{
+		"code":      "widget_not_found",
+		"detail":    "Widget not found",
+		"widget_id": "w-1",
+	})
+}
+
+func TestRenderAppErrorFieldsAsExtensions(t *testing.T) {
+	// Fields of an AppError wrapping a registered sentinel are extension members,
+	// but cannot replace the standard members
+	err := fmt.Errorf("handler: %w", apperrors.NewAppError(errWidgetNotFound, "lookup failed").WithFields(map[string]interface{}{
+		"widget_id": "w-1",
+		"attempts":  3,
+		"status":    200,
+		"detail":    "overridden",
+	}))
+
+	resp, body := render(t, err)
+
+	assertProblem(t, resp, body, http.StatusNotFound, map[string]interface{}{
+		"code":      "widget_not_found",
+		"detail":    "Widget not found",
+		"widget_id": "w-1",
+		"attempts":  float64(3),
+	})
+}
+
+func TestRenderValidationErrors(t *testing.T) {
+	errs := apperrors.NewValidationErrors()
+	errs.Add("email", "is required")
+	errs.Add("password", "must be at least 8 characters")
+	errs.Add("password", "must contain a digit")
+
+	resp, body := render(t, errs)
+
+	assertProblem(t, resp, body, http.StatusUnprocessableEntity, map[string]interface{}{
+		"type": "/problems/validation_error",
+		"code": "validation_error",
+		"fields": map[string]interface{}{
+			"email":    "is required",
+			"password": "must be at least 8 characters; must contain a digit",
+		},
+	})
+}
+
+func TestRenderProblem(t *testing.T) {
+	resp, body := render(t, New(http.StatusConflict, "widget_locked", "Widget is locked").WithCause(errors.New("row lock")))
+
+	assertProblem(t, resp, body, http.StatusConflict, map[string]interface{}{
+		"code":   "widget_locked",
+		"detail": "Widget is locked",
+	})
+}
+
+func TestRenderFiberError(t *testing.T) {
+	resp, body := render(t, fiber.NewError(http.StatusRequestEntityTooLarge, "Request Entity Too Large"))
+
+	assertProblem(t, resp, body, http.StatusRequestEntityTooLarge, map[string]interface{}{
+		"type": "/problems/request_entity_too_large",
+		"code": "request_entity_too_large",
+	})
+}
+
+func TestRenderUnknownError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{"plain error", errors.New("pq: password authentication failed for user admin")},
+		{"app error with unregistered code", apperrors.NewAppError(errors.New("pq: secret detail"), "query failed").WithCode("db_exploded")},
+		{"fiber server error", fiber.NewError(http.StatusServiceUnavailable, "upstream secret detail")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, body := render(t, tt.err)
+
+			assertProblem(t, resp, body, http.StatusInternalServerError, map[string]interface{}{
+				"code":   "internal_error",
+				"detail": "An internal error occurred",
+			})
+
+			raw, _ := json.Marshal(body)
+			if strings.Contains(string(raw), "secret") || strings.Contains(string(raw), "pq:") {
+				t.Errorf("response leaks the error: %s", raw)
+			}
+		})
+	}
+}
+
+func TestRenderWithoutTypeBaseURI(t *testing.T) {
+	renderer := NewRenderer("", apperrors.NewRegistry(), logger.New("error"))
+
+	if got := renderer.typeURI("not_found"); got != "about:blank" {
+		t.Errorf("typeURI() = %q, want about:blank", got)
+	}
+}
@@ -0,0 +1,96 @@
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+// sweepThreshold is the number of tracked keys above which expired entries are purged
+const sweepThreshold = 10000
+
+// failureWindow tracks the failures of one key within the current window
+type failureWindow struct {
+	count   int
+	resetAt time.Time
+}
+
+/*
+FailureLimiter counts failures per key (for example a client IP address) in a
+fixed time window and blocks the key once it reaches the maximum.
+State is kept in memory, so each application instance limits independently.
+It is safe for concurrent use.
+*/
+type FailureLimiter struct {
+	mu          sync.Mutex
+	maxFailures int
+	window      time.Duration
+	entries     map[string]*failureWindow
+}
+
+/*
+NewFailureLimiter creates a FailureLimiter that blocks a key after maxFailures
+failures within window. A maxFailures of zero disables blocking.
+*/
+func NewFailureLimiter(maxFailures int, window time.Duration) *FailureLimiter {
+	return &FailureLimiter{
+		maxFailures: maxFailures,
+		window:      window,
+		entries:     make(map[string]*failureWindow),
+	}
+}
+
+/*
+Allow reports whether the key may make another attempt at the given instant.
+If not, it also returns how long the key has to wait.
+*/
+func (l *FailureLimiter) Allow(key string, now time.Time) (time.Duration, bool) {
+	if l.maxFailures <= 0 {
+		return 0, true
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	entry, ok := l.entries[key]
+	if !ok || !now.Before(entry.resetAt) {
+		return 0, true
+	}
+	if entry.count < l.maxFailures {
+		return 0, true
+	}
+
+	return entry.resetAt.Sub(now), false
+}
+
+/*
+RecordFailure counts a failed attempt for the key.
+The first failure of a key starts its window.
+*/
+func (l *FailureLimiter) RecordFailure(key string, now time.Time) {
+	if l.maxFailures <= 0 {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	entry, ok := l.entries[key]
+	if !ok || !now.Before(entry.resetAt) {
+		if len(l.entries) >= sweepThreshold {
+			l.sweep(now)
+		}
+		entry = &failureWindow{resetAt: now.Add(l.window)}
+		l.entries[key] = entry
+	}
+
+	entry.count++
+}
+
+// sweep removes entries whose window has ended; the caller must hold the lock
+func (l *FailureLimiter) sweep(now time.Time) {
+	for key, entry := range l.entries {
+		if !now.Before(entry.resetAt) {
+			delete(l.entries, key)
+		}
+	}
+}
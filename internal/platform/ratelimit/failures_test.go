@@ -0,0 +1,120 @@
+package ratelimit
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestFailureLimiter(t *testing.T) {
+	now := time.Now()
+	limiter := NewFailureLimiter(3, time.Minute)
+
+	for i := 0; i < 3; i++ {
+		if _, ok := limiter.Allow("203.0.113.7", now); !ok {
+			t.Fatalf("Allow() after %d failures = false, want true", i)
+		}
+		limiter.RecordFailure("203.0.113.7", now.Add(time.Duration(i)*time.Second))
+	}
+
+	retryAfter, ok := limiter.Allow("203.0.113.7", now.Add(10*time.Second))
+	if ok {
+		t.Fatal("Allow() after 3 failures = true, want false")
+	}
+	// The window started with the first failure
+	if retryAfter != 50*time.Second {
+		t.Errorf("Allow() retryAfter = %s, want 50s", retryAfter)
+	}
+
+	if _, ok := limiter.Allow("198.51.100.1", now); !ok {
+		t.Error("Allow() for another key = false, want true")
+	}
+}
+
+func TestFailureLimiterWindowExpiry(t *testing.T) {
+	now := time.Now()
+	limiter := NewFailureLimiter(2, time.Minute)
+
+	limiter.RecordFailure("key", now)
+	limiter.RecordFailure("key", now)
+
+	tests := []struct {
+		name string
+		at   time.Time
+		want bool
+	}{
+		{name: "within the window", at: now.Add(59 * time.Second), want: false},
+		{name: "at the end of the window", at: now.Add(time.Minute), want: true},
+		{name: "after the window", at: now.Add(2 * time.Minute), want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, got := limiter.Allow("key", tt.at); got != tt.want {
+				t.Errorf("Allow() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFailureLimiterResetsAfterWindow(t *testing.T) {
+	now := time.Now()
+	limiter := NewFailureLimiter(2, time.Minute)
+
+	limiter.RecordFailure("key", now)
+	limiter.RecordFailure("key", now)
+
+	// A failure after the window starts a new one with a fresh count
+	later := now.Add(time.Minute)
+	limiter.RecordFailure("key", later)
+
+	if _, ok := limiter.Allow("key", later); !ok {
+		t.Error("Allow() after one failure in a new window = false, want true")
+	}
+	limiter.RecordFailure("key", later)
+	if retryAfter, ok := limiter.Allow("key", later); ok || retryAfter != time.Minute {
+		t.Errorf("Allow() = (%s, %v), want (1m0s, false)", retryAfter, ok)
+	}
+}
+
+func TestFailureLimiterDisabled(t *testing.T) {
+	limiter := NewFailureLimiter(0, time.Minute)
+	now := time.Now()
+
+	for i := 0; i < 100; i++ {
+		limiter.RecordFailure("key", now)
+	}
+
+	if _, ok := limiter.Allow("key", now); !ok {
+		t.Error("Allow() with blocking disabled = false, want true")
+	}
+	if len(limiter.entries) != 0 {
+		t.Errorf("tracked keys = %d, want 0", len(limiter.entries))
+	}
+}
+
+func TestFailureLimiterSweep(t *testing.T) {
+	now := time.Now()
+	limiter := NewFailureLimiter(1, time.Minute)
+
+	for i := 0; i < sweepThreshold-1; i++ {
+		limiter.RecordFailure(fmt.Sprintf("expired-%d", i), now)
+	}
+	limiter.RecordFailure("active", now.Add(30*time.Second))
+
+	// Below the threshold nothing is swept, even though entries have expired
+	later := now.Add(time.Minute)
+	limiter.RecordFailure("active", later)
+	if len(limiter.entries) != sweepThreshold {
+		t.Fatalf("tracked keys = %d, want %d", len(limiter.entries), sweepThreshold)
+	}
+
+	// A new key at the threshold sweeps the expired entries
+	limiter.RecordFailure("new", later)
+	if len(limiter.entries) != 2 {
+		t.Errorf("tracked keys after sweep = %d, want 2", len(limiter.entries))
+	}
+	if _, ok := limiter.Allow("active", later); ok {
+		t.Error("Allow() for an unexpired key after sweep = true, want false")
+	}
+}
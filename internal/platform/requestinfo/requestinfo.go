@@ -0,0 +1,33 @@
+package requestinfo
+
+import "context"
+
+/*
+Info describes the HTTP request a piece of work is done for.
+It is placed into the request context by the request logger middleware, so code
+far from the handler (such as the audit log) can record where a change came from.
+*/
+type Info struct {
+	RequestID string
+	IP        string
+}
+
+// infoKey is the unexported context key for the request info
+type infoKey struct{}
+
+/*
+WithInfo returns a copy of ctx that carries the given request info.
+*/
+func WithInfo(ctx context.Context, info Info) context.Context {
+	return context.WithValue(ctx, infoKey{}, info)
+}
+
+/*
+FromContext returns the request info stored in ctx.
+The boolean is false if ctx does not belong to an HTTP request, such as in a
+background job.
+*/
+func FromContext(ctx context.Context) (Info, bool) {
+	info, ok := ctx.Value(infoKey{}).(Info)
+	return info, ok
+}
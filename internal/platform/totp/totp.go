@@ -0,0 +1,125 @@
+/*
+Package totp implements RFC 6238 time-based one-time passwords with the parameters
+supported by common authenticator apps: HMAC-SHA1, 6 digits and a 30 second period.
+*/
+package totp
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// Digits is the number of digits in a generated code
+	Digits = 6
+
+	// Period is the lifetime of a single code
+	Period = 30 * time.Second
+
+	// Skew is the number of periods before and after the current one that are accepted,
+	// to tolerate clock drift between the server and the authenticator app
+	Skew = 1
+
+	// secretSize is the size of generated secrets in bytes (160 bits, as recommended by RFC 4226)
+	secretSize = 20
+)
+
+// encoding is the unpadded base32 encoding used for secrets in otpauth:// URIs
+var encoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+/*
+GenerateSecret returns a new random secret, base32 encoded without padding.
+*/
+func GenerateSecret() (string, error) {
+	secret := make([]byte, secretSize)
+	if _, err := rand.Read(secret); err != nil {
+		return "", fmt.Errorf("failed to generate totp secret: %w", err)
+	}
+	return encoding.EncodeToString(secret), nil
+}
+
+/*
+URI returns the otpauth:// key URI for the secret, which authenticator apps accept
+directly or as a QR code.
+Example: otpauth://totp/Issuer:user@example.com?secret=...&issuer=Issuer&algorithm=SHA1&digits=6&period=30
+*/
+func URI(issuer, account, secret string) string {
+	params := url.Values{}
+	params.Set("secret", secret)
+	params.Set("issuer", issuer)
+	params.Set("algorithm", "SHA1")
+	params.Set("digits", fmt.Sprint(Digits))
+	params.Set("period", fmt.Sprint(int(Period.Seconds())))
+
+	u := url.URL{
+		Scheme:   "otpauth",
+		Host:     "totp",
+		Path:     "/" + issuer + ":" + account,
+		RawQuery: params.Encode(),
+	}
+	return u.String()
+}
+
+/*
+Step returns the time step (counter) that t falls into.
+*/
+func Step(t time.Time) int64 {
+	return t.Unix() / int64(Period.Seconds())
+}
+
+/*
+Code returns the code for the given secret and time step.
+Returns an error if the secret is not valid base32.
+*/
+func Code(secret string, step int64) (string, error) {
+	key, err := encoding.DecodeString(strings.ToUpper(secret))
+	if err != nil {
+		return "", fmt.Errorf("invalid totp secret: %w", err)
+	}
+
+	var counter [8]byte
+	binary.BigEndian.PutUint64(counter[:], uint64(step))
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(counter[:])
+	sum := mac.Sum(nil)
+
+	// Dynamic truncation (RFC 4226 section 5.3)
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	return fmt.Sprintf("%0*d", Digits, value%1_000_000), nil
+}
+
+/*
+Validate checks a code against the secret at time now, accepting the current
+step and Skew steps on either side.
+Returns the matching step so callers can reject a code that was already used,
+and false if the code does not match.
+*/
+func Validate(secret, code string, now time.Time) (int64, bool) {
+	if len(code) != Digits {
+		return 0, false
+	}
+
+	current := Step(now)
+	for step := current - Skew; step <= current+Skew; step++ {
+		expected, err := Code(secret, step)
+		if err != nil {
+			return 0, false
+		}
+		if subtle.ConstantTimeCompare([]byte(expected), []byte(code)) == 1 {
+			return step, true
+		}
+	}
+
+	return 0, false
+}
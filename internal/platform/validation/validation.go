@@ -0,0 +1,124 @@
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	apperrors "github.com/dzikrisyairozi/go-ddd-clean-starter/internal/platform/errors"
+	"github.com/go-playground/validator/v10"
+)
+
+/*
+validate is the shared validator instance.
+It is safe for concurrent use and caches struct metadata, so one instance
+serves every request.
+*/
+var validate = newValidator()
+
+/*
+newValidator creates a validator that reports fields by the name clients send
+them under: the json tag for request bodies, or the query tag for query parameters.
+*/
+func newValidator() *validator.Validate {
+	v := validator.New(validator.WithRequiredStructEnabled())
+
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		for _, tag := range []string{"json", "query"} {
+			name := strings.SplitN(field.Tag.Get(tag), ",", 2)[0]
+			if name == "-" {
+				return ""
+			}
+			if name != "" {
+				return name
+			}
+		}
+		return field.Name
+	})
+
+	return v
+}
+
+/*
+Struct enforces the validate tags on a request model.
+Call it after BodyParser or QueryParser has filled in the struct.
+Returns nil if every field is valid, or *errors.ValidationErrors listing
+every failing field with a client-facing message.
+*/
+func Struct(s interface{}) error {
+	err := validate.Struct(s)
+	if err == nil {
+		return nil
+	}
+
+	var fieldErrs validator.ValidationErrors
+	if !errors.As(err, &fieldErrs) {
+		// Only returned for programming errors, such as passing a non-struct
+		return fmt.Errorf("failed to validate request: %w", err)
+	}
+
+	errs := apperrors.NewValidationErrors()
+	for _, fe := range fieldErrs {
+		errs.Add(fieldName(fe), message(fe))
+	}
+	return errs
+}
+
+/*
+fieldName returns the path of the failing field as the client sent it,
+without the top-level struct name (e.g. "scopes[0]" rather than "CreateAPIKeyRequest.scopes[0]").
+*/
+func fieldName(fe validator.FieldError) string {
+	namespace := fe.Namespace()
+	if i := strings.Index(namespace, "."); i >= 0 {
+		return namespace[i+1:]
+	}
+	return fe.Field()
+}
+
+// message describes a failed validation rule in client-facing terms
+func message(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	case "email":
+		return "must be a valid email address"
+	case "numeric":
+		return "must contain only digits"
+	case "uuid":
+		return "must be a valid UUID"
+	case "datetime":
+		return "must be an RFC 3339 date-time (e.g. 2006-01-02T15:04:05Z)"
+	case "oneof":
+		return fmt.Sprintf("must be one of: %s", strings.ReplaceAll(fe.Param(), " ", ", "))
+	case "min":
+		return boundMessage(fe, "at least")
+	case "max":
+		return boundMessage(fe, "at most")
+	case "len":
+		return boundMessage(fe, "exactly")
+	default:
+		return "is invalid"
+	}
+}
+
+// boundMessage describes a min, max or len rule according to the field's kind
+func boundMessage(fe validator.FieldError, bound string) string {
+	switch fe.Kind() {
+	case reflect.String:
+		return fmt.Sprintf("must be %s %s %s", bound, fe.Param(), plural(fe.Param(), "character"))
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return fmt.Sprintf("must contain %s %s %s", bound, fe.Param(), plural(fe.Param(), "item"))
+	default:
+		return fmt.Sprintf("must be %s %s", bound, fe.Param())
+	}
+}
+
+// plural returns noun in the plural unless count is "1"
+func plural(count, noun string) string {
+	if count == "1" {
+		return noun
+	}
+	return noun + "s"
+}
@@ -0,0 +1,134 @@
+package validation
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	apperrors "github.com/dzikrisyairozi/go-ddd-clean-starter/internal/platform/errors"
+)
+
+type testAddress struct {
+	City string `json:"city" validate:"required"`
+}
+
+// testForm exercises every rule with a dedicated message
+type testForm struct {
+	Email    string      `json:"email,omitempty" validate:"required,email"`
+	Name     string      `json:"name" validate:"required,min=2,max=5"`
+	Initial  string      `json:"initial" validate:"omitempty,len=1"`
+	Role     string      `json:"role" validate:"omitempty,oneof=admin support member"`
+	Code     string      `json:"code" validate:"omitempty,numeric,len=6"`
+	UserID   string      `json:"user_id" validate:"omitempty,uuid"`
+	From     string      `json:"from" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+	Tags     []string    `json:"tags" validate:"omitempty,max=2,dive,oneof=a b"`
+	Scopes   []string    `json:"scopes" validate:"required,min=1"`
+	Limit    int         `query:"limit" validate:"min=1,max=100"`
+	Address  testAddress `json:"address"`
+	Internal string      `validate:"omitempty,alpha"`
+}
+
+// validForm returns a form that passes validation
+func validForm() testForm {
+	return testForm{
+		Email:   "alice@example.com",
+		Name:    "Alice",
+		Scopes:  []string{"users:read"},
+		Limit:   10,
+		Address: testAddress{City: "Jakarta"},
+	}
+}
+
+// validationErrors asserts that err is *ValidationErrors and returns its messages by field
+func validationErrors(t *testing.T, err error) map[string]string {
+	t.Helper()
+
+	var errs *apperrors.ValidationErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("Struct() error = %v, want *ValidationErrors", err)
+	}
+
+	fields := make(map[string]string, len(errs.Errors))
+	for _, fe := range errs.Errors {
+		fields[fe.Field] = fe.Message
+	}
+	return fields
+}
+
+func TestStructValid(t *testing.T) {
+	form := validForm()
+	if err := Struct(&form); err != nil {
+		t.Errorf("Struct() = %v, want nil", err)
+	}
+}
+
+func TestStructMessages(t *testing.T) {
+	tests := []struct {
+		name        string
+		change      func(f *testForm)
+		wantField   string
+		wantMessage string
+	}{
+		{name: "required", change: func(f *testForm) { f.Email = "" }, wantField: "email", wantMessage: "is required"},
+		{name: "email", change: func(f *testForm) { f.Email = "alice" }, wantField: "email", wantMessage: "must be a valid email address"},
+		{name: "min string", change: func(f *testForm) { f.Name = "A" }, wantField: "name", wantMessage: "must be at least 2 characters"},
+		{name: "single character", change: func(f *testForm) { f.Initial = "AB" }, wantField: "initial", wantMessage: "must be exactly 1 character"},
+		{name: "max string", change: func(f *testForm) { f.Name = "Alice Cooper" }, wantField: "name", wantMessage: "must be at most 5 characters"},
+		{name: "oneof", change: func(f *testForm) { f.Role = "owner" }, wantField: "role", wantMessage: "must be one of: admin, support, member"},
+		{name: "numeric", change: func(f *testForm) { f.Code = "12a456" }, wantField: "code", wantMessage: "must contain only digits"},
+		{name: "len string", change: func(f *testForm) { f.Code = "12345" }, wantField: "code", wantMessage: "must be exactly 6 characters"},
+		{name: "uuid", change: func(f *testForm) { f.UserID = "42" }, wantField: "user_id", wantMessage: "must be a valid UUID"},
+		{
+			name:        "datetime",
+			change:      func(f *testForm) { f.From = "yesterday" },
+			wantField:   "from",
+			wantMessage: "must be an RFC 3339 date-time (e.g. 2006-01-02T15:04:05Z)",
+		},
+		{name: "min items", change: func(f *testForm) { f.Scopes = []string{} }, wantField: "scopes", wantMessage: "must contain at least 1 item"},
+		{name: "max items", change: func(f *testForm) { f.Tags = []string{"a", "b", "a"} }, wantField: "tags", wantMessage: "must contain at most 2 items"},
+		{name: "slice element", change: func(f *testForm) { f.Tags = []string{"a", "c"} }, wantField: "tags[1]", wantMessage: "must be one of: a, b"},
+		{name: "min number", change: func(f *testForm) { f.Limit = 0 }, wantField: "limit", wantMessage: "must be at least 1"},
+		{name: "max number", change: func(f *testForm) { f.Limit = 101 }, wantField: "limit", wantMessage: "must be at most 100"},
+		{name: "nested field", change: func(f *testForm) { f.Address.City = "" }, wantField: "address.city", wantMessage: "is required"},
+		{name: "untagged field", change: func(f *testForm) { f.Internal = "42" }, wantField: "Internal", wantMessage: "is invalid"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			form := validForm()
+			tt.change(&form)
+
+			fields := validationErrors(t, Struct(&form))
+			want := map[string]string{tt.wantField: tt.wantMessage}
+			if !reflect.DeepEqual(fields, want) {
+				t.Errorf("Struct() errors = %v, want %v", fields, want)
+			}
+		})
+	}
+}
+
+func TestStructReportsEveryField(t *testing.T) {
+	form := validForm()
+	form.Email = ""
+	form.Name = ""
+	form.Limit = 0
+
+	fields := validationErrors(t, Struct(&form))
+	want := map[string]string{
+		"email": "is required",
+		"name":  "is required",
+		"limit": "must be at least 1",
+	}
+	if !reflect.DeepEqual(fields, want) {
+		t.Errorf("Struct() errors = %v, want %v", fields, want)
+	}
+}
+
+func TestStructRejectsNonStruct(t *testing.T) {
+	err := Struct("not a struct")
+
+	var errs *apperrors.ValidationErrors
+	if err == nil || errors.As(err, &errs) {
+		t.Errorf("Struct() error = %v, want a plain error", err)
+	}
+}
@@ -1,13 +1,16 @@
 #!/bin/bash
 
 # Database Migration Helper Script
-# This script helps run database migrations using psql
+# Creates and drops the database with psql; migrations are run by the
+# migration runner embedded in the api binary (go run ./cmd/api migrate)
 
 set -e
 
 # Load environment variables from .env if it exists
 if [ -f .env ]; then
-    export $(cat .env | grep -v '^#' | xargs)
+    set -a
+    . ./.env
+    set +a
 fi
 
 # Default values
@@ -17,8 +20,6 @@ DB_USER=${DB_USER:-postgres}
 DB_PASSWORD=${DB_PASSWORD:-postgres}
 DB_NAME=${DB_NAME:-go_ddd_starter}
 
-MIGRATIONS_DIR="infrastructure/database/migrations"
-
 # Colors for output
 GREEN='\033[0;32m'
 YELLOW='\033[1;33m'
@@ -38,30 +39,14 @@ print_error() {
     echo -e "${RED}[ERROR]${NC} $1"
 }
 
-# Function to run migration
-run_migration() {
-    local file=$1
-    print_info "Running migration: $file"
-    
-    PGPASSWORD=$DB_PASSWORD psql -h $DB_HOST -p $DB_PORT -U $DB_USER -d $DB_NAME -f "$file"
-    
-    if [ $? -eq 0 ]; then
-        print_info "✓ Migration successful: $file"
-    else
-        print_error "✗ Migration failed: $file"
-        exit 1
-    fi
-}
-
 # Function to migrate up
 migrate_up() {
     print_info "Running UP migrations..."
     
-    for file in $MIGRATIONS_DIR/*.up.sql; do
-        if [ -f "$file" ]; then
-            run_migration "$file"
-        fi
-    done
+    if ! go run ./cmd/api migrate up; then
+        print_error "✗ Migration failed"
+        exit 1
+    fi
     
     print_info "All migrations completed successfully!"
 }
@@ -70,12 +55,11 @@ migrate_up() {
 migrate_down() {
     print_warn "Running DOWN migrations (this will rollback changes)..."
     
-    # Run down migrations in reverse order
-    for file in $(ls -r $MIGRATIONS_DIR/*.down.sql); do
-        if [ -f "$file" ]; then
-            run_migration "$file"
-        fi
-    done
+    # Revert every applied migration
+    if ! go run ./cmd/api migrate goto 0; then
+        print_error "✗ Rollback failed"
+        exit 1
+    fi
     
     print_info "All rollbacks completed successfully!"
 }